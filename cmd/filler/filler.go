@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/catalogfi/orderbook/filler"
+	"github.com/catalogfi/orderbook/internal/path"
+	"github.com/catalogfi/orderbook/rest"
+	"github.com/catalogfi/orderbook/watcher"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type Config struct {
+	FILLER_KEY string        `binding:"required"`
+	FILLER_DB  string        `binding:"required"`
+	FILLER     filler.Config `binding:"required"`
+}
+
+func LoadConfiguration(file string) Config {
+	var config Config
+	configFile, err := os.Open(file)
+	if err != nil {
+		panic(err)
+	}
+	defer configFile.Close()
+	jsonParser := json.NewDecoder(configFile)
+	if err := jsonParser.Decode(&config); err != nil {
+		panic(err)
+	}
+	return config
+}
+
+func main() {
+	envConfig := LoadConfiguration(path.ConfigPath)
+	store, err := filler.NewStore(sqlite.Open(envConfig.FILLER_DB), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		Logger:  logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		panic(err)
+	}
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		panic(err)
+	}
+
+	privKey := strings.TrimPrefix(envConfig.FILLER_KEY, "0x")
+	key, err := crypto.HexToECDSA(privKey)
+	if err != nil {
+		panic(err)
+	}
+	client := rest.NewClient(envConfig.FILLER.URL, privKey)
+
+	swaps, err := filler.LoadSwaps(key, envConfig.FILLER.Network, logger)
+	if err != nil {
+		panic(err)
+	}
+	balances, err := watcher.LoadBalanceReaders(envConfig.FILLER.Network, logger)
+	if err != nil {
+		panic(err)
+	}
+	newWS := func() rest.WSClient {
+		return rest.NewWSClient(envConfig.FILLER.WSURL, logger)
+	}
+	bot, err := filler.New(envConfig.FILLER, key, client, newWS, swaps, balances, store, logger)
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	bot.Run(ctx)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"time"
 
-	"github.com/catalogfi/orderbook/internal/path"
+	"github.com/catalogfi/orderbook/feehub"
 	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/price"
 	"github.com/catalogfi/orderbook/rest"
 	"github.com/catalogfi/orderbook/screener"
+	"github.com/catalogfi/orderbook/stats"
 	"github.com/catalogfi/orderbook/store"
 	"github.com/catalogfi/orderbook/watcher"
 	"go.uber.org/zap"
@@ -17,7 +19,7 @@ import (
 
 func main() {
 	// sqlite db
-	store, err := store.New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{
+	store, err := store.New(sqlite.Open("test.db"), &gorm.Config{
 		NowFunc: func() time.Time { return time.Now().UTC() },
 	})
 	if err != nil {
@@ -50,12 +52,28 @@ func main() {
 		panic(err)
 	}
 
+	drawer := watcher.NewDrawer(store, config, logger)
+	go drawer.Run(context.Background())
+	readers, err := watcher.LoadBalanceReaders(config, logger)
+	if err != nil {
+		panic(err)
+	}
+	stocktaker := watcher.NewStocktaker(store, readers, model.Config{Network: config}, logger)
+	go stocktaker.Run(context.Background())
 	watcher := watcher.NewWatcher(logger, store, 4)
 	go watcher.Run(context.Background())
+	roller := stats.NewRoller(store, model.Config{Network: config}, logger)
+	go roller.Run(context.Background())
+	snapshotter := stats.NewSnapshotter(store, model.Config{Network: config}, logger)
+	go snapshotter.Run(context.Background())
+	scorer := stats.NewScorer(store, model.Config{Network: config}, logger)
+	go scorer.Run(context.Background())
 
 	// Screen is not doing sanction check in this case
 	screener := screener.NewScreener(nil, "")
-	server := rest.NewServer(store, model.Config{Network: config}, logger, "SECRET", nil, screener)
+	// there is no feehub or seed price service locally
+	priceFetcher := price.NewPriceFetcher(price.Options{})
+	server := rest.NewServer(store, model.Config{Network: config}, logger, "SECRET", rest.NewSocketPool(), screener, feehub.NewFeehubClient(""), priceFetcher)
 	if err := server.Run(context.Background(), ":8080"); err != nil {
 		panic(err)
 	}
@@ -45,7 +45,7 @@ func LoadConfiguration(file string) Config {
 func main() {
 	// psql db
 	envConfig := LoadConfiguration(path.ConfigPath)
-	store, err := store.New(postgres.Open(envConfig.PSQL_DB), path.SQLSetupPath, &gorm.Config{
+	store, err := store.New(postgres.Open(envConfig.PSQL_DB), &gorm.Config{
 		NowFunc: func() time.Time { return time.Now().UTC() },
 		Logger:  logger.Default.LogMode(logger.Silent),
 	})
@@ -58,9 +58,10 @@ func main() {
 		panic(err)
 	}
 
-	priceFetcher := price.NewPriceFetcher(price.Options{
+	// the discount path shares the price cache of the store
+	priceFetcher := price.NewCachedPriceFetcher(price.NewPriceFetcher(price.Options{
 		URL: envConfig.PRICE_URL,
-	})
+	}), store.Prices(), "btc-seed", time.Duration(envConfig.CONFIG.PriceTTL)*time.Second)
 
 	screener := screener.NewScreener(store.Gorm(), envConfig.TRM_KEY)
 	socketPool := rest.NewSocketPool()
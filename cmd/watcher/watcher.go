@@ -10,6 +10,7 @@ import (
 	"github.com/catalogfi/orderbook/internal/path"
 	"github.com/catalogfi/orderbook/model"
 	"github.com/catalogfi/orderbook/screener"
+	"github.com/catalogfi/orderbook/stats"
 	"github.com/catalogfi/orderbook/store"
 	"github.com/catalogfi/orderbook/watcher"
 	watchers "github.com/catalogfi/orderbook/watcher"
@@ -47,7 +48,7 @@ func LoadConfiguration(file string) Config {
 
 func main() {
 	envConfig := LoadConfiguration(path.ConfigPath)
-	store, err := store.New(postgres.Open(envConfig.PSQL_DB), path.SQLSetupPath, &gorm.Config{
+	store, err := store.New(postgres.Open(envConfig.PSQL_DB), &gorm.Config{
 		NowFunc: func() time.Time { return time.Now().UTC() },
 		Logger:  logger.Default.LogMode(logger.Silent),
 	})
@@ -77,6 +78,27 @@ func main() {
 	}
 
 	screener := screener.NewScreener(store.Gorm(), envConfig.TRM_KEY)
+	roller := stats.NewRoller(store, envConfig.CONFIG, logger)
+	go roller.Run(context.Background())
+	snapshotter := stats.NewSnapshotter(store, envConfig.CONFIG, logger)
+	go snapshotter.Run(context.Background())
+	scorer := stats.NewScorer(store, envConfig.CONFIG, logger)
+	go scorer.Run(context.Background())
+	drawer := watcher.NewDrawer(store, envConfig.CONFIG.Network, logger)
+	go drawer.Run(context.Background())
+	if envConfig.CONFIG.Bond.Chain != "" {
+		verifier, err := watcher.NewEVMVerifier(store, envConfig.CONFIG, logger)
+		if err != nil {
+			panic(err)
+		}
+		go verifier.Run(context.Background())
+	}
+	readers, err := watcher.LoadBalanceReaders(envConfig.CONFIG.Network, logger)
+	if err != nil {
+		panic(err)
+	}
+	stocktaker := watcher.NewStocktaker(store, readers, envConfig.CONFIG, logger)
+	go stocktaker.Run(context.Background())
 	for chain, Network := range envConfig.CONFIG.Network {
 		if chain.IsBTC() {
 			//interval is set to 10 seconds to detect iw tx's quicky
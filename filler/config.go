@@ -0,0 +1,116 @@
+package filler
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+)
+
+// DefaultPollInterval is the time between polls of the active swaps when it is
+// not configured
+const DefaultPollInterval = 10 * time.Second
+
+// TokenRefresh is how long before its jwt expires the filler logs in again
+const TokenRefresh = time.Hour
+
+// InitiateGrace is how long the filler waits for the orderbook to see its
+// initiation before initiating again after a restart
+const InitiateGrace = 10 * time.Minute
+
+type Config struct {
+	// orderbook api and websocket urls
+	URL   string
+	WSURL string
+	// network of the chains the filler swaps on
+	Network model.Network
+	// the pairs the filler fills and at which prices
+	Strategies []Strategy
+	// amounts the filler keeps of a chain and asset, by <chain> or <chain>:<asset>
+	Reserves map[string]string
+	// whether to publish the available inventories to the orderbook
+	PublishInventory bool
+	// seconds between polls of the active swaps, defaults to ten seconds
+	PollInterval int64
+}
+
+// Strategy is how the filler prices the orders of a pair. The filler fills an
+// order when it sends at most the price of the amount it receives, less the
+// margin.
+type Strategy struct {
+	OrderPair string
+	// units of the asset the filler sends per unit of the asset it receives, in
+	// the smallest units of both assets
+	Price float64
+	// margin the filler keeps in basis points of the price
+	MarginBips int64
+	// bounds of the amount the filler sends, not enforced when empty
+	MinAmount string
+	MaxAmount string
+}
+
+// Accepts reports whether the filler fills an order of the strategy in which it
+// receives and sends the given amounts
+func (strategy Strategy) Accepts(receiveAmount, sendAmount *big.Int) (bool, error) {
+	if strategy.MinAmount != "" {
+		minAmount, ok := new(big.Int).SetString(strategy.MinAmount, 10)
+		if !ok {
+			return false, fmt.Errorf("invalid min amount %s", strategy.MinAmount)
+		}
+		if sendAmount.Cmp(minAmount) < 0 {
+			return false, nil
+		}
+	}
+	if strategy.MaxAmount != "" {
+		maxAmount, ok := new(big.Int).SetString(strategy.MaxAmount, 10)
+		if !ok {
+			return false, fmt.Errorf("invalid max amount %s", strategy.MaxAmount)
+		}
+		if sendAmount.Cmp(maxAmount) > 0 {
+			return false, nil
+		}
+	}
+	if strategy.Price <= 0 || strategy.MarginBips < 0 || strategy.MarginBips >= 10000 {
+		return false, fmt.Errorf("invalid price %v with margin %d", strategy.Price, strategy.MarginBips)
+	}
+	// the most the filler sends for the amount it receives
+	limit := new(big.Float).Mul(new(big.Float).SetInt(receiveAmount), big.NewFloat(strategy.Price))
+	limit.Mul(limit, new(big.Float).SetInt64(10000-strategy.MarginBips))
+	return new(big.Float).SetInt(new(big.Int).Mul(sendAmount, big.NewInt(10000))).Cmp(limit) <= 0, nil
+}
+
+func (config Config) strategy(orderPair string) (Strategy, bool) {
+	for _, strategy := range config.Strategies {
+		if strings.EqualFold(strategy.OrderPair, orderPair) {
+			return strategy, true
+		}
+	}
+	return Strategy{}, false
+}
+
+func (config Config) reserve(chain model.Chain, asset model.Asset) (*big.Int, error) {
+	key := string(chain)
+	if asset != model.Primary {
+		key += ":" + string(asset)
+	}
+	for chainAsset, amount := range config.Reserves {
+		if !strings.EqualFold(chainAsset, key) {
+			continue
+		}
+		reserve, ok := new(big.Int).SetString(amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid reserve %s of %s", amount, key)
+		}
+		return reserve, nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (config Config) pollInterval() time.Duration {
+	if config.PollInterval > 0 {
+		return time.Duration(config.PollInterval) * time.Second
+	}
+	return DefaultPollInterval
+}
@@ -0,0 +1,504 @@
+package filler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/rest"
+	"github.com/catalogfi/orderbook/watcher"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// Orderbook is the part of the orderbook api the filler uses, see rest.Client
+type Orderbook interface {
+	FillOrder(orderID uint, sendAddress, receiveAddress string) error
+	GetOrder(id uint) (model.Order, error)
+	PublishInventory(chain model.Chain, asset model.Asset, amount *big.Int) error
+	Login() (string, error)
+	SetJwt(token string) error
+}
+
+// Filler fills the open orders of its strategies it has the inventory for and
+// drives both swaps of the orders it filled until they complete
+type Filler interface {
+	Run(ctx context.Context)
+}
+
+type filler struct {
+	config    Config
+	address   string
+	addresses map[model.Chain]string
+	orderbook Orderbook
+	newWS     func() rest.WSClient
+	swaps     Swaps
+	balances  map[model.Chain]watcher.BalanceReader
+	store     Store
+	logger    *zap.Logger
+
+	// serializes fills and steps so that inventory is not committed twice
+	mu sync.Mutex
+	// when the jwt of the filler expires
+	tokenExpiry time.Time
+}
+
+func New(config Config, key *ecdsa.PrivateKey, orderbook Orderbook, newWS func() rest.WSClient, swaps Swaps, balances map[model.Chain]watcher.BalanceReader, store Store, logger *zap.Logger) (Filler, error) {
+	addresses := map[model.Chain]string{}
+	for chain := range config.Network {
+		address, err := Address(key, chain)
+		if err != nil {
+			return nil, err
+		}
+		addresses[chain] = address
+	}
+	return &filler{
+		config:    config,
+		address:   strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex()),
+		addresses: addresses,
+		orderbook: orderbook,
+		newWS:     newWS,
+		swaps:     swaps,
+		balances:  balances,
+		store:     store,
+		logger:    logger.With(zap.String("service", "filler")),
+	}, nil
+}
+
+func (f *filler) Run(ctx context.Context) {
+	listening := make(chan struct{})
+	go func() {
+		defer close(listening)
+		f.listen(ctx)
+	}()
+	defer func() { <-listening }()
+	ticker := time.NewTicker(f.config.pollInterval())
+	defer ticker.Stop()
+	for {
+		f.poll()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// listen to the open orders of the pairs of the strategies and to the orders of
+// the filler, reconnecting when the connection drops
+func (f *filler) listen(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		ws := f.newWS()
+		responses := ws.Listen()
+		for _, strategy := range f.config.Strategies {
+			ws.Subscribe("subscribe::" + strategy.OrderPair)
+		}
+		ws.Subscribe("subscribe::" + f.address)
+		f.handle(ctx, responses)
+		if ctx.Err() != nil {
+			return
+		}
+		f.logger.Warn("websocket disconnected, reconnecting", zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+func (f *filler) handle(ctx context.Context, responses <-chan interface{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case response, ok := <-responses:
+			if !ok {
+				return
+			}
+			switch response := response.(type) {
+			case rest.OpenOrders:
+				for _, order := range response.Orders {
+					f.consider(order)
+				}
+			case rest.UpdatedOrders:
+				for _, order := range response.Orders {
+					f.step(order)
+				}
+			case rest.UpdatedOrder:
+				f.step(response.Order)
+			case rest.WebsocketError:
+				f.logger.Warn("websocket error", zap.String("error", response.Error))
+			}
+		}
+	}
+}
+
+// step the active swaps with the latest state of their orders and publish the
+// inventories
+func (f *filler) poll() {
+	swaps, err := f.store.ActiveSwaps()
+	if err != nil {
+		f.logger.Error("get active swaps", zap.Error(err))
+		return
+	}
+	for _, swap := range swaps {
+		order, err := f.orderbook.GetOrder(swap.OrderID)
+		if err != nil {
+			f.logger.Error("get order", zap.Uint("order", swap.OrderID), zap.Error(err))
+			continue
+		}
+		f.step(order)
+	}
+	if f.config.PublishInventory {
+		f.publishInventories()
+	}
+}
+
+// fill an open order if it matches a strategy and the filler has the inventory
+// to send its receive amount
+func (f *filler) consider(order model.Order) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	logger := f.logger.With(zap.Uint("order", order.ID))
+	if order.Status != model.Created || order.Taker != "" || order.Partial || order.InLottery() ||
+		strings.EqualFold(order.Maker, f.address) || order.InitiatorAtomicSwap == nil || order.FollowerAtomicSwap == nil {
+		return
+	}
+	if !order.CanBeFilledBy(f.address) {
+		return
+	}
+	strategy, ok := f.config.strategy(order.OrderPair)
+	if !ok {
+		return
+	}
+	if swap, err := f.store.GetSwap(order.ID); err != nil || swap != nil {
+		return
+	}
+	receiveAmount, ok := new(big.Int).SetString(order.InitiatorAtomicSwap.Amount, 10)
+	if !ok {
+		logger.Error("invalid initiator amount", zap.String("amount", order.InitiatorAtomicSwap.Amount))
+		return
+	}
+	sendAmount, ok := new(big.Int).SetString(order.FollowerAtomicSwap.Amount, 10)
+	if !ok {
+		logger.Error("invalid follower amount", zap.String("amount", order.FollowerAtomicSwap.Amount))
+		return
+	}
+	if order.Auction != nil {
+		// the amount of an auction only decreases until the order is filled
+		amount, err := order.Auction.AmountAt(time.Now())
+		if err != nil {
+			logger.Error("invalid auction", zap.Error(err))
+			return
+		}
+		sendAmount = amount
+	}
+	accepted, err := strategy.Accepts(receiveAmount, sendAmount)
+	if err != nil {
+		logger.Error("price order", zap.Error(err))
+		return
+	}
+	if !accepted {
+		logger.Debug("order is not priced by the strategy")
+		return
+	}
+	chain, asset := order.FollowerAtomicSwap.Chain, order.FollowerAtomicSwap.Asset
+	available, err := f.available(chain, asset)
+	if err != nil {
+		logger.Error("get inventory", zap.Error(err))
+		return
+	}
+	if available.Cmp(sendAmount) < 0 {
+		logger.Info("not enough inventory to fill order", zap.String("available", available.String()), zap.String("amount", sendAmount.String()))
+		return
+	}
+
+	if err := f.authenticate(); err != nil {
+		logger.Error("login", zap.Error(err))
+		return
+	}
+	if err := f.orderbook.FillOrder(order.ID, f.addresses[chain], f.addresses[order.InitiatorAtomicSwap.Chain]); err != nil {
+		logger.Warn("fill order", zap.Error(err))
+		return
+	}
+	amount := sendAmount.String()
+	if order.Auction != nil {
+		// the amount of an auction is locked in when the orderbook fills the order,
+		// which is later than it was priced
+		filled, err := f.orderbook.GetOrder(order.ID)
+		if err != nil || filled.FollowerAtomicSwap == nil {
+			// the swap is recovered from the orders of the filler
+			logger.Error("get filled order", zap.Error(err))
+			return
+		}
+		amount = filled.FollowerAtomicSwap.Amount
+	}
+	swap := &Swap{
+		OrderID:   order.ID,
+		OrderPair: order.OrderPair,
+		Status:    SwapFilled,
+		Chain:     chain,
+		Asset:     asset,
+		Amount:    amount,
+	}
+	if err := f.store.SaveSwap(swap); err != nil {
+		// the swap is recovered from the orders of the filler
+		logger.Error("save swap", zap.Error(err))
+		return
+	}
+	logger.Info("filled order", zap.String("amount", swap.Amount))
+}
+
+// log in to the orderbook when the jwt of the filler expires within TokenRefresh,
+// the jwts issued by the orderbook expire after a day
+func (f *filler) authenticate() error {
+	if time.Until(f.tokenExpiry) > TokenRefresh {
+		return nil
+	}
+	token, err := f.orderbook.Login()
+	if err != nil {
+		return err
+	}
+	expiry, err := rest.GetExpiryFromJWT(token)
+	if err != nil {
+		return err
+	}
+	if err := f.orderbook.SetJwt(token); err != nil {
+		return err
+	}
+	f.tokenExpiry = expiry
+	return nil
+}
+
+// what the filler has available to send of an asset: its balance less its
+// reserve and the amounts of the orders it filled but did not initiate yet
+func (f *filler) available(chain model.Chain, asset model.Asset) (*big.Int, error) {
+	reader, ok := f.balances[chain]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain %s", chain)
+	}
+	balance, err := reader.Balance(context.Background(), asset, f.addresses[chain])
+	if err != nil {
+		return nil, err
+	}
+	reserve, err := f.config.reserve(chain, asset)
+	if err != nil {
+		return nil, err
+	}
+	available := new(big.Int).Sub(balance, reserve)
+	swaps, err := f.store.ActiveSwaps()
+	if err != nil {
+		return nil, err
+	}
+	for _, swap := range swaps {
+		if !swap.Committed() || swap.Chain != chain || !strings.EqualFold(string(swap.Asset), string(asset)) {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(swap.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid amount %s of order %d", swap.Amount, swap.OrderID)
+		}
+		available.Sub(available, amount)
+	}
+	if available.Sign() < 0 {
+		available.SetInt64(0)
+	}
+	return available, nil
+}
+
+// publish what the filler has available of the assets it sends
+func (f *filler) publishInventories() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.authenticate(); err != nil {
+		f.logger.Error("login", zap.Error(err))
+		return
+	}
+	published := map[string]bool{}
+	for _, strategy := range f.config.Strategies {
+		_, chain, _, asset, err := model.ParseOrderPair(strategy.OrderPair)
+		if err != nil {
+			f.logger.Error("invalid strategy", zap.String("pair", strategy.OrderPair), zap.Error(err))
+			continue
+		}
+		if published[string(chain)+":"+string(asset)] {
+			continue
+		}
+		published[string(chain)+":"+string(asset)] = true
+		available, err := f.available(chain, asset)
+		if err != nil {
+			f.logger.Error("get inventory", zap.String("chain", string(chain)), zap.Error(err))
+			continue
+		}
+		if err := f.orderbook.PublishInventory(chain, asset, available); err != nil {
+			f.logger.Error("publish inventory", zap.String("chain", string(chain)), zap.Error(err))
+		}
+	}
+}
+
+// advance the swaps of an order the filler filled with the latest state of the
+// order. Swaps of orders filled before a crash are recovered from the order.
+func (f *filler) step(order model.Order) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.EqualFold(order.Taker, f.address) || order.InitiatorAtomicSwap == nil || order.FollowerAtomicSwap == nil {
+		return
+	}
+	logger := f.logger.With(zap.Uint("order", order.ID))
+	swap, err := f.store.GetSwap(order.ID)
+	if err != nil {
+		logger.Error("get swap", zap.Error(err))
+		return
+	}
+	if swap == nil {
+		if order.Status != model.Filled {
+			return
+		}
+		swap = &Swap{
+			OrderID:   order.ID,
+			OrderPair: order.OrderPair,
+			Status:    SwapFilled,
+			Chain:     order.FollowerAtomicSwap.Chain,
+			Asset:     order.FollowerAtomicSwap.Asset,
+			Amount:    order.FollowerAtomicSwap.Amount,
+		}
+	}
+	status := swap.Status
+	if err := f.advance(swap, order, logger); err != nil {
+		logger.Warn("step swap", zap.String("status", string(swap.Status)), zap.Error(err))
+		swap.Error = err.Error()
+	} else {
+		swap.Error = ""
+	}
+	if swap.ID != 0 && swap.Status == status && swap.Error == "" {
+		return
+	}
+	if err := f.store.SaveSwap(swap); err != nil {
+		logger.Error("save swap", zap.Error(err))
+	}
+}
+
+func (f *filler) advance(swap *Swap, order model.Order, logger *zap.Logger) error {
+	switch swap.Status {
+	case SwapInitiating:
+		// the initiation of the filler was interrupted, it is only sent again once
+		// the orderbook has not seen it for the grace period and the htlc of the
+		// filler is not funded, not even by a pending transaction
+		if order.FollowerAtomicSwap.Status != model.NotStarted {
+			swap.Status = SwapInitiated
+			swap.InitiateTxHash = order.FollowerAtomicSwap.InitiateTxHash
+			return f.advance(swap, order, logger)
+		}
+		if swap.InitiatingAt != nil && time.Since(*swap.InitiatingAt) < InitiateGrace {
+			return nil
+		}
+		initiator, err := f.swaps.Initiator(order)
+		if err != nil {
+			return err
+		}
+		initiated, txHash, err := initiator.IsInitiated()
+		if err != nil {
+			return err
+		}
+		if initiated {
+			logger.Info("found the initiation on chain", zap.String("txHash", txHash))
+			swap.Status = SwapInitiated
+			swap.InitiateTxHash = txHash
+			return f.advance(swap, order, logger)
+		}
+		swap.Status = SwapFilled
+		fallthrough
+	case SwapFilled:
+		if order.Status != model.Filled {
+			logger.Info("abandoned order the maker did not initiate", zap.Uint("status", uint(order.Status)))
+			swap.Status = SwapAbandoned
+			return nil
+		}
+		redeemer, err := f.swaps.Redeemer(order)
+		if err != nil {
+			return err
+		}
+		initiated, _, _, err := redeemer.IsInitiated()
+		if err != nil || !initiated {
+			return err
+		}
+		initiator, err := f.swaps.Initiator(order)
+		if err != nil {
+			return err
+		}
+		// the initiation is recorded before it is sent so that a crash does not
+		// send it twice
+		now := time.Now().UTC()
+		swap.Status = SwapInitiating
+		swap.InitiatingAt = &now
+		if err := f.store.SaveSwap(swap); err != nil {
+			return err
+		}
+		txHash, err := initiator.Initiate()
+		if err != nil {
+			// the transaction may have been sent anyway, the swap stays initiating
+			// so that it is checked on chain before it is sent again
+			return err
+		}
+		logger.Info("initiated", zap.String("txHash", txHash))
+		swap.Status = SwapInitiated
+		swap.InitiateTxHash = txHash
+		return nil
+	case SwapInitiated:
+		initiator, err := f.swaps.Initiator(order)
+		if err != nil {
+			return err
+		}
+		redeemed, secret, _, err := initiator.IsRedeemed()
+		if err != nil {
+			return err
+		}
+		if !redeemed && order.Secret != "" {
+			// the orderbook saw the secret before the chain of the filler did
+			if secret, err = hex.DecodeString(order.Secret); err == nil {
+				redeemed = true
+			}
+		}
+		if redeemed {
+			redeemer, err := f.swaps.Redeemer(order)
+			if err != nil {
+				return err
+			}
+			txHash, err := redeemer.Redeem(secret)
+			if err != nil {
+				return err
+			}
+			logger.Info("redeemed", zap.String("txHash", txHash))
+			swap.Status = SwapRedeemed
+			swap.RedeemTxHash = txHash
+			return nil
+		}
+		expired, err := initiator.Expired()
+		if err != nil || !expired {
+			return err
+		}
+		txHash, err := initiator.Refund()
+		if err != nil {
+			return err
+		}
+		logger.Info("refunded", zap.String("txHash", txHash))
+		swap.Status = SwapRefunded
+		swap.RefundTxHash = txHash
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,582 @@
+package filler_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/catalogfi/orderbook/filler"
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/rest"
+	"github.com/catalogfi/orderbook/swapper"
+	"github.com/catalogfi/orderbook/watcher"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	btcChain = model.BitcoinTestnet
+	ethChain = model.EthereumSepolia
+	ethAsset = model.Asset("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+	pair     = "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"
+	maker    = "0x17100301bb2ff58ae6b5ca5b8f9ec6f872e0f2da"
+)
+
+// orderbook is an in memory orderbook in which the watcher is played by the tests
+type orderbook struct {
+	mu          sync.Mutex
+	orders      map[uint]model.Order
+	inventories map[string]string
+	// how long the jwts it issues are valid
+	ttl    time.Duration
+	jwt    string
+	logins int
+}
+
+func newOrderbook() *orderbook {
+	return &orderbook{orders: map[uint]model.Order{}, inventories: map[string]string{}, ttl: 24 * time.Hour}
+}
+
+func (o *orderbook) Login() (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.logins++
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, rest.Claims{
+		UserWallet: maker,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(o.ttl).Unix(),
+		},
+	}).SignedString([]byte("secret"))
+}
+
+func (o *orderbook) SetJwt(token string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.jwt = token
+	return nil
+}
+
+// the error of the orderbook when the jwt of a request is missing or expired
+func (o *orderbook) authenticate() error {
+	expiry, err := rest.GetExpiryFromJWT(o.jwt)
+	if err != nil {
+		return fmt.Errorf("401: %v", err)
+	}
+	if time.Now().After(expiry) {
+		return fmt.Errorf("401: token is expired")
+	}
+	return nil
+}
+
+func (o *orderbook) add(id uint, receiveAmount, sendAmount string) model.Order {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	order := model.Order{
+		Model:     gorm.Model{ID: id},
+		Maker:     maker,
+		OrderPair: pair,
+		Status:    model.Created,
+		InitiatorAtomicSwap: &model.AtomicSwap{
+			Chain:  btcChain,
+			Asset:  model.Primary,
+			Amount: receiveAmount,
+		},
+		FollowerAtomicSwap: &model.AtomicSwap{
+			Chain:  ethChain,
+			Asset:  ethAsset,
+			Amount: sendAmount,
+		},
+	}
+	o.orders[id] = order
+	return order
+}
+
+func (o *orderbook) update(id uint, update func(order *model.Order)) model.Order {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	order := o.orders[id]
+	initiator, follower := *order.InitiatorAtomicSwap, *order.FollowerAtomicSwap
+	order.InitiatorAtomicSwap, order.FollowerAtomicSwap = &initiator, &follower
+	update(&order)
+	o.orders[id] = order
+	return order
+}
+
+func (o *orderbook) FillOrder(orderID uint, sendAddress, receiveAddress string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.authenticate(); err != nil {
+		return err
+	}
+	order, ok := o.orders[orderID]
+	if !ok || order.Status != model.Created {
+		return fmt.Errorf("order %d cannot be filled", orderID)
+	}
+	order.Status = model.Filled
+	order.Taker = strings.ToLower(sendAddress)
+	initiator, follower := *order.InitiatorAtomicSwap, *order.FollowerAtomicSwap
+	initiator.RedeemerAddress, follower.InitiatorAddress = receiveAddress, sendAddress
+	if order.Auction != nil {
+		// the fill reaches the orderbook a second after the filler priced it
+		amount, err := order.Auction.AmountAt(time.Now().Add(time.Second))
+		if err != nil {
+			return err
+		}
+		follower.Amount = amount.String()
+	}
+	order.InitiatorAtomicSwap, order.FollowerAtomicSwap = &initiator, &follower
+	o.orders[orderID] = order
+	return nil
+}
+
+func (o *orderbook) GetOrder(id uint) (model.Order, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	order, ok := o.orders[id]
+	if !ok {
+		return model.Order{}, fmt.Errorf("order %d not found", id)
+	}
+	return order, nil
+}
+
+func (o *orderbook) PublishInventory(chain model.Chain, asset model.Asset, amount *big.Int) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.authenticate(); err != nil {
+		return err
+	}
+	o.inventories[string(chain)+":"+string(asset)] = amount.String()
+	return nil
+}
+
+func (o *orderbook) inventory(chain model.Chain, asset model.Asset) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.inventories[string(chain)+":"+string(asset)]
+}
+
+// websocket delivers the responses pushed by the tests to every connection
+type websocket struct {
+	mu            sync.Mutex
+	responses     chan interface{}
+	subscriptions []string
+}
+
+func (ws *websocket) Listen() <-chan interface{} {
+	return ws.responses
+}
+
+func (ws *websocket) Subscribe(msg string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.subscriptions = append(ws.subscriptions, msg)
+}
+
+func (ws *websocket) subscribed() []string {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return append([]string{}, ws.subscriptions...)
+}
+
+// chain is the state of both legs of the orders, the maker is played by the tests
+type chain struct {
+	mu              sync.Mutex
+	makerInitiated  map[uint]bool
+	fillerInitiated map[uint]int
+	// the transactions funding the htlc of the filler, pending ones included
+	fillerFunded   map[uint]string
+	makerRedeemed  map[uint][]byte
+	fillerRedeemed map[uint][]byte
+	expired        map[uint]bool
+	refunded       map[uint]bool
+}
+
+func newChain() *chain {
+	return &chain{
+		makerInitiated:  map[uint]bool{},
+		fillerInitiated: map[uint]int{},
+		fillerFunded:    map[uint]string{},
+		makerRedeemed:   map[uint][]byte{},
+		fillerRedeemed:  map[uint][]byte{},
+		expired:         map[uint]bool{},
+		refunded:        map[uint]bool{},
+	}
+}
+
+func (c *chain) do(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f()
+}
+
+func (c *chain) Initiator(order model.Order) (swapper.InitiatorSwap, error) {
+	return &initiatorSwap{chain: c, id: order.ID}, nil
+}
+
+func (c *chain) Redeemer(order model.Order) (swapper.RedeemerSwap, error) {
+	return &redeemerSwap{chain: c, id: order.ID}, nil
+}
+
+// initiatorSwap is the leg the filler initiates
+type initiatorSwap struct {
+	chain *chain
+	id    uint
+}
+
+func (s *initiatorSwap) Initiate() (txHash string, err error) {
+	s.chain.do(func() {
+		s.chain.fillerInitiated[s.id]++
+		txHash = fmt.Sprintf("initiate-%d", s.id)
+		s.chain.fillerFunded[s.id] = txHash
+	})
+	return txHash, nil
+}
+
+func (s *initiatorSwap) IsInitiated() (initiated bool, txHash string, err error) {
+	s.chain.do(func() {
+		txHash, initiated = s.chain.fillerFunded[s.id]
+	})
+	return initiated, txHash, nil
+}
+
+func (s *initiatorSwap) WaitForRedeem() ([]byte, string, error) {
+	return nil, "", fmt.Errorf("not implemented")
+}
+
+func (s *initiatorSwap) IsRedeemed() (redeemed bool, secret []byte, txHash string, err error) {
+	s.chain.do(func() {
+		secret, redeemed = s.chain.makerRedeemed[s.id]
+	})
+	return redeemed, secret, "", nil
+}
+
+func (s *initiatorSwap) Refund() (txHash string, err error) {
+	s.chain.do(func() {
+		s.chain.refunded[s.id] = true
+	})
+	return fmt.Sprintf("refund-%d", s.id), nil
+}
+
+func (s *initiatorSwap) Expired() (expired bool, err error) {
+	s.chain.do(func() {
+		expired = s.chain.expired[s.id]
+	})
+	return expired, nil
+}
+
+// redeemerSwap is the leg the maker initiates
+type redeemerSwap struct {
+	chain *chain
+	id    uint
+}
+
+func (s *redeemerSwap) Redeem(secret []byte) (string, error) {
+	s.chain.do(func() {
+		s.chain.fillerRedeemed[s.id] = secret
+	})
+	return fmt.Sprintf("redeem-%d", s.id), nil
+}
+
+func (s *redeemerSwap) IsInitiated() (initiated bool, txHash string, amount uint64, err error) {
+	s.chain.do(func() {
+		initiated = s.chain.makerInitiated[s.id]
+	})
+	return initiated, "", 0, nil
+}
+
+func (s *redeemerSwap) WaitForInitiate() (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+type balance struct {
+	amount *big.Int
+}
+
+func (b balance) Balance(ctx context.Context, asset model.Asset, address string) (*big.Int, error) {
+	return b.amount, nil
+}
+
+var _ = Describe("Filler", func() {
+	var (
+		key      *ecdsa.PrivateKey
+		address  string
+		config   Config
+		book     *orderbook
+		ws       *websocket
+		swaps    *chain
+		balances map[model.Chain]watcher.BalanceReader
+		store    Store
+	)
+
+	BeforeEach(func() {
+		var err error
+		key, err = crypto.GenerateKey()
+		Expect(err).NotTo(HaveOccurred())
+		address = strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+		config = Config{
+			Network: model.Network{btcChain: {}, ethChain: {}},
+			Strategies: []Strategy{{
+				OrderPair:  pair,
+				Price:      10,
+				MarginBips: 100,
+				MaxAmount:  "10000000",
+			}},
+			Reserves:         map[string]string{string(ethChain) + ":" + string(ethAsset): "100000"},
+			PublishInventory: true,
+			PollInterval:     1,
+		}
+		book = newOrderbook()
+		ws = &websocket{responses: make(chan interface{}, 8)}
+		swaps = newChain()
+		balances = map[model.Chain]watcher.BalanceReader{ethChain: balance{big.NewInt(2100000)}}
+		store, err = NewStore(sqlite.Open("test.db"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.Remove("test.db")).To(Succeed())
+	})
+
+	start := func() context.CancelFunc {
+		filler, err := New(config, key, book, func() rest.WSClient { return ws }, swaps, balances, store, zap.NewNop())
+		Expect(err).NotTo(HaveOccurred())
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			filler.Run(ctx)
+		}()
+		stop := func() {
+			cancel()
+			Eventually(done).Should(BeClosed())
+		}
+		DeferCleanup(stop)
+		return stop
+	}
+
+	swapStatus := func(id uint) SwapStatus {
+		swap, err := store.GetSwap(id)
+		Expect(err).NotTo(HaveOccurred())
+		if swap == nil {
+			return ""
+		}
+		return swap.Status
+	}
+
+	It("should price orders by the strategy", func() {
+		strategy := config.Strategies[0]
+		Expect(strategy.Accepts(big.NewInt(100000), big.NewInt(990000))).To(BeTrue())
+		Expect(strategy.Accepts(big.NewInt(100000), big.NewInt(990001))).To(BeFalse())
+		Expect(strategy.Accepts(big.NewInt(10000000), big.NewInt(10000001))).To(BeFalse())
+		strategy.Price = 0
+		_, err := strategy.Accepts(big.NewInt(100000), big.NewInt(1))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fill an order and drive both swaps to completion", func() {
+		start()
+		Eventually(ws.subscribed).Should(ConsistOf("subscribe::"+pair, "subscribe::"+address))
+
+		ws.responses <- rest.OpenOrders{Orders: []model.Order{book.add(1, "100000", "990000")}}
+		Eventually(func() SwapStatus { return swapStatus(1) }).Should(Equal(SwapFilled))
+		order, err := book.GetOrder(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Taker).To(Equal(address))
+		ethAddress, err := Address(key, ethChain)
+		Expect(err).NotTo(HaveOccurred())
+		btcAddress, err := Address(key, btcChain)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.FollowerAtomicSwap.InitiatorAddress).To(Equal(ethAddress))
+		Expect(order.InitiatorAtomicSwap.RedeemerAddress).To(Equal(btcAddress))
+
+		// the filler only initiates once the maker did
+		Consistently(func() int { return swaps.fillerInitiated[1] }, 1500*time.Millisecond).Should(BeZero())
+		swaps.do(func() { swaps.makerInitiated[1] = true })
+		ws.responses <- rest.UpdatedOrders{Orders: []model.Order{book.update(1, func(order *model.Order) {
+			order.InitiatorAtomicSwap.Status = model.Initiated
+		})}}
+		Eventually(func() SwapStatus { return swapStatus(1) }).Should(Equal(SwapInitiated))
+		swap, err := store.GetSwap(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(swap.InitiateTxHash).To(Equal("initiate-1"))
+
+		secret := []byte("secret")
+		swaps.do(func() { swaps.makerRedeemed[1] = secret })
+		Eventually(func() SwapStatus { return swapStatus(1) }, 5*time.Second).Should(Equal(SwapRedeemed))
+		swaps.do(func() {
+			Expect(swaps.fillerInitiated[1]).To(Equal(1))
+			Expect(swaps.fillerRedeemed[1]).To(Equal(secret))
+		})
+	})
+
+	It("should store the amount an auction order locked in when it was filled", func() {
+		start()
+
+		book.add(1, "100000", "990000")
+		order := book.update(1, func(order *model.Order) {
+			order.Auction = &model.AuctionCurve{StartAmount: "990000", EndAmount: "900000", StartsAt: time.Now(), EndsAt: time.Now().Add(10 * time.Second)}
+		})
+		ws.responses <- rest.OpenOrders{Orders: []model.Order{order}}
+		Eventually(func() SwapStatus { return swapStatus(1) }).Should(Equal(SwapFilled))
+
+		filled, err := book.GetOrder(1)
+		Expect(err).NotTo(HaveOccurred())
+		swap, err := store.GetSwap(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(swap.Amount).To(Equal(filled.FollowerAtomicSwap.Amount))
+	})
+
+	It("should only fill orders it has the inventory for", func() {
+		start()
+
+		// the balance less the reserve covers two orders
+		ws.responses <- rest.OpenOrders{Orders: []model.Order{
+			book.add(1, "100000", "990000"),
+			book.add(2, "100000", "990000"),
+			book.add(3, "100000", "990000"),
+			book.add(4, "100000", "990001"),
+		}}
+		Eventually(func() SwapStatus { return swapStatus(2) }).Should(Equal(SwapFilled))
+		Expect(swapStatus(1)).To(Equal(SwapFilled))
+		Eventually(func() string { return book.inventory(ethChain, ethAsset) }, 5*time.Second).Should(Equal("20000"))
+		Expect(swapStatus(3)).To(BeEmpty())
+		Expect(swapStatus(4)).To(BeEmpty())
+
+		// an order the maker does not initiate releases its inventory
+		book.update(1, func(order *model.Order) { order.Status = model.Cancelled })
+		Eventually(func() SwapStatus { return swapStatus(1) }, 5*time.Second).Should(Equal(SwapAbandoned))
+		ws.responses <- rest.OpenOrders{Orders: []model.Order{book.update(3, func(*model.Order) {})}}
+		Eventually(func() SwapStatus { return swapStatus(3) }).Should(Equal(SwapFilled))
+	})
+
+	It("should log in again before its jwt expires", func() {
+		book.ttl = 2 * time.Second
+		start()
+		Eventually(func() string { return book.inventory(ethChain, ethAsset) }).Should(Equal("2000000"))
+
+		// the orderbook rejects the first jwt once it expired
+		time.Sleep(3 * time.Second)
+		ws.responses <- rest.OpenOrders{Orders: []model.Order{book.add(1, "100000", "990000")}}
+		Eventually(func() SwapStatus { return swapStatus(1) }).Should(Equal(SwapFilled))
+		book.mu.Lock()
+		defer book.mu.Unlock()
+		Expect(book.logins).To(BeNumerically(">", 1))
+	})
+
+	It("should keep its jwt until it expires", func() {
+		start()
+		ws.responses <- rest.OpenOrders{Orders: []model.Order{book.add(1, "100000", "990000"), book.add(2, "100000", "990000")}}
+		Eventually(func() SwapStatus { return swapStatus(2) }).Should(Equal(SwapFilled))
+		Eventually(func() string { return book.inventory(ethChain, ethAsset) }, 5*time.Second).Should(Equal("20000"))
+		book.mu.Lock()
+		defer book.mu.Unlock()
+		Expect(book.logins).To(Equal(1))
+	})
+
+	It("should resume its swaps after a restart", func() {
+		stop := start()
+		ws.responses <- rest.OpenOrders{Orders: []model.Order{book.add(1, "100000", "990000"), book.add(2, "100000", "990000")}}
+		Eventually(func() SwapStatus { return swapStatus(2) }).Should(Equal(SwapFilled))
+		swaps.do(func() {
+			swaps.makerInitiated[1] = true
+			swaps.makerInitiated[2] = true
+		})
+		Eventually(func() SwapStatus { return swapStatus(1) }, 5*time.Second).Should(Equal(SwapInitiated))
+		Eventually(func() SwapStatus { return swapStatus(2) }, 5*time.Second).Should(Equal(SwapInitiated))
+		stop()
+
+		// the maker redeems one swap and the other expires while the filler is down
+		swaps.do(func() {
+			swaps.makerRedeemed[1] = []byte("secret")
+			swaps.expired[2] = true
+		})
+		var err error
+		store, err = NewStore(sqlite.Open("test.db"), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		start()
+
+		Eventually(func() SwapStatus { return swapStatus(1) }, 5*time.Second).Should(Equal(SwapRedeemed))
+		Eventually(func() SwapStatus { return swapStatus(2) }, 5*time.Second).Should(Equal(SwapRefunded))
+		swaps.do(func() {
+			Expect(swaps.fillerInitiated[1]).To(Equal(1))
+			Expect(swaps.fillerInitiated[2]).To(Equal(1))
+			Expect(swaps.fillerRedeemed[1]).To(Equal([]byte("secret")))
+			Expect(swaps.refunded[2]).To(BeTrue())
+		})
+	})
+
+	It("should not initiate twice after a crash during its initiation", func() {
+		order := book.add(1, "100000", "990000")
+		token, err := book.Login()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(book.SetJwt(token)).To(Succeed())
+		Expect(book.FillOrder(1, address, "")).To(Succeed())
+		now := time.Now().UTC()
+		Expect(store.SaveSwap(&Swap{OrderID: order.ID, OrderPair: pair, Status: SwapInitiating, Chain: ethChain, Asset: ethAsset, Amount: "990000", InitiatingAt: &now})).To(Succeed())
+		swaps.do(func() { swaps.makerInitiated[1] = true })
+
+		start()
+		Consistently(func() SwapStatus { return swapStatus(1) }, 1500*time.Millisecond).Should(Equal(SwapInitiating))
+		book.update(1, func(order *model.Order) {
+			order.FollowerAtomicSwap.Status = model.Initiated
+			order.FollowerAtomicSwap.InitiateTxHash = "initiate-before-crash"
+		})
+		Eventually(func() SwapStatus { return swapStatus(1) }, 5*time.Second).Should(Equal(SwapInitiated))
+		swaps.do(func() { Expect(swaps.fillerInitiated[1]).To(BeZero()) })
+		swap, err := store.GetSwap(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(swap.InitiateTxHash).To(Equal("initiate-before-crash"))
+		Expect(swap.Error).To(BeEmpty())
+	})
+
+	It("should not initiate again when its htlc is funded but the orderbook did not see it", func() {
+		order := book.add(1, "100000", "990000")
+		token, err := book.Login()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(book.SetJwt(token)).To(Succeed())
+		Expect(book.FillOrder(1, address, "")).To(Succeed())
+		initiatingAt := time.Now().UTC().Add(-InitiateGrace - time.Minute)
+		Expect(store.SaveSwap(&Swap{OrderID: order.ID, OrderPair: pair, Status: SwapInitiating, Chain: ethChain, Asset: ethAsset, Amount: "990000", InitiatingAt: &initiatingAt})).To(Succeed())
+		swaps.do(func() {
+			swaps.makerInitiated[1] = true
+			swaps.fillerFunded[1] = "initiate-pending"
+		})
+
+		start()
+		Eventually(func() SwapStatus { return swapStatus(1) }, 5*time.Second).Should(Equal(SwapInitiated))
+		swaps.do(func() { Expect(swaps.fillerInitiated[1]).To(BeZero()) })
+		swap, err := store.GetSwap(1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(swap.InitiateTxHash).To(Equal("initiate-pending"))
+	})
+
+	It("should initiate again once the grace period passes without a funded htlc", func() {
+		order := book.add(1, "100000", "990000")
+		token, err := book.Login()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(book.SetJwt(token)).To(Succeed())
+		Expect(book.FillOrder(1, address, "")).To(Succeed())
+		initiatingAt := time.Now().UTC().Add(-InitiateGrace - time.Minute)
+		Expect(store.SaveSwap(&Swap{OrderID: order.ID, OrderPair: pair, Status: SwapInitiating, Chain: ethChain, Asset: ethAsset, Amount: "990000", InitiatingAt: &initiatingAt})).To(Succeed())
+		swaps.do(func() { swaps.makerInitiated[1] = true })
+
+		start()
+		Eventually(func() SwapStatus { return swapStatus(1) }, 5*time.Second).Should(Equal(SwapInitiated))
+		Consistently(func() int {
+			var initiated int
+			swaps.do(func() { initiated = swaps.fillerInitiated[1] })
+			return initiated
+		}, 1500*time.Millisecond).Should(Equal(1))
+	})
+})
@@ -0,0 +1,105 @@
+package filler
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+type SwapStatus string
+
+const (
+	// the order is filled, the filler waits for the maker to initiate
+	SwapFilled SwapStatus = "filled"
+	// the filler is initiating its swap, it is not initiated again before the
+	// initiate grace period passes and only if its htlc is not funded on chain
+	SwapInitiating SwapStatus = "initiating"
+	// the filler initiated its swap and waits for the maker to redeem it
+	SwapInitiated SwapStatus = "initiated"
+	// the filler redeemed the swap of the maker
+	SwapRedeemed SwapStatus = "redeemed"
+	// the maker never redeemed and the filler refunded its swap
+	SwapRefunded SwapStatus = "refunded"
+	// the maker never initiated
+	SwapAbandoned SwapStatus = "abandoned"
+)
+
+// Swap is the state of an order the filler filled, persisted so that the filler
+// completes its swaps after a restart
+type Swap struct {
+	gorm.Model
+
+	OrderID   uint       `gorm:"uniqueIndex"`
+	OrderPair string     `gorm:"size:255"`
+	Status    SwapStatus `gorm:"size:16;index"`
+	// the chain, asset and amount the filler sends
+	Chain  model.Chain `gorm:"size:64"`
+	Asset  model.Asset `gorm:"size:255"`
+	Amount string      `gorm:"size:78"`
+
+	InitiatingAt   *time.Time
+	InitiateTxHash string
+	RedeemTxHash   string
+	RefundTxHash   string
+	// the last error driving the swap
+	Error string
+}
+
+// Done reports whether the filler has nothing left to do for the swap
+func (swap Swap) Done() bool {
+	return swap.Status == SwapRedeemed || swap.Status == SwapRefunded || swap.Status == SwapAbandoned
+}
+
+// Committed reports whether the amount of the swap is committed but not sent yet
+func (swap Swap) Committed() bool {
+	return swap.Status == SwapFilled || swap.Status == SwapInitiating
+}
+
+type Store interface {
+	// save the state of a swap
+	SaveSwap(swap *Swap) error
+	// get the swap of an order, nil if the filler did not fill it
+	GetSwap(orderID uint) (*Swap, error)
+	// get the swaps which are not done yet
+	ActiveSwaps() ([]Swap, error)
+}
+
+type store struct {
+	db *gorm.DB
+}
+
+// NewStore returns a store of the swaps of the filler in its own database
+func NewStore(dialector gorm.Dialector, opts ...gorm.Option) (Store, error) {
+	db, err := gorm.Open(dialector, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&Swap{}); err != nil {
+		return nil, err
+	}
+	return &store{db: db}, nil
+}
+
+func (s *store) SaveSwap(swap *Swap) error {
+	return s.db.Save(swap).Error
+}
+
+func (s *store) GetSwap(orderID uint) (*Swap, error) {
+	swaps := []Swap{}
+	if err := s.db.Where("order_id = ?", orderID).Limit(1).Find(&swaps).Error; err != nil {
+		return nil, err
+	}
+	if len(swaps) == 0 {
+		return nil, nil
+	}
+	return &swaps[0], nil
+}
+
+func (s *store) ActiveSwaps() ([]Swap, error) {
+	swaps := []Swap{}
+	if err := s.db.Where("status IN ?", []SwapStatus{SwapFilled, SwapInitiating, SwapInitiated}).Order("order_id ASC").Find(&swaps).Error; err != nil {
+		return nil, err
+	}
+	return swaps, nil
+}
@@ -0,0 +1,170 @@
+package filler
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/swapper"
+	"github.com/catalogfi/orderbook/swapper/bitcoin"
+	"github.com/catalogfi/orderbook/swapper/ethereum"
+	"github.com/catalogfi/orderbook/watcher"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// Swaps builds the legs of the orders the filler filled on their chains
+type Swaps interface {
+	// the swap the filler initiates on the chain the maker receives on
+	Initiator(order model.Order) (swapper.InitiatorSwap, error)
+	// the swap the maker initiated, which the filler redeems
+	Redeemer(order model.Order) (swapper.RedeemerSwap, error)
+}
+
+type chainSwaps struct {
+	key        *ecdsa.PrivateKey
+	btcKey     *btcec.PrivateKey
+	network    model.Network
+	btcClients map[model.Chain]bitcoin.Client
+	ethClients map[model.Chain]ethereum.Client
+	logger     *zap.Logger
+}
+
+// NewSwaps returns the swaps of the filler with the given key on the chains of
+// the given clients
+func NewSwaps(key *ecdsa.PrivateKey, network model.Network, btcClients map[model.Chain]bitcoin.Client, ethClients map[model.Chain]ethereum.Client, logger *zap.Logger) Swaps {
+	btcKey, _ := btcec.PrivKeyFromBytes(crypto.FromECDSA(key))
+	return &chainSwaps{
+		key:        key,
+		btcKey:     btcKey,
+		network:    network,
+		btcClients: btcClients,
+		ethClients: ethClients,
+		logger:     logger,
+	}
+}
+
+// LoadSwaps returns the swaps of the filler with the given key on the chains of
+// the network
+func LoadSwaps(key *ecdsa.PrivateKey, network model.Network, logger *zap.Logger) (Swaps, error) {
+	btcClients := map[model.Chain]bitcoin.Client{}
+	ethClients := map[model.Chain]ethereum.Client{}
+	for chain, config := range network {
+		switch {
+		case chain.IsBTC():
+			client, err := watcher.LoadBTCClient(chain, config, nil)
+			if err != nil {
+				return nil, err
+			}
+			btcClients[chain] = client
+		case chain.IsEVM():
+			client, err := ethereum.NewClient(logger, config.RPC["ethrpc"])
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client: %v", err)
+			}
+			ethClients[chain] = client
+		}
+	}
+	return NewSwaps(key, network, btcClients, ethClients, logger), nil
+}
+
+// Address returns the address of the filler with the given key on a chain, a
+// native segwit address on bitcoin chains
+func Address(key *ecdsa.PrivateKey, chain model.Chain) (string, error) {
+	switch {
+	case chain.IsBTC():
+		btcKey, _ := btcec.PrivKeyFromBytes(crypto.FromECDSA(key))
+		address, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(btcKey.PubKey().SerializeCompressed()), chain.Params())
+		if err != nil {
+			return "", err
+		}
+		return address.EncodeAddress(), nil
+	case chain.IsEVM():
+		return crypto.PubkeyToAddress(key.PublicKey).Hex(), nil
+	}
+	return "", fmt.Errorf("unsupported chain %s", chain)
+}
+
+func (s *chainSwaps) Initiator(order model.Order) (swapper.InitiatorSwap, error) {
+	swap := order.FollowerAtomicSwap
+	if swap == nil {
+		return nil, fmt.Errorf("order %d has no follower swap", order.ID)
+	}
+	secretHash, amount, timelock, err := swapParams(order, *swap)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case swap.Chain.IsBTC():
+		client, ok := s.btcClients[swap.Chain]
+		if !ok {
+			return nil, fmt.Errorf("unsupported chain %s", swap.Chain)
+		}
+		redeemer, err := btcutil.DecodeAddress(swap.RedeemerAddress, swap.Chain.Params())
+		if err != nil {
+			return nil, fmt.Errorf("invalid redeemer address: %v", err)
+		}
+		return bitcoin.NewInitiatorSwap(s.logger, s.btcKey, redeemer, secretHash, timelock.Int64(), swap.MinimumConfirmations, amount.Uint64(), client)
+	case swap.Chain.IsEVM():
+		client, ok := s.ethClients[swap.Chain]
+		if !ok {
+			return nil, fmt.Errorf("unsupported chain %s", swap.Chain)
+		}
+		minConfirmations := new(big.Int).SetUint64(swap.MinimumConfirmations)
+		return ethereum.NewInitiatorSwap(s.key, common.HexToAddress(swap.RedeemerAddress), common.HexToAddress(string(swap.Asset)), secretHash, timelock, minConfirmations, amount, client, s.network[swap.Chain].EventWindow)
+	}
+	return nil, fmt.Errorf("unsupported chain %s", swap.Chain)
+}
+
+func (s *chainSwaps) Redeemer(order model.Order) (swapper.RedeemerSwap, error) {
+	swap := order.InitiatorAtomicSwap
+	if swap == nil {
+		return nil, fmt.Errorf("order %d has no initiator swap", order.ID)
+	}
+	secretHash, amount, timelock, err := swapParams(order, *swap)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case swap.Chain.IsBTC():
+		client, ok := s.btcClients[swap.Chain]
+		if !ok {
+			return nil, fmt.Errorf("unsupported chain %s", swap.Chain)
+		}
+		initiator, err := btcutil.DecodeAddress(swap.InitiatorAddress, swap.Chain.Params())
+		if err != nil {
+			return nil, fmt.Errorf("invalid initiator address: %v", err)
+		}
+		return bitcoin.NewRedeemerSwap(s.logger, s.btcKey, initiator, secretHash, timelock.Int64(), swap.MinimumConfirmations, amount.Uint64(), client)
+	case swap.Chain.IsEVM():
+		client, ok := s.ethClients[swap.Chain]
+		if !ok {
+			return nil, fmt.Errorf("unsupported chain %s", swap.Chain)
+		}
+		minConfirmations := new(big.Int).SetUint64(swap.MinimumConfirmations)
+		return ethereum.NewRedeemerSwap(s.key, common.HexToAddress(swap.InitiatorAddress), common.HexToAddress(string(swap.Asset)), secretHash, timelock, amount, minConfirmations, client, s.network[swap.Chain].EventWindow)
+	}
+	return nil, fmt.Errorf("unsupported chain %s", swap.Chain)
+}
+
+// the secret hash of the order and the amount and timelock of one of its swaps
+func swapParams(order model.Order, swap model.AtomicSwap) ([]byte, *big.Int, *big.Int, error) {
+	secretHash, err := hex.DecodeString(order.SecretHash)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid secret hash: %v", err)
+	}
+	amount, ok := new(big.Int).SetString(swap.Amount, 10)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("invalid amount: %s", swap.Amount)
+	}
+	timelock, ok := new(big.Int).SetString(swap.Timelock, 10)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("invalid timelock: %s", swap.Timelock)
+	}
+	return secretHash, amount, timelock, nil
+}
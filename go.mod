@@ -84,7 +84,6 @@ require (
 	golang.org/x/tools v0.20.0 // indirect
 	google.golang.org/protobuf v1.33.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
-	gorm.io/driver/mysql v1.4.7 // indirect
 	rsc.io/tmplfunc v0.0.3 // indirect
 )
 
@@ -102,5 +101,6 @@ require (
 	go.uber.org/mock v0.2.0
 	go.uber.org/zap v1.27.0
 	gorm.io/datatypes v1.2.0
+	gorm.io/driver/mysql v1.4.7
 	gorm.io/driver/postgres v1.5.2
 )
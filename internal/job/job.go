@@ -0,0 +1,53 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job runs until its context is done
+type Job interface {
+	Run(ctx context.Context)
+}
+
+type periodic struct {
+	interval time.Duration
+	run      func(ctx context.Context) error
+	logger   *zap.Logger
+}
+
+// NewPeriodic returns a job which runs the func right away and then once every
+// interval, logging its errors
+func NewPeriodic(interval time.Duration, run func(ctx context.Context) error, logger *zap.Logger) Job {
+	return &periodic{
+		interval: interval,
+		run:      run,
+		logger:   logger,
+	}
+}
+
+// Interval returns the interval configured in seconds, or the default interval
+// when it is not configured
+func Interval(seconds int64, defaultInterval time.Duration) time.Duration {
+	if seconds <= 0 {
+		return defaultInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (p *periodic) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		if err := p.run(ctx); err != nil {
+			p.logger.Error("run periodic job", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/catalogfi/orderbook/price"
 	"github.com/catalogfi/orderbook/rest"
 	"github.com/catalogfi/orderbook/screener"
+	"github.com/catalogfi/orderbook/stats"
 	"github.com/catalogfi/orderbook/store"
 	watchers "github.com/catalogfi/orderbook/watcher"
 	"github.com/ethereum/go-ethereum/common"
@@ -53,7 +54,21 @@ func LoadConfiguration(file string) Config {
 func main() {
 	// psql db
 	envConfig := LoadConfiguration(path.ConfigPath)
-	store, err := store.New(postgres.Open(envConfig.PSQL_DB), path.SQLSetupPath, &gorm.Config{
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		db, err := gorm.Open(postgres.Open(envConfig.PSQL_DB), &gorm.Config{
+			NowFunc: func() time.Time { return time.Now().UTC() },
+			Logger:  logger.Default.LogMode(logger.Silent),
+		})
+		if err != nil {
+			panic(err)
+		}
+		if err := migrate(db, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	store, err := store.New(postgres.Open(envConfig.PSQL_DB), &gorm.Config{
 		NowFunc: func() time.Time { return time.Now().UTC() },
 		Logger:  logger.Default.LogMode(logger.Silent),
 	})
@@ -86,6 +101,27 @@ func main() {
 	go watcher.Run(context.Background())
 
 	screener := screener.NewScreener(store.Gorm(), envConfig.TRM_KEY)
+	roller := stats.NewRoller(store, envConfig.CONFIG, logger)
+	go roller.Run(context.Background())
+	snapshotter := stats.NewSnapshotter(store, envConfig.CONFIG, logger)
+	go snapshotter.Run(context.Background())
+	scorer := stats.NewScorer(store, envConfig.CONFIG, logger)
+	go scorer.Run(context.Background())
+	drawer := watchers.NewDrawer(store, envConfig.CONFIG.Network, logger)
+	go drawer.Run(context.Background())
+	if envConfig.CONFIG.Bond.Chain != "" {
+		verifier, err := watchers.NewEVMVerifier(store, envConfig.CONFIG, logger)
+		if err != nil {
+			panic(err)
+		}
+		go verifier.Run(context.Background())
+	}
+	readers, err := watchers.LoadBalanceReaders(envConfig.CONFIG.Network, logger)
+	if err != nil {
+		panic(err)
+	}
+	stocktaker := watchers.NewStocktaker(store, readers, envConfig.CONFIG, logger)
+	go stocktaker.Run(context.Background())
 	for chain, Network := range envConfig.CONFIG.Network {
 		if chain.IsBTC() {
 			//interval is set to 10 seconds to detect iw tx's quicky
@@ -116,9 +152,10 @@ func main() {
 	listener := rest.NewDBListener(envConfig.PSQL_DB, socketPool, logger, store)
 	go listener.Start("updates_to_orders", "updates_to_atomic_swaps", "added_to_orders")
 
-	priceFetcher := price.NewPriceFetcher(price.Options{
+	// the discount path shares the price cache of the store
+	priceFetcher := price.NewCachedPriceFetcher(price.NewPriceFetcher(price.Options{
 		URL: envConfig.PRICE_URL,
-	})
+	}), store.Prices(), "btc-seed", time.Duration(envConfig.CONFIG.PriceTTL)*time.Second)
 	feehubClient := feehub.NewFeehubClient(envConfig.FEEHUB_URL)
 	server := rest.NewServer(store, envConfig.CONFIG, logger, envConfig.SERVER_SECRET, socketPool, screener, feehubClient, priceFetcher)
 	if err := server.Run(context.Background(), fmt.Sprintf(":%s", envConfig.PORT)); err != nil {
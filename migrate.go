@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/catalogfi/orderbook/store"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: orderbook migrate [up [n] | down [n] | status]"
+
+// runs the migrate subcommand, migrations are applied up to the latest version
+// by default, down reverts the latest migration unless told otherwise
+func migrate(db *gorm.DB, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	steps := 0
+	if command == "down" {
+		steps = 1
+	}
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of migrations %q, %s", args[1], migrateUsage)
+		}
+		steps = n
+	}
+
+	switch command {
+	case "up":
+		if err := store.MigrateUp(db, steps); err != nil {
+			return err
+		}
+	case "down":
+		if err := store.MigrateDown(db, steps); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", command, migrateUsage)
+	}
+
+	statuses, err := store.MigrationStatuses(db)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return w.Flush()
+}
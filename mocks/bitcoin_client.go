@@ -145,14 +145,30 @@ func (mr *MockClientMockRecorder) GetTx(txid interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTx", reflect.TypeOf((*MockBitcoinClient)(nil).GetTx), txid)
 }
 
+// GetTxs mocks base method.
+func (m *MockBitcoinClient) GetTxs(addr string) ([]bitcoin.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxs", addr)
+	ret0, _ := ret[0].([]bitcoin.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTxs indicates an expected call of GetTxs.
+func (mr *MockClientMockRecorder) GetTxs(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxs", reflect.TypeOf((*MockBitcoinClient)(nil).GetTxs), addr)
+}
+
 // GetUTXOs mocks base method.
-func (m *MockBitcoinClient) GetUTXOs(address btcutil.Address, amount uint64) (bitcoin.UTXOs, uint64, error) {
+func (m *MockBitcoinClient) GetUTXOs(address btcutil.Address, amount uint64) (bitcoin.UTXOs, uint64, uint64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUTXOs", address, amount)
 	ret0, _ := ret[0].(bitcoin.UTXOs)
 	ret1, _ := ret[1].(uint64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret2, _ := ret[2].(uint64)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
 }
 
 // GetUTXOs indicates an expected call of GetUTXOs.
@@ -1,16 +1,17 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: ./store/store.go
+// Source: github.com/catalogfi/orderbook/rest (interfaces: Store)
 
-// Package mock_store is a generated GoMock package.
+// Package mocks is a generated GoMock package.
 package mocks
 
 import (
 	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/catalogfi/orderbook/model"
+	rest "github.com/catalogfi/orderbook/rest"
 	gomock "go.uber.org/mock/gomock"
-	gorm "gorm.io/gorm"
 )
 
 // MockServerStore is a mock of Store interface.
@@ -36,207 +37,569 @@ func (m *MockServerStore) EXPECT() *MockServerStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptQuote mocks base method.
+func (m *MockServerStore) AcceptQuote(arg0 string, arg1, arg2 uint, arg3, arg4, arg5, arg6 string, arg7 model.Config) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptQuote", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptQuote indicates an expected call of AcceptQuote.
+func (mr *MockServerStoreMockRecorder) AcceptQuote(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptQuote", reflect.TypeOf((*MockServerStore)(nil).AcceptQuote), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+}
+
 // CancelOrder mocks base method.
-func (m *MockServerStore) CancelOrder(creator string, orderID uint) error {
+func (m *MockServerStore) CancelOrder(arg0 string, arg1 uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CancelOrder", creator, orderID)
+	ret := m.ctrl.Call(m, "CancelOrder", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CancelOrder indicates an expected call of CancelOrder.
-func (mr *MockServerStoreMockRecorder) CancelOrder(creator, orderID interface{}) *gomock.Call {
+func (mr *MockServerStoreMockRecorder) CancelOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockServerStore)(nil).CancelOrder), arg0, arg1)
+}
+
+// Candles mocks base method.
+func (m *MockServerStore) Candles(arg0 string, arg1 model.CandleInterval, arg2, arg3 time.Time) ([]model.Candle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Candles", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.Candle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Candles indicates an expected call of Candles.
+func (mr *MockServerStoreMockRecorder) Candles(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Candles", reflect.TypeOf((*MockServerStore)(nil).Candles), arg0, arg1, arg2, arg3)
+}
+
+// CompleteIdempotencyKey mocks base method.
+func (m *MockServerStore) CompleteIdempotencyKey(arg0, arg1 string, arg2 int, arg3 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotencyKey", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotencyKey indicates an expected call of CompleteIdempotencyKey.
+func (mr *MockServerStoreMockRecorder) CompleteIdempotencyKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockServerStore)(nil).CancelOrder), creator, orderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotencyKey", reflect.TypeOf((*MockServerStore)(nil).CompleteIdempotencyKey), arg0, arg1, arg2, arg3)
 }
 
 // CreateOrder mocks base method.
-func (m *MockServerStore) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash, userWalletBTCAddress string, config model.Config) (uint, error) {
+func (m *MockServerStore) CreateOrder(arg0, arg1, arg2, arg3, arg4, arg5 string, arg6, arg7, arg8, arg9 *big.Int, arg10 bool, arg11 model.OrderTerms, arg12 model.Config, arg13 ...rest.AfterHook) (uint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrder", creator, sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash, userWalletBTCAddress, config)
+	varargs := []interface{}{arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12}
+	for _, a := range arg13 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateOrder", varargs...)
 	ret0, _ := ret[0].(uint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockServerStoreMockRecorder) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash, userWalletBTCAddress, config interface{}) *gomock.Call {
+func (mr *MockServerStoreMockRecorder) CreateOrder(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12 interface{}, arg13 ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockServerStore)(nil).CreateOrder), creator, sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash, userWalletBTCAddress, config)
+	varargs := append([]interface{}{arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12}, arg13...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockServerStore)(nil).CreateOrder), varargs...)
+}
+
+// CreateQuote mocks base method.
+func (m *MockServerStore) CreateQuote(arg0 *model.Quote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQuote", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateQuote indicates an expected call of CreateQuote.
+func (mr *MockServerStoreMockRecorder) CreateQuote(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuote", reflect.TypeOf((*MockServerStore)(nil).CreateQuote), arg0)
+}
+
+// CreateQuoteRequest mocks base method.
+func (m *MockServerStore) CreateQuoteRequest(arg0, arg1 string, arg2 *big.Int, arg3 time.Duration, arg4 model.Config) (*model.QuoteRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQuoteRequest", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.QuoteRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateQuoteRequest indicates an expected call of CreateQuoteRequest.
+func (mr *MockServerStoreMockRecorder) CreateQuoteRequest(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQuoteRequest", reflect.TypeOf((*MockServerStore)(nil).CreateQuoteRequest), arg0, arg1, arg2, arg3, arg4)
 }
 
 // FillOrder mocks base method.
-func (m *MockServerStore) FillOrder(orderID uint, filler, sendAddress, receiveAddress string, config model.Network) error {
+func (m *MockServerStore) FillOrder(arg0 uint, arg1, arg2, arg3 string, arg4 model.Network) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FillOrder", orderID, filler, sendAddress, receiveAddress, config)
+	ret := m.ctrl.Call(m, "FillOrder", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FillOrder indicates an expected call of FillOrder.
-func (mr *MockServerStoreMockRecorder) FillOrder(orderID, filler, sendAddress, receiveAddress, config interface{}) *gomock.Call {
+func (mr *MockServerStoreMockRecorder) FillOrder(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillOrder", reflect.TypeOf((*MockServerStore)(nil).FillOrder), arg0, arg1, arg2, arg3, arg4)
+}
+
+// FillOrderPart mocks base method.
+func (m *MockServerStore) FillOrderPart(arg0 uint, arg1, arg2, arg3 string, arg4 *big.Int, arg5 model.Network) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FillOrderPart", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FillOrderPart indicates an expected call of FillOrderPart.
+func (mr *MockServerStoreMockRecorder) FillOrderPart(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillOrder", reflect.TypeOf((*MockServerStore)(nil).FillOrder), orderID, filler, sendAddress, receiveAddress, config)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillOrderPart", reflect.TypeOf((*MockServerStore)(nil).FillOrderPart), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // FilterOrders mocks base method.
-func (m *MockServerStore) FilterOrders(maker, taker, orderPair, secretHash, sort string, status model.Status, minPrice, maxPrice, minAmount, maxAmount float64, page, perPage int, verbose bool) ([]model.Order, error) {
+func (m *MockServerStore) FilterOrders(arg0 model.OrderFilter) ([]model.Order, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FilterOrders", maker, taker, orderPair, secretHash, sort, status, minPrice, maxPrice, minAmount, maxAmount, page, perPage, verbose)
+	ret := m.ctrl.Call(m, "FilterOrders", arg0)
 	ret0, _ := ret[0].([]model.Order)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FilterOrders indicates an expected call of FilterOrders.
+func (mr *MockServerStoreMockRecorder) FilterOrders(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterOrders", reflect.TypeOf((*MockServerStore)(nil).FilterOrders), arg0)
+}
+
+// GetBond mocks base method.
+func (m *MockServerStore) GetBond(arg0 string) (*model.Bond, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBond", arg0)
+	ret0, _ := ret[0].(*model.Bond)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FilterOrders indicates an expected call of FilterOrders.
-func (mr *MockServerStoreMockRecorder) FilterOrders(maker, taker, orderPair, secretHash, sort, status, minPrice, maxPrice, minAmount, maxAmount, page, perPage, verbose interface{}) *gomock.Call {
+// GetBond indicates an expected call of GetBond.
+func (mr *MockServerStoreMockRecorder) GetBond(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterOrders", reflect.TypeOf((*MockServerStore)(nil).FilterOrders), maker, taker, orderPair, secretHash, sort, status, minPrice, maxPrice, minAmount, maxAmount, page, perPage, verbose)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBond", reflect.TypeOf((*MockServerStore)(nil).GetBond), arg0)
 }
 
-// GetActiveOrders mocks base method.
-func (m *MockServerStore) GetActiveOrders() ([]model.Order, error) {
+// GetFillIntents mocks base method.
+func (m *MockServerStore) GetFillIntents(arg0 uint) ([]model.FillIntent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActiveOrders")
-	ret0, _ := ret[0].([]model.Order)
+	ret := m.ctrl.Call(m, "GetFillIntents", arg0)
+	ret0, _ := ret[0].([]model.FillIntent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetActiveOrders indicates an expected call of GetActiveOrders.
-func (mr *MockServerStoreMockRecorder) GetActiveOrders() *gomock.Call {
+// GetFillIntents indicates an expected call of GetFillIntents.
+func (mr *MockServerStoreMockRecorder) GetFillIntents(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveOrders", reflect.TypeOf((*MockServerStore)(nil).GetActiveOrders))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFillIntents", reflect.TypeOf((*MockServerStore)(nil).GetFillIntents), arg0)
 }
 
-// GetActiveSwaps mocks base method.
-func (m *MockServerStore) GetActiveSwaps(chain model.Chain) ([]model.AtomicSwap, error) {
+// GetFiller mocks base method.
+func (m *MockServerStore) GetFiller(arg0 string) (*model.Filler, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActiveSwaps", chain)
-	ret0, _ := ret[0].([]model.AtomicSwap)
+	ret := m.ctrl.Call(m, "GetFiller", arg0)
+	ret0, _ := ret[0].(*model.Filler)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetActiveSwaps indicates an expected call of GetActiveSwaps.
-func (mr *MockServerStoreMockRecorder) GetActiveSwaps(chain interface{}) *gomock.Call {
+// GetFiller indicates an expected call of GetFiller.
+func (mr *MockServerStoreMockRecorder) GetFiller(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSwaps", reflect.TypeOf((*MockServerStore)(nil).GetActiveSwaps), chain)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFiller", reflect.TypeOf((*MockServerStore)(nil).GetFiller), arg0)
+}
+
+// GetFillers mocks base method.
+func (m *MockServerStore) GetFillers() ([]model.Filler, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFillers")
+	ret0, _ := ret[0].([]model.Filler)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFillers indicates an expected call of GetFillers.
+func (mr *MockServerStoreMockRecorder) GetFillers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFillers", reflect.TypeOf((*MockServerStore)(nil).GetFillers))
+}
+
+// GetInventories mocks base method.
+func (m *MockServerStore) GetInventories(arg0 string, arg1 time.Time) ([]model.Inventory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventories", arg0, arg1)
+	ret0, _ := ret[0].([]model.Inventory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventories indicates an expected call of GetInventories.
+func (mr *MockServerStoreMockRecorder) GetInventories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventories", reflect.TypeOf((*MockServerStore)(nil).GetInventories), arg0, arg1)
 }
 
 // GetOrder mocks base method.
-func (m *MockServerStore) GetOrder(orderID uint) (*model.Order, error) {
+func (m *MockServerStore) GetOrder(arg0 uint) (*model.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", orderID)
+	ret := m.ctrl.Call(m, "GetOrder", arg0)
 	ret0, _ := ret[0].(*model.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrder indicates an expected call of GetOrder.
-func (mr *MockServerStoreMockRecorder) GetOrder(orderID interface{}) *gomock.Call {
+func (mr *MockServerStoreMockRecorder) GetOrder(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockServerStore)(nil).GetOrder), orderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockServerStore)(nil).GetOrder), arg0)
 }
 
 // GetOrderBySwapID mocks base method.
-func (m *MockServerStore) GetOrderBySwapID(swapID uint) (*model.Order, error) {
+func (m *MockServerStore) GetOrderBySwapID(arg0 uint) (*model.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrderBySwapID", swapID)
+	ret := m.ctrl.Call(m, "GetOrderBySwapID", arg0)
 	ret0, _ := ret[0].(*model.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrderBySwapID indicates an expected call of GetOrderBySwapID.
-func (mr *MockServerStoreMockRecorder) GetOrderBySwapID(swapID interface{}) *gomock.Call {
+func (mr *MockServerStoreMockRecorder) GetOrderBySwapID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderBySwapID", reflect.TypeOf((*MockServerStore)(nil).GetOrderBySwapID), arg0)
+}
+
+// GetOrderHistory mocks base method.
+func (m *MockServerStore) GetOrderHistory(arg0 uint) (model.OrderHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderHistory", arg0)
+	ret0, _ := ret[0].(model.OrderHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderHistory indicates an expected call of GetOrderHistory.
+func (mr *MockServerStoreMockRecorder) GetOrderHistory(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderBySwapID", reflect.TypeOf((*MockServerStore)(nil).GetOrderBySwapID), swapID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderHistory", reflect.TypeOf((*MockServerStore)(nil).GetOrderHistory), arg0)
 }
 
 // GetOrdersByAddress mocks base method.
-func (m *MockServerStore) GetOrdersByAddress(address string) ([]model.Order, error) {
+func (m *MockServerStore) GetOrdersByAddress(arg0 string) ([]model.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrdersByAddress", address)
+	ret := m.ctrl.Call(m, "GetOrdersByAddress", arg0)
 	ret0, _ := ret[0].([]model.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrdersByAddress indicates an expected call of GetOrdersByAddress.
-func (mr *MockServerStoreMockRecorder) GetOrdersByAddress(address interface{}) *gomock.Call {
+func (mr *MockServerStoreMockRecorder) GetOrdersByAddress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersByAddress", reflect.TypeOf((*MockServerStore)(nil).GetOrdersByAddress), arg0)
+}
+
+// GetPenalties mocks base method.
+func (m *MockServerStore) GetPenalties(arg0 string, arg1 model.PenaltyStatus) ([]model.Penalty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPenalties", arg0, arg1)
+	ret0, _ := ret[0].([]model.Penalty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPenalties indicates an expected call of GetPenalties.
+func (mr *MockServerStoreMockRecorder) GetPenalties(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersByAddress", reflect.TypeOf((*MockServerStore)(nil).GetOrdersByAddress), address)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPenalties", reflect.TypeOf((*MockServerStore)(nil).GetPenalties), arg0, arg1)
 }
 
-// Gorm mocks base method.
-func (m *MockServerStore) Gorm() *gorm.DB {
+// GetPendingOrdersForAddress mocks base method.
+func (m *MockServerStore) GetPendingOrdersForAddress(arg0 string) ([]model.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Gorm")
-	ret0, _ := ret[0].(*gorm.DB)
+	ret := m.ctrl.Call(m, "GetPendingOrdersForAddress", arg0)
+	ret0, _ := ret[0].([]model.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingOrdersForAddress indicates an expected call of GetPendingOrdersForAddress.
+func (mr *MockServerStoreMockRecorder) GetPendingOrdersForAddress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOrdersForAddress", reflect.TypeOf((*MockServerStore)(nil).GetPendingOrdersForAddress), arg0)
+}
+
+// GetQuoteRequest mocks base method.
+func (m *MockServerStore) GetQuoteRequest(arg0 uint) (*model.QuoteRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuoteRequest", arg0)
+	ret0, _ := ret[0].(*model.QuoteRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuoteRequest indicates an expected call of GetQuoteRequest.
+func (mr *MockServerStoreMockRecorder) GetQuoteRequest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuoteRequest", reflect.TypeOf((*MockServerStore)(nil).GetQuoteRequest), arg0)
+}
+
+// GetSecrets mocks base method.
+func (m *MockServerStore) GetSecrets(arg0 time.Time) ([]model.SecretRevealed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecrets", arg0)
+	ret0, _ := ret[0].([]model.SecretRevealed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecrets indicates an expected call of GetSecrets.
+func (mr *MockServerStoreMockRecorder) GetSecrets(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecrets", reflect.TypeOf((*MockServerStore)(nil).GetSecrets), arg0)
+}
+
+// GetUserLimits mocks base method.
+func (m *MockServerStore) GetUserLimits(arg0 string, arg1 bool) ([]model.UserLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLimits", arg0, arg1)
+	ret0, _ := ret[0].([]model.UserLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLimits indicates an expected call of GetUserLimits.
+func (mr *MockServerStoreMockRecorder) GetUserLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLimits", reflect.TypeOf((*MockServerStore)(nil).GetUserLimits), arg0, arg1)
+}
+
+// GrantUserLimit mocks base method.
+func (m *MockServerStore) GrantUserLimit(arg0 *model.UserLimit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantUserLimit", arg0)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Gorm indicates an expected call of Gorm.
-func (mr *MockServerStoreMockRecorder) Gorm() *gomock.Call {
+// GrantUserLimit indicates an expected call of GrantUserLimit.
+func (mr *MockServerStoreMockRecorder) GrantUserLimit(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantUserLimit", reflect.TypeOf((*MockServerStore)(nil).GrantUserLimit), arg0)
+}
+
+// OpenQuoteRequests mocks base method.
+func (m *MockServerStore) OpenQuoteRequests(arg0 string) ([]model.QuoteRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenQuoteRequests", arg0)
+	ret0, _ := ret[0].([]model.QuoteRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenQuoteRequests indicates an expected call of OpenQuoteRequests.
+func (mr *MockServerStoreMockRecorder) OpenQuoteRequests(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Gorm", reflect.TypeOf((*MockServerStore)(nil).Gorm))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenQuoteRequests", reflect.TypeOf((*MockServerStore)(nil).OpenQuoteRequests), arg0)
 }
 
-// SwapByOCID mocks base method.
-func (m *MockServerStore) SwapByOCID(ocID string) (model.AtomicSwap, error) {
+// OrderStats mocks base method.
+func (m *MockServerStore) OrderStats(arg0 model.Granularity, arg1, arg2 time.Time, arg3 string) ([]model.OrderStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SwapByOCID", ocID)
-	ret0, _ := ret[0].(model.AtomicSwap)
+	ret := m.ctrl.Call(m, "OrderStats", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.OrderStats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SwapByOCID indicates an expected call of SwapByOCID.
-func (mr *MockServerStoreMockRecorder) SwapByOCID(ocID interface{}) *gomock.Call {
+// OrderStats indicates an expected call of OrderStats.
+func (mr *MockServerStoreMockRecorder) OrderStats(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderStats", reflect.TypeOf((*MockServerStore)(nil).OrderStats), arg0, arg1, arg2, arg3)
+}
+
+// PublishInventory mocks base method.
+func (m *MockServerStore) PublishInventory(arg0 *model.Inventory, arg1 model.Network) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishInventory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishInventory indicates an expected call of PublishInventory.
+func (mr *MockServerStoreMockRecorder) PublishInventory(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapByOCID", reflect.TypeOf((*MockServerStore)(nil).SwapByOCID), ocID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishInventory", reflect.TypeOf((*MockServerStore)(nil).PublishInventory), arg0, arg1)
 }
 
-// UpdateOrder mocks base method.
-func (m *MockServerStore) UpdateOrder(order *model.Order) error {
+// RegisterFillIntent mocks base method.
+func (m *MockServerStore) RegisterFillIntent(arg0 uint, arg1, arg2, arg3 string, arg4 model.Network) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateOrder", order)
+	ret := m.ctrl.Call(m, "RegisterFillIntent", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateOrder indicates an expected call of UpdateOrder.
-func (mr *MockServerStoreMockRecorder) UpdateOrder(order interface{}) *gomock.Call {
+// RegisterFillIntent indicates an expected call of RegisterFillIntent.
+func (mr *MockServerStoreMockRecorder) RegisterFillIntent(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrder", reflect.TypeOf((*MockServerStore)(nil).UpdateOrder), order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFillIntent", reflect.TypeOf((*MockServerStore)(nil).RegisterFillIntent), arg0, arg1, arg2, arg3, arg4)
 }
 
-// UpdateSwap mocks base method.
-func (m *MockServerStore) UpdateSwap(swap *model.AtomicSwap) error {
+// RegisterFiller mocks base method.
+func (m *MockServerStore) RegisterFiller(arg0, arg1, arg2, arg3 string, arg4 []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSwap", swap)
+	ret := m.ctrl.Call(m, "RegisterFiller", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateSwap indicates an expected call of UpdateSwap.
-func (mr *MockServerStoreMockRecorder) UpdateSwap(swap interface{}) *gomock.Call {
+// RegisterFiller indicates an expected call of RegisterFiller.
+func (mr *MockServerStoreMockRecorder) RegisterFiller(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFiller", reflect.TypeOf((*MockServerStore)(nil).RegisterFiller), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockServerStore) ReleaseIdempotencyKey(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockServerStoreMockRecorder) ReleaseIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockServerStore)(nil).ReleaseIdempotencyKey), arg0, arg1)
+}
+
+// ReserveIdempotencyKey mocks base method.
+func (m *MockServerStore) ReserveIdempotencyKey(arg0, arg1, arg2 string, arg3, arg4 time.Duration) (*model.IdempotentRequest, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveIdempotencyKey", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.IdempotentRequest)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReserveIdempotencyKey indicates an expected call of ReserveIdempotencyKey.
+func (mr *MockServerStoreMockRecorder) ReserveIdempotencyKey(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveIdempotencyKey", reflect.TypeOf((*MockServerStore)(nil).ReserveIdempotencyKey), arg0, arg1, arg2, arg3, arg4)
+}
+
+// RevokeUserLimit mocks base method.
+func (m *MockServerStore) RevokeUserLimit(arg0 uint, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserLimit", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserLimit indicates an expected call of RevokeUserLimit.
+func (mr *MockServerStoreMockRecorder) RevokeUserLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserLimit", reflect.TypeOf((*MockServerStore)(nil).RevokeUserLimit), arg0, arg1)
+}
+
+// SettlePenalty mocks base method.
+func (m *MockServerStore) SettlePenalty(arg0 uint, arg1 model.PenaltyStatus, arg2, arg3, arg4 string) (*model.Penalty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettlePenalty", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*model.Penalty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettlePenalty indicates an expected call of SettlePenalty.
+func (mr *MockServerStoreMockRecorder) SettlePenalty(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettlePenalty", reflect.TypeOf((*MockServerStore)(nil).SettlePenalty), arg0, arg1, arg2, arg3, arg4)
+}
+
+// TVLSeries mocks base method.
+func (m *MockServerStore) TVLSeries(arg0 model.Chain, arg1, arg2 time.Time) ([]model.TVLPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TVLSeries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.TVLPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TVLSeries indicates an expected call of TVLSeries.
+func (mr *MockServerStoreMockRecorder) TVLSeries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TVLSeries", reflect.TypeOf((*MockServerStore)(nil).TVLSeries), arg0, arg1, arg2)
+}
+
+// Trades mocks base method.
+func (m *MockServerStore) Trades(arg0 string, arg1, arg2 time.Time, arg3 int) ([]model.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trades", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trades indicates an expected call of Trades.
+func (mr *MockServerStoreMockRecorder) Trades(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trades", reflect.TypeOf((*MockServerStore)(nil).Trades), arg0, arg1, arg2, arg3)
+}
+
+// UserStats mocks base method.
+func (m *MockServerStore) UserStats(arg0 string, arg1 model.Granularity, arg2, arg3 time.Time) ([]model.UserStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserStats", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.UserStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserStats indicates an expected call of UserStats.
+func (mr *MockServerStoreMockRecorder) UserStats(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSwap", reflect.TypeOf((*MockServerStore)(nil).UpdateSwap), swap)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserStats", reflect.TypeOf((*MockServerStore)(nil).UserStats), arg0, arg1, arg2, arg3)
 }
 
 // ValueLockedByChain mocks base method.
-func (m *MockServerStore) ValueLockedByChain(chain model.Chain, config model.Network) (*big.Int, error) {
+func (m *MockServerStore) ValueLockedByChain(arg0 model.Chain, arg1 model.Network) (model.Decimal, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValueLockedByChain", chain, config)
-	ret0, _ := ret[0].(*big.Int)
+	ret := m.ctrl.Call(m, "ValueLockedByChain", arg0, arg1)
+	ret0, _ := ret[0].(model.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ValueLockedByChain indicates an expected call of ValueLockedByChain.
-func (mr *MockServerStoreMockRecorder) ValueLockedByChain(chain, config interface{}) *gomock.Call {
+func (mr *MockServerStoreMockRecorder) ValueLockedByChain(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValueLockedByChain", reflect.TypeOf((*MockServerStore)(nil).ValueLockedByChain), chain, config)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValueLockedByChain", reflect.TypeOf((*MockServerStore)(nil).ValueLockedByChain), arg0, arg1)
 }
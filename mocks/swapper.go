@@ -64,6 +64,22 @@ func (mr *MockInitiatorSwapMockRecorder) Initiate() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Initiate", reflect.TypeOf((*MockInitiatorSwap)(nil).Initiate))
 }
 
+// IsInitiated mocks base method.
+func (m *MockInitiatorSwap) IsInitiated() (bool, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsInitiated")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// IsInitiated indicates an expected call of IsInitiated.
+func (mr *MockInitiatorSwapMockRecorder) IsInitiated() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsInitiated", reflect.TypeOf((*MockInitiatorSwap)(nil).IsInitiated))
+}
+
 // IsRedeemed mocks base method.
 func (m *MockInitiatorSwap) IsRedeemed() (bool, []byte, string, error) {
 	m.ctrl.T.Helper()
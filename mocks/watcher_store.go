@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	model "github.com/catalogfi/orderbook/model"
+	watcher "github.com/catalogfi/orderbook/watcher"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -63,6 +64,7 @@ func (mr *MockStoreMockRecorder) GetActiveSwaps(chain interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSwaps", reflect.TypeOf((*MockStore)(nil).GetActiveSwaps), chain)
 }
+
 // GetOrderBySwapID mocks base method.
 func (m *MockStore) GetOrderBySwapID(swapID uint) (*model.Order, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +74,12 @@ func (m *MockStore) GetOrderBySwapID(swapID uint) (*model.Order, error) {
 	return ret0, ret1
 }
 
+// GetOrderBySwapID indicates an expected call of GetOrderBySwapID.
+func (mr *MockStoreMockRecorder) GetOrderBySwapID(swapID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderBySwapID", reflect.TypeOf((*MockStore)(nil).GetOrderBySwapID), swapID)
+}
+
 // SwapByOCID mocks base method.
 func (m *MockStore) SwapByOCID(ocID string) (model.AtomicSwap, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +109,20 @@ func (mr *MockStoreMockRecorder) UpdateOrder(order interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrder", reflect.TypeOf((*MockStore)(nil).UpdateOrder), order)
 }
 
+// RecordPenalty mocks base method.
+func (m *MockStore) RecordPenalty(order *model.Order, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPenalty", order, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPenalty indicates an expected call of RecordPenalty.
+func (mr *MockStoreMockRecorder) RecordPenalty(order, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPenalty", reflect.TypeOf((*MockStore)(nil).RecordPenalty), order, reason)
+}
+
 // UpdateSwap mocks base method.
 func (m *MockStore) UpdateSwap(swap *model.AtomicSwap) error {
 	m.ctrl.T.Helper()
@@ -114,3 +136,103 @@ func (mr *MockStoreMockRecorder) UpdateSwap(swap interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSwap", reflect.TypeOf((*MockStore)(nil).UpdateSwap), swap)
 }
+
+// GetBlockCursor mocks base method.
+func (m *MockStore) GetBlockCursor(chain model.Chain, contract string) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockCursor", chain, contract)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockCursor indicates an expected call of GetBlockCursor.
+func (mr *MockStoreMockRecorder) GetBlockCursor(chain, contract interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockCursor", reflect.TypeOf((*MockStore)(nil).GetBlockCursor), chain, contract)
+}
+
+// UpdateBlockCursor mocks base method.
+func (m *MockStore) UpdateBlockCursor(chain model.Chain, contract string, blockNumber uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBlockCursor", chain, contract, blockNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBlockCursor indicates an expected call of UpdateBlockCursor.
+func (mr *MockStoreMockRecorder) UpdateBlockCursor(chain, contract, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBlockCursor", reflect.TypeOf((*MockStore)(nil).UpdateBlockCursor), chain, contract, blockNumber)
+}
+
+// Transaction mocks base method.
+func (m *MockStore) Transaction(fn func(watcher.Store) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockStoreMockRecorder) Transaction(fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockStore)(nil).Transaction), fn)
+}
+
+// RecordBlockHash mocks base method.
+func (m *MockStore) RecordBlockHash(chain model.Chain, contract string, blockNumber uint64, blockHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordBlockHash", chain, contract, blockNumber, blockHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordBlockHash indicates an expected call of RecordBlockHash.
+func (mr *MockStoreMockRecorder) RecordBlockHash(chain, contract, blockNumber, blockHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBlockHash", reflect.TypeOf((*MockStore)(nil).RecordBlockHash), chain, contract, blockNumber, blockHash)
+}
+
+// GetProcessedBlocks mocks base method.
+func (m *MockStore) GetProcessedBlocks(chain model.Chain, contract string, limit int) ([]model.ProcessedBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProcessedBlocks", chain, contract, limit)
+	ret0, _ := ret[0].([]model.ProcessedBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProcessedBlocks indicates an expected call of GetProcessedBlocks.
+func (mr *MockStoreMockRecorder) GetProcessedBlocks(chain, contract, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProcessedBlocks", reflect.TypeOf((*MockStore)(nil).GetProcessedBlocks), chain, contract, limit)
+}
+
+// RollbackBlocks mocks base method.
+func (m *MockStore) RollbackBlocks(chain model.Chain, contract string, blockNumber uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackBlocks", chain, contract, blockNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackBlocks indicates an expected call of RollbackBlocks.
+func (mr *MockStoreMockRecorder) RollbackBlocks(chain, contract, blockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackBlocks", reflect.TypeOf((*MockStore)(nil).RollbackBlocks), chain, contract, blockNumber)
+}
+
+// RollbackL2Blocks mocks base method.
+func (m *MockStore) RollbackL2Blocks(chain model.Chain, contract string, blockNumber, l1BlockNumber uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackL2Blocks", chain, contract, blockNumber, l1BlockNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackL2Blocks indicates an expected call of RollbackL2Blocks.
+func (mr *MockStoreMockRecorder) RollbackL2Blocks(chain, contract, blockNumber, l1BlockNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackL2Blocks", reflect.TypeOf((*MockStore)(nil).RollbackL2Blocks), chain, contract, blockNumber, l1BlockNumber)
+}
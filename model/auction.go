@@ -0,0 +1,61 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuctionCurve is the receive amount of a Dutch auction order, which decays
+// linearly from the start amount when the auction starts to the end amount when
+// it ends. Amounts are in the smallest unit of the asset received.
+type AuctionCurve struct {
+	StartAmount string    `json:"startAmount" gorm:"size:78"`
+	EndAmount   string    `json:"endAmount" gorm:"size:78"`
+	StartsAt    time.Time `json:"startsAt"`
+	EndsAt      time.Time `json:"endsAt"`
+	// the receive amount when the order was read
+	CurrentAmount string `json:"currentAmount" gorm:"-"`
+}
+
+// AmountAt returns the receive amount of the auction at the given time, rounded
+// up so that the maker never receives less than the curve
+func (curve AuctionCurve) AmountAt(t time.Time) (*big.Int, error) {
+	start, ok := new(big.Int).SetString(curve.StartAmount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid auction start amount %q", curve.StartAmount)
+	}
+	end, ok := new(big.Int).SetString(curve.EndAmount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid auction end amount %q", curve.EndAmount)
+	}
+	if !t.After(curve.StartsAt) {
+		return start, nil
+	}
+	if !t.Before(curve.EndsAt) {
+		return end, nil
+	}
+	elapsed := big.NewInt(int64(t.Sub(curve.StartsAt)))
+	duration := big.NewInt(int64(curve.EndsAt.Sub(curve.StartsAt)))
+	decay := new(big.Int).Mul(new(big.Int).Sub(start, end), elapsed)
+	// floor of the decay is the ceiling of the amount
+	decay.Div(decay, duration)
+	return start.Sub(start, decay), nil
+}
+
+// AfterFind sets the current amount of the auction of an auction order, orders
+// without an auction have no curve
+func (order *Order) AfterFind(tx *gorm.DB) error {
+	if order.Auction == nil || order.Auction.StartAmount == "" {
+		order.Auction = nil
+		return nil
+	}
+	amount, err := order.Auction.AmountAt(time.Now())
+	if err != nil {
+		return err
+	}
+	order.Auction.CurrentAmount = amount.String()
+	return nil
+}
@@ -0,0 +1,43 @@
+package model_test
+
+import (
+	"math/big"
+	"time"
+
+	. "github.com/catalogfi/orderbook/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Auctions", func() {
+	It("should decay the receive amount linearly", func() {
+		start := time.Now()
+		curve := AuctionCurve{StartAmount: "1000", EndAmount: "900", StartsAt: start, EndsAt: start.Add(100 * time.Second)}
+		for offset, expected := range map[time.Duration]int64{
+			-time.Second:            1000,
+			0:                       1000,
+			25 * time.Second:        975,
+			1500 * time.Millisecond: 999,
+			100 * time.Second:       900,
+			time.Hour:               900,
+		} {
+			amount, err := curve.AmountAt(start.Add(offset))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(amount.Int64()).To(Equal(expected), offset.String())
+		}
+	})
+
+	It("should validate auction terms", func() {
+		now := time.Now()
+		terms, err := OrderTerms{Type: AuctionOrder, AuctionEndAmount: big.NewInt(900), AuctionDuration: time.Hour}.Validate(now, big.NewInt(1000))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(terms.ExpiresAt).To(Equal(now.Add(time.Hour)))
+
+		_, err = OrderTerms{Type: AuctionOrder, AuctionEndAmount: big.NewInt(1000), AuctionDuration: time.Hour}.Validate(now, big.NewInt(1000))
+		Expect(err).To(HaveOccurred())
+		_, err = OrderTerms{Type: AuctionOrder, AuctionEndAmount: big.NewInt(900), AuctionDuration: MaxAuctionDuration + time.Second}.Validate(now, big.NewInt(1000))
+		Expect(err).To(HaveOccurred())
+		_, err = OrderTerms{Type: LimitOrder, ExpiresAt: now.Add(time.Hour), AuctionDuration: time.Hour}.Validate(now, big.NewInt(1000))
+		Expect(err).To(HaveOccurred())
+	})
+})
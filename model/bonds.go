@@ -0,0 +1,103 @@
+package model
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BondConfig is the bond fillers post to fill orders. The bond of a filler is the
+// balance of its bond address on the bond chain, or the bond a bond contract
+// holds for the filler when one is configured. A bond address other than the
+// filler signs BondAddressMessage when the filler registers it.
+type BondConfig struct {
+	Chain Chain
+	// token the bond is posted in, the native asset of the chain when empty
+	Token string
+	// contract holding the bonds of the fillers, queried with bondOf(address)
+	Contract string
+	// minimum bond in the smallest unit of the asset, bonds are not required when
+	// empty
+	MinAmount string
+	// seconds between verifications of the bonds, defaults to ten minutes
+	VerifyInterval int64
+}
+
+// Required reports whether fillers have to post a bond to fill orders
+func (config BondConfig) Required() bool {
+	return config.MinAmount != ""
+}
+
+// Bond is the bond of a filler as last verified on chain. Slashed amounts are
+// owed by the filler and count against its bond until it posts more.
+type Bond struct {
+	Filler      string     `json:"filler"`
+	BondAddress string     `json:"bondAddress"`
+	Amount      string     `json:"amount"`
+	Slashed     string     `json:"slashed"`
+	Available   string     `json:"available"`
+	Pending     int64      `json:"pendingPenalties"`
+	VerifiedAt  *time.Time `json:"verifiedAt,omitempty"`
+}
+
+// BondAddressMessage is the message a bond address signs to hold the bond of the
+// filler
+func BondAddressMessage(filler string) string {
+	return fmt.Sprintf("Orderbook bond address\nFiller: %s", strings.ToLower(filler))
+}
+
+// SignBondAddress signs the message letting the filler register the address of
+// the key as its bond address
+func SignBondAddress(filler string, key *ecdsa.PrivateKey) (string, error) {
+	return signMessage(BondAddressMessage(filler), key)
+}
+
+// BondAddressSigner returns the lower case address which signed the message
+// letting the filler register it as its bond address
+func BondAddressSigner(filler, signature string) (string, error) {
+	signer, err := recoverSigner(BondAddressMessage(filler), signature)
+	if err != nil {
+		return "", fmt.Errorf("invalid bond address signature: %v", err)
+	}
+	return signer, nil
+}
+
+type PenaltyStatus string
+
+const (
+	PenaltyPending PenaltyStatus = "pending"
+	PenaltySlashed PenaltyStatus = "slashed"
+	PenaltyWaived  PenaltyStatus = "waived"
+)
+
+// PenaltyFillerDefaulted is the reason of the penalties of fillers who never
+// initiated an order they filled after its maker did
+const PenaltyFillerDefaulted = "filler did not initiate after the maker"
+
+// Penalty is recorded against a filler who failed an order it filled, it is
+// pending until an admin reviews it and slashes the bond of the filler or waives it
+type Penalty struct {
+	gorm.Model
+
+	Filler    string        `json:"filler" gorm:"size:255;index"`
+	OrderID   uint          `json:"orderId" gorm:"uniqueIndex"`
+	Reason    string        `json:"reason"`
+	Status    PenaltyStatus `json:"status" gorm:"size:16;index"`
+	Amount    string        `json:"amount" gorm:"size:78"`
+	SettledBy string        `json:"settledBy,omitempty"`
+	SettledAt *time.Time    `json:"settledAt,omitempty"`
+	Note      string        `json:"note,omitempty"`
+}
+
+// FillerDefaulted reports whether the order failed as its filler never initiated
+// the follower swap after the maker initiated. Orders cancelled as their maker
+// never initiated are not the fault of the filler.
+func (order Order) FillerDefaulted() bool {
+	if order.Status != FailedSoft || order.Taker == "" || order.InitiatorAtomicSwap == nil || order.FollowerAtomicSwap == nil {
+		return false
+	}
+	return order.InitiatorAtomicSwap.Status != NotStarted && order.FollowerAtomicSwap.Status == NotStarted
+}
@@ -0,0 +1,46 @@
+package model_test
+
+import (
+	"strings"
+
+	. "github.com/catalogfi/orderbook/model"
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bonds", func() {
+	It("should only blame the filler when the maker initiated", func() {
+		order := func(status Status, initiator, follower SwapStatus) Order {
+			return Order{
+				Status:              status,
+				Taker:               "0x3cb762058f019c3abcd5e4a07957ee996ee319bd",
+				InitiatorAtomicSwap: &AtomicSwap{Status: initiator},
+				FollowerAtomicSwap:  &AtomicSwap{Status: follower},
+			}
+		}
+		Expect(order(FailedSoft, Refunded, NotStarted).FillerDefaulted()).To(BeTrue())
+		Expect(order(FailedSoft, Refunded, Refunded).FillerDefaulted()).To(BeFalse())
+		Expect(order(Cancelled, NotStarted, NotStarted).FillerDefaulted()).To(BeFalse())
+		Expect(order(Filled, Initiated, NotStarted).FillerDefaulted()).To(BeFalse())
+	})
+
+	It("should recover the bond address which signed for the filler", func() {
+		filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+		key, err := crypto.GenerateKey()
+		Expect(err).NotTo(HaveOccurred())
+		signature, err := SignBondAddress(filler, key)
+		Expect(err).NotTo(HaveOccurred())
+
+		// the signature only lets the filler it was made for register the address
+		signer, err := BondAddressSigner("0xe103abfa0f867e53cef1ad2cb0dcbc193b385a93", signature)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signer).NotTo(Equal(strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())))
+		signer, err = BondAddressSigner(strings.ToUpper(filler), signature)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signer).To(Equal(strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())))
+
+		_, err = BondAddressSigner(filler, "0x1234")
+		Expect(err).To(HaveOccurred())
+	})
+})
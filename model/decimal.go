@@ -0,0 +1,206 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DecimalPlaces is the number of fractional digits kept by a Decimal
+const DecimalPlaces = 18
+
+var decimalScale = new(big.Int).Exp(big.NewInt(10), big.NewInt(DecimalPlaces), nil)
+
+// Decimal is a fixed-point decimal number with DecimalPlaces fractional digits,
+// results with more digits are rounded half away from zero. The zero value is 0.
+type Decimal struct {
+	// value multiplied by 10^DecimalPlaces
+	scaled *big.Int
+}
+
+// NewDecimal parses a decimal string, i.e. "42000.5" or "1e-3"
+func NewDecimal(value string) (Decimal, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal: %q", value)
+	}
+	return Decimal{scaled: roundQuo(new(big.Int).Mul(r.Num(), decimalScale), r.Denom())}, nil
+}
+
+// NewDecimalFromFloat converts a float64 using its shortest decimal representation,
+// NaN and infinities are converted to 0
+func NewDecimalFromFloat(value float64) Decimal {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Decimal{}
+	}
+	d, _ := NewDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+	return d
+}
+
+// NewDecimalFromBigInt converts an integer amount of an asset with the given decimals,
+// i.e. 150000000 with 8 decimals is 1.5
+func NewDecimalFromBigInt(value *big.Int, decimals int64) Decimal {
+	if value == nil {
+		return Decimal{}
+	}
+	if decimals <= DecimalPlaces {
+		multiplier := new(big.Int).Exp(big.NewInt(10), big.NewInt(DecimalPlaces-decimals), nil)
+		return Decimal{scaled: new(big.Int).Mul(value, multiplier)}
+	}
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals-DecimalPlaces), nil)
+	return Decimal{scaled: roundQuo(value, divisor)}
+}
+
+func (d Decimal) value() *big.Int {
+	if d.scaled == nil {
+		return new(big.Int)
+	}
+	return d.scaled
+}
+
+func (d Decimal) Add(y Decimal) Decimal {
+	return Decimal{scaled: new(big.Int).Add(d.value(), y.value())}
+}
+
+func (d Decimal) Sub(y Decimal) Decimal {
+	return Decimal{scaled: new(big.Int).Sub(d.value(), y.value())}
+}
+
+func (d Decimal) Mul(y Decimal) Decimal {
+	return Decimal{scaled: roundQuo(new(big.Int).Mul(d.value(), y.value()), decimalScale)}
+}
+
+func (d Decimal) Quo(y Decimal) (Decimal, error) {
+	if y.Sign() == 0 {
+		return Decimal{}, errors.New("decimal division by zero")
+	}
+	return Decimal{scaled: roundQuo(new(big.Int).Mul(d.value(), decimalScale), y.value())}, nil
+}
+
+func (d Decimal) Cmp(y Decimal) int {
+	return d.value().Cmp(y.value())
+}
+
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// Floor returns the largest integer less than or equal to the decimal
+func (d Decimal) Floor() *big.Int {
+	// euclidean division rounds towards negative infinity for a positive divisor
+	return new(big.Int).Div(d.value(), decimalScale)
+}
+
+// Float64 returns the nearest float64, for display and legacy apis only
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns the decimal without trailing fractional zeros
+func (d Decimal) String() string {
+	abs := new(big.Int).Abs(d.value())
+	integer, fraction := new(big.Int).QuoRem(abs, decimalScale, new(big.Int))
+	str := integer.String()
+	if fraction.Sign() != 0 {
+		digits := fraction.String()
+		digits = strings.Repeat("0", DecimalPlaces-len(digits)) + digits
+		str += "." + strings.TrimRight(digits, "0")
+	}
+	if d.Sign() < 0 {
+		str = "-" + str
+	}
+	return str
+}
+
+// Scan implements the sql.Scanner interface
+func (d *Decimal) Scan(value interface{}) error {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		*d = Decimal{}
+	case float64:
+		*d = NewDecimalFromFloat(v)
+	case int64:
+		*d = NewDecimalFromBigInt(big.NewInt(v), 0)
+	case []byte:
+		*d, err = scanDecimal(string(v))
+	case string:
+		*d, err = scanDecimal(v)
+	default:
+		return fmt.Errorf("unsupported type for Decimal: %T", value)
+	}
+	return err
+}
+
+func scanDecimal(value string) (Decimal, error) {
+	if value == "" {
+		return Decimal{}, nil
+	}
+	return NewDecimal(value)
+}
+
+// Value implements the driver.Valuer interface
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// GormDBDataType stores decimals as exact numerics
+func (Decimal) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "mysql":
+		return fmt.Sprintf("decimal(65,%d)", DecimalPlaces)
+	default:
+		return "numeric"
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface, decimals are encoded as
+// json numbers to stay compatible with the float64 fields they replace
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, both json numbers
+// and strings are accepted
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		number = json.Number(str)
+	}
+	if number == "" {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := NewDecimal(string(number))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// rounds n / m half away from zero
+func roundQuo(n, m *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(n, m, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(new(big.Int).Abs(m)) >= 0 {
+		if (n.Sign() < 0) != (m.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
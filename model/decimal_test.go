@@ -0,0 +1,92 @@
+package model_test
+
+import (
+	"encoding/json"
+	"math/big"
+
+	. "github.com/catalogfi/orderbook/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func decimal(value string) Decimal {
+	d, err := NewDecimal(value)
+	Expect(err).NotTo(HaveOccurred())
+	return d
+}
+
+var _ = Describe("Decimal", func() {
+	It("should parse and print decimals", func() {
+		Expect(decimal("42000.50").String()).To(Equal("42000.5"))
+		Expect(decimal("-0.000000000000000001").String()).To(Equal("-0.000000000000000001"))
+		Expect(decimal("1e-3").String()).To(Equal("0.001"))
+		Expect(Decimal{}.String()).To(Equal("0"))
+		_, err := NewDecimal("1,000")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should convert asset amounts exactly", func() {
+		Expect(NewDecimalFromBigInt(big.NewInt(150000000), 8).String()).To(Equal("1.5"))
+		amount, _ := new(big.Int).SetString("1234567890123456789012", 10)
+		Expect(NewDecimalFromBigInt(amount, 18).String()).To(Equal("1234.567890123456789012"))
+		Expect(NewDecimalFromFloat(0.1).String()).To(Equal("0.1"))
+	})
+
+	It("should do exact arithmetic", func() {
+		// 0.1 + 0.2 is not 0.3 with float64
+		Expect(decimal("0.1").Add(decimal("0.2")).Cmp(decimal("0.3"))).To(Equal(0))
+		Expect(decimal("1.5").Sub(decimal("2")).String()).To(Equal("-0.5"))
+		Expect(decimal("42000.12").Mul(decimal("123.456789")).String()).To(Equal("5185199.95281468"))
+
+		third, err := decimal("1").Quo(decimal("3"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(third.String()).To(Equal("0.333333333333333333"))
+		twoThirds, err := decimal("2").Quo(decimal("3"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(twoThirds.String()).To(Equal("0.666666666666666667"))
+		_, err = decimal("1").Quo(Decimal{})
+		Expect(err).To(HaveOccurred())
+
+		Expect(decimal("99.99").Floor().Int64()).To(Equal(int64(99)))
+		Expect(decimal("-0.5").Floor().Int64()).To(Equal(int64(-1)))
+	})
+
+	It("should scan and value sql columns", func() {
+		d := Decimal{}
+		for _, column := range []struct {
+			value    interface{}
+			expected string
+		}{
+			{"42000.12", "42000.12"},
+			{[]byte("0.000001"), "0.000001"},
+			{float64(0.1), "0.1"},
+			{int64(7), "7"},
+			{nil, "0"},
+		} {
+			Expect(d.Scan(column.value)).To(Succeed())
+			Expect(d.String()).To(Equal(column.expected))
+		}
+		Expect(d.Scan(true)).NotTo(Succeed())
+
+		value, err := decimal("1.25").Value()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("1.25"))
+	})
+
+	It("should marshal to json numbers and unmarshal numbers and strings", func() {
+		data, err := json.Marshal(struct {
+			Price Decimal `json:"price"`
+		}{decimal("0.000000000000000001")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{"price":0.000000000000000001}`))
+
+		var res struct {
+			Price Decimal `json:"price"`
+		}
+		Expect(json.Unmarshal(data, &res)).To(Succeed())
+		Expect(res.Price.String()).To(Equal("0.000000000000000001"))
+		Expect(json.Unmarshal([]byte(`{"price":"12.5"}`), &res)).To(Succeed())
+		Expect(res.Price.String()).To(Equal("12.5"))
+		Expect(json.Unmarshal([]byte(`{"price":"abc"}`), &res)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Filler is the profile of a filler and its reputation from the orders it filled.
+// Fillers register their profile, the reputation of every wallet which filled an
+// order is scored periodically whether it registered or not.
+type Filler struct {
+	gorm.Model
+
+	Address    string      `json:"address" gorm:"size:255;uniqueIndex"`
+	Name       string      `json:"name"`
+	Pairs      StringArray `json:"pairs"`
+	Contact    string      `json:"contact"`
+	Registered bool        `json:"registered"`
+	// address holding the bond of the filler, the filler itself when empty
+	BondAddress string `json:"bondAddress,omitempty"`
+	// bond of the filler when it was last verified on chain
+	Bond           string     `json:"bond,omitempty" gorm:"size:78"`
+	BondVerifiedAt *time.Time `json:"bondVerifiedAt,omitempty"`
+
+	// orders filled and how they ended, cancelled orders were not initiated by
+	// their maker
+	Fills      uint64 `json:"fills"`
+	Executed   uint64 `json:"executed"`
+	FailedSoft uint64 `json:"failedSoft"`
+	FailedHard uint64 `json:"failedHard"`
+	Cancelled  uint64 `json:"cancelled"`
+	// fraction of the finished orders which were executed
+	FillRate float64 `json:"fillRate"`
+	// median seconds between the initiation of the maker and of the filler
+	MedianTimeToInitiate int64      `json:"medianTimeToInitiate"`
+	Score                uint64     `json:"score"`
+	ScoredAt             *time.Time `json:"scoredAt,omitempty"`
+}
+
+// FillerScore is the reputation of a filler with the given number of executed
+// and failed orders, the weight it has in lotteries
+func FillerScore(executed, failedSoft, failedHard uint64) uint64 {
+	return LotteryWeight(executed, failedSoft+failedHard)
+}
+
+// FillRate is the fraction of the finished orders of a filler which were
+// executed, fillers without finished orders have a fill rate of 1
+func FillRate(executed, failedSoft, failedHard uint64) float64 {
+	finished := executed + failedSoft + failedHard
+	if finished == 0 {
+		return 1
+	}
+	return float64(executed) / float64(finished)
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxInventoryClockSkew is how far in the future a signed inventory can be
+// published at
+const MaxInventoryClockSkew = time.Minute
+
+type InventorySource string
+
+const (
+	// the amount is published by the filler in a signed update
+	InventorySigned InventorySource = "signed"
+	// the amount is the balance of an address of the filler read on chain
+	InventoryOnChain InventorySource = "onchain"
+)
+
+// InventoryCheck is what happens to orders no registered filler can cover
+type InventoryCheck string
+
+const (
+	InventoryWarn   InventoryCheck = "warn"
+	InventoryReject InventoryCheck = "reject"
+)
+
+// Inventory is the amount of an asset a filler has available to send on a chain
+type Inventory struct {
+	gorm.Model
+
+	Filler string          `json:"filler" gorm:"size:255;uniqueIndex:idx_inventories_filler_chain_asset"`
+	Chain  Chain           `json:"chain" gorm:"size:64;uniqueIndex:idx_inventories_filler_chain_asset"`
+	Asset  Asset           `json:"asset" gorm:"size:255;uniqueIndex:idx_inventories_filler_chain_asset"`
+	Source InventorySource `json:"source" gorm:"size:16"`
+	// address whose balance is read on chain
+	Address string `json:"address,omitempty"`
+	Amount  string `json:"amount" gorm:"size:78"`
+	// when the filler signed the amount or its balance was read
+	PublishedAt *time.Time `json:"publishedAt,omitempty" gorm:"index"`
+	Signature   string     `json:"signature,omitempty"`
+}
+
+// Liquidity is what the registered fillers have available of the asset an order
+// pair receives
+type Liquidity struct {
+	OrderPair string `json:"orderPair"`
+	// the sum of the inventories of the fillers
+	Amount string `json:"amount"`
+	// the largest inventory of a filler, the largest order one filler can cover
+	Largest string `json:"largest"`
+	Fillers int    `json:"fillers"`
+}
+
+// Message is the message a filler signs to publish its inventory
+func (inventory Inventory) Message() string {
+	publishedAt := int64(0)
+	if inventory.PublishedAt != nil {
+		publishedAt = inventory.PublishedAt.Unix()
+	}
+	return fmt.Sprintf("Orderbook inventory\nChain: %s\nAsset: %s\nAmount: %s\nPublished at: %d", inventory.Chain, inventory.Asset, inventory.Amount, publishedAt)
+}
+
+// Sign signs the inventory with an ethereum personal signature of its message
+func (inventory Inventory) Sign(key *ecdsa.PrivateKey) (string, error) {
+	return signMessage(inventory.Message(), key)
+}
+
+// Signer returns the lower case address which signed the inventory
+func (inventory Inventory) Signer() (string, error) {
+	signer, err := recoverSigner(inventory.Message(), inventory.Signature)
+	if err != nil {
+		return "", fmt.Errorf("invalid inventory signature: %v", err)
+	}
+	return signer, nil
+}
+
+// FillerInventories returns what each of the fillers has available of the asset
+// the order pair receives. Fillers which listed the pairs they fill only count
+// for those pairs.
+func FillerInventories(orderPair string, fillers []Filler, inventories []Inventory) (map[string]*big.Int, error) {
+	_, receiveChain, _, receiveAsset, err := ParseOrderPair(orderPair)
+	if err != nil {
+		return nil, err
+	}
+	fills := map[string]bool{}
+	for _, filler := range fillers {
+		if len(filler.Pairs) == 0 {
+			fills[filler.Address] = true
+			continue
+		}
+		for _, pair := range filler.Pairs {
+			if strings.EqualFold(pair, orderPair) {
+				fills[filler.Address] = true
+			}
+		}
+	}
+	available := map[string]*big.Int{}
+	for _, inventory := range inventories {
+		if !fills[inventory.Filler] || inventory.Chain != receiveChain || !strings.EqualFold(string(inventory.Asset), string(receiveAsset)) {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(inventory.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid inventory amount %s", inventory.Amount)
+		}
+		available[inventory.Filler] = amount
+	}
+	return available, nil
+}
+
+// PairLiquidity sums what the fillers have available for an order pair
+func PairLiquidity(orderPair string, available map[string]*big.Int) Liquidity {
+	amount, largest := big.NewInt(0), big.NewInt(0)
+	fillers := 0
+	for _, inventory := range available {
+		if inventory.Sign() <= 0 {
+			continue
+		}
+		fillers++
+		amount.Add(amount, inventory)
+		if inventory.Cmp(largest) > 0 {
+			largest.Set(inventory)
+		}
+	}
+	return Liquidity{OrderPair: orderPair, Amount: amount.String(), Largest: largest.String(), Fillers: fillers}
+}
+
+// CanCover reports whether one of the fillers, or one of the allowed fillers of a
+// private order, has the amount available
+func CanCover(available map[string]*big.Int, amount *big.Int, allowedFillers []string) bool {
+	for filler, inventory := range available {
+		if len(allowedFillers) > 0 && !containsFold(allowedFillers, filler) {
+			continue
+		}
+		if inventory.Cmp(amount) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderPairs returns every order pair between the assets of two different chains
+// of the network, sorted
+func (network Network) OrderPairs() []string {
+	chainAssets := []string{}
+	chains := map[string]Chain{}
+	for chain, config := range network {
+		for asset := range config.Assets {
+			chainAsset := string(chain)
+			if asset != Primary {
+				chainAsset += ":" + string(asset)
+			}
+			chainAssets = append(chainAssets, chainAsset)
+			chains[chainAsset] = chain
+		}
+	}
+	pairs := []string{}
+	for _, from := range chainAssets {
+		for _, to := range chainAssets {
+			if chains[from] != chains[to] {
+				pairs = append(pairs, from+"-"+to)
+			}
+		}
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package model_test
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	. "github.com/catalogfi/orderbook/model"
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Inventory", func() {
+	token := "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"
+	pair := "bitcoin_testnet-ethereum_sepolia:" + token
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	otherFiller := "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0"
+
+	It("should be signed by the filler", func() {
+		key, err := crypto.GenerateKey()
+		Expect(err).NotTo(HaveOccurred())
+		publishedAt := time.Unix(time.Now().Unix(), 0)
+		inventory := Inventory{Chain: EthereumSepolia, Asset: NewSecondary(token), Amount: "100", PublishedAt: &publishedAt}
+		inventory.Signature, err = inventory.Sign(key)
+		Expect(err).NotTo(HaveOccurred())
+		signer, err := inventory.Signer()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signer).To(Equal(strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())))
+
+		inventory.Amount = "1000"
+		signer, err = inventory.Signer()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signer).NotTo(Equal(strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())))
+	})
+
+	It("should count the inventories of the fillers of a pair", func() {
+		fillers := []Filler{{Address: filler}, {Address: otherFiller, Pairs: StringArray{"ethereum_sepolia:" + token + "-bitcoin_testnet"}}}
+		inventories := []Inventory{
+			{Filler: filler, Chain: EthereumSepolia, Asset: NewSecondary(strings.ToLower(token)), Amount: "70"},
+			{Filler: filler, Chain: BitcoinTestnet, Asset: Primary, Amount: "1000"},
+			{Filler: otherFiller, Chain: EthereumSepolia, Asset: NewSecondary(token), Amount: "500"},
+		}
+		available, err := FillerInventories(pair, fillers, inventories)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(available).To(HaveLen(1))
+		Expect(available[filler].Int64()).To(Equal(int64(70)))
+		Expect(CanCover(available, big.NewInt(70), nil)).To(BeTrue())
+		Expect(CanCover(available, big.NewInt(71), nil)).To(BeFalse())
+		Expect(CanCover(available, big.NewInt(70), []string{otherFiller})).To(BeFalse())
+
+		fillers[1].Pairs = nil
+		available, err = FillerInventories(pair, fillers, inventories)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(PairLiquidity(pair, available)).To(Equal(Liquidity{OrderPair: pair, Amount: "570", Largest: "500", Fillers: 2}))
+	})
+
+	It("should list the pairs between chains", func() {
+		network := Network{
+			BitcoinTestnet:  NetworkConfig{Assets: map[Asset]Token{Primary: {}}},
+			EthereumSepolia: NetworkConfig{Assets: map[Asset]Token{NewSecondary(token): {}}},
+		}
+		Expect(network.OrderPairs()).To(Equal([]string{pair, "ethereum_sepolia:" + token + "-bitcoin_testnet"}))
+	})
+})
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxLotteryWindow is how long fillers can register their intent to fill a
+// lottery order at most
+const MaxLotteryWindow = time.Minute
+
+// FillIntent is the intent of a filler to fill a lottery order with the given
+// addresses. The weight and score of the filler are set when the lottery is drawn.
+type FillIntent struct {
+	gorm.Model
+
+	OrderID        uint   `json:"orderId" gorm:"uniqueIndex:idx_fill_intents_order_filler"`
+	Filler         string `json:"filler" gorm:"size:255;uniqueIndex:idx_fill_intents_order_filler"`
+	SendAddress    string `json:"sendAddress"`
+	ReceiveAddress string `json:"receiveAddress"`
+	Weight         uint64 `json:"weight"`
+	Score          uint64 `json:"score"`
+	Won            bool   `json:"won"`
+}
+
+// LotterySeedHash is the commitment to the seed of a lottery published when the
+// order is created, so that the seed revealed after the draw can be audited
+func LotterySeedHash(seed string) (string, error) {
+	seedBytes, err := hex.DecodeString(seed)
+	if err != nil {
+		return "", fmt.Errorf("invalid lottery seed: %v", err)
+	}
+	hash := sha256.Sum256(seedBytes)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// LotteryScore is the random score of a filler, the first 63 bits of the sha256
+// of the seed followed by the lowercase address of the filler, which fit the
+// signed integer columns of any database
+func LotteryScore(seed, filler string) (uint64, error) {
+	seedBytes, err := hex.DecodeString(seed)
+	if err != nil {
+		return 0, fmt.Errorf("invalid lottery seed: %v", err)
+	}
+	hash := sha256.Sum256(append(seedBytes, []byte(strings.ToLower(filler))...))
+	return binary.BigEndian.Uint64(hash[:8]) >> 1, nil
+}
+
+// LotteryWeight is the reputation of a filler with the given number of executed
+// and failed orders, between 1 and 100. New fillers start at 50 and only approach
+// 100 as they execute orders, so that a new address does not outweigh fillers
+// with a record.
+func LotteryWeight(executed, failed uint64) uint64 {
+	weight := 100 * (executed + 1) / (executed + failed + 2)
+	if weight == 0 {
+		return 1
+	}
+	return weight
+}
+
+// DrawLottery scores the intents with the seed and ranks them, the winner first.
+// The order is assigned to the first of them who can fill it.
+// A filler wins with a probability proportional to its weight: the score is a
+// uniform number u in (0, 1] and the filler with the highest u^(1/weight) wins.
+func DrawLottery(seed string, intents []FillIntent) ([]FillIntent, error) {
+	type entry struct {
+		intent FillIntent
+		key    float64
+	}
+	entries := make([]entry, len(intents))
+	for i, intent := range intents {
+		score, err := LotteryScore(seed, intent.Filler)
+		if err != nil {
+			return nil, err
+		}
+		if intent.Weight == 0 {
+			return nil, fmt.Errorf("filler %s has no weight", intent.Filler)
+		}
+		intent.Score = score
+		u := (float64(score) + 1) / math.Exp2(63)
+		entries[i] = entry{intent: intent, key: math.Log(u) / float64(intent.Weight)}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].key != entries[j].key {
+			return entries[i].key > entries[j].key
+		}
+		return entries[i].intent.Filler < entries[j].intent.Filler
+	})
+	ranked := make([]FillIntent, len(entries))
+	for i, entry := range entries {
+		ranked[i] = entry.intent
+	}
+	return ranked, nil
+}
+
+// InLottery reports whether the order is assigned by a lottery which has not been
+// drawn yet
+func (order Order) InLottery() bool {
+	return order.LotteryEndsAt != nil && order.LotterySeed == ""
+}
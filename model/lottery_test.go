@@ -0,0 +1,71 @@
+package model_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	. "github.com/catalogfi/orderbook/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lotteries", func() {
+	seed := "5d1b6c6f0d3a6a2b7d1e9c4f8a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d"
+
+	It("should commit to the seed by its hash", func() {
+		seedHash, err := LotterySeedHash(seed)
+		Expect(err).NotTo(HaveOccurred())
+		seedBytes, _ := hex.DecodeString(seed)
+		hash := sha256.Sum256(seedBytes)
+		Expect(seedHash).To(Equal(hex.EncodeToString(hash[:])))
+		_, err = LotterySeedHash("not hex")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should weigh fillers by their executed and failed orders", func() {
+		Expect(LotteryWeight(0, 0)).To(Equal(uint64(50)))
+		Expect(LotteryWeight(1, 0)).To(Equal(uint64(66)))
+		Expect(LotteryWeight(9, 0)).To(Equal(uint64(90)))
+		Expect(LotteryWeight(99, 0)).To(Equal(uint64(99)))
+		Expect(LotteryWeight(1, 1)).To(Equal(uint64(50)))
+		Expect(LotteryWeight(0, 1000)).To(Equal(uint64(1)))
+	})
+
+	It("should rank the fillers reproducibly from the seed", func() {
+		intents := []FillIntent{
+			{Filler: "0x3cb762058f019c3abcd5e4a07957ee996ee319bd", Weight: 100},
+			{Filler: "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0", Weight: 100},
+			{Filler: "0x17100301bb2ff58ae6b5ca5b8f9ec6f872e0f2da", Weight: 100},
+		}
+		ranked, err := DrawLottery(seed, intents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ranked).To(HaveLen(3))
+		for _, intent := range ranked {
+			score, err := LotteryScore(seed, intent.Filler)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(intent.Score).To(Equal(score))
+		}
+		again, err := DrawLottery(seed, []FillIntent{intents[2], intents[0], intents[1]})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again).To(Equal(ranked))
+
+		intents[0].Weight = 0
+		_, err = DrawLottery(seed, intents)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should pick fillers in proportion to their weight", func() {
+		wins := map[string]int{}
+		for i := 0; i < 2000; i++ {
+			seed := sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+			ranked, err := DrawLottery(hex.EncodeToString(seed[:]), []FillIntent{
+				{Filler: "0x3cb762058f019c3abcd5e4a07957ee996ee319bd", Weight: 100},
+				{Filler: "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0", Weight: 25},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			wins[ranked[0].Filler]++
+		}
+		// the first filler wins 4 out of 5 draws on average
+		Expect(wins["0x3cb762058f019c3abcd5e4a07957ee996ee319bd"]).To(BeNumerically("~", 1600, 100))
+	})
+})
@@ -26,8 +26,50 @@ type Config struct {
 	Network    Network
 	MinTxLimit string
 	MaxTxLimit string
-	DailyLimit string
 	PriceTTL   int64
+	// maximum fraction a price source may deviate from the median of all sources
+	PriceDeviation float64
+	// limits in USD on the value sent by a user over rolling windows of
+	// 24 hours, 7 days and 30 days, empty limits are not enforced
+	DailyUSDLimit   string
+	WeeklyUSDLimit  string
+	MonthlyUSDLimit string
+	// wallets allowed to use the admin api
+	Admins []string
+	// seconds a response to a request with an idempotency key is replayed, defaults to a day
+	IdempotencyTTL int64
+	// seconds a request with an idempotency key is reserved while in progress before
+	// a retry can take it over, defaults to a minute
+	IdempotencyLockTimeout int64
+	// seconds between rollups of the order statistics, defaults to a minute
+	RollupInterval int64
+	// seconds between snapshots of the value locked, defaults to five minutes
+	SnapshotInterval int64
+	// wallets allowed to quote requests for quotes
+	Fillers []string
+	// seconds fillers can quote a request for quote, defaults to ten seconds
+	QuoteWindow int64
+	// seconds fillers can register their intent to fill a lottery order, defaults
+	// to five seconds
+	LotteryWindow int64
+	// seconds between scorings of the fillers, defaults to ten minutes
+	ScoreInterval int64
+	// fillers scored below are not allowed to fill orders, not enforced when zero
+	MinFillerScore uint64
+	// score the minimum filler score is checked against for fillers which have not
+	// been scored yet, defaults to the score of a filler without finished orders
+	NewFillerScore uint64
+	// bond fillers post to fill orders
+	Bond BondConfig
+	// seconds the inventory of a filler counts as available after it was published,
+	// defaults to ten minutes
+	InventoryTTL int64
+	// seconds between readings of the inventories of the fillers on chain, defaults
+	// to a minute
+	InventoryInterval int64
+	// whether orders no registered filler can cover are created with a warning or
+	// rejected, not checked when empty
+	InventoryCheck InventoryCheck
 }
 
 type Chain string
@@ -116,7 +158,9 @@ func (c Chain) IsTestnet() bool {
 }
 
 type Token struct {
-	Oracle       string
+	Oracle string
+	// additional price sources aggregated with the oracle, see price.ParseOracle
+	Oracles      []string
 	TokenAddress string
 	Decimals     int64
 	StartBlock   uint64
@@ -164,6 +208,128 @@ const (
 	Refunded
 )
 
+// OrderType is how an order is priced and how long it can be filled for
+type OrderType string
+
+const (
+	// market orders are cancelled unless they are filled shortly after creation
+	MarketOrder OrderType = "market"
+	// limit orders rest on the book until they are filled, cancelled or expire
+	LimitOrder OrderType = "limit"
+	// auction orders receive less the longer they are not filled and expire when
+	// the auction ends
+	AuctionOrder OrderType = "auction"
+)
+
+// MaxLimitOrderExpiry is how long after creation a limit order can expire at most
+const MaxLimitOrderExpiry = 30 * 24 * time.Hour
+
+// MaxAuctionDuration is how long an auction order can run at most
+const MaxAuctionDuration = 24 * time.Hour
+
+func ParseOrderType(orderType string) (OrderType, error) {
+	switch OrderType(orderType) {
+	case "":
+		return MarketOrder, nil
+	case MarketOrder, LimitOrder, AuctionOrder:
+		return OrderType(orderType), nil
+	default:
+		return "", fmt.Errorf("unknown order type %v, expected %s, %s or %s", orderType, MarketOrder, LimitOrder, AuctionOrder)
+	}
+}
+
+// OrderTerms are the terms a maker creates an order with besides its amounts.
+// The receive amount of an auction order is the amount it starts at.
+type OrderTerms struct {
+	Type      OrderType
+	ExpiresAt time.Time
+
+	AuctionEndAmount *big.Int
+	AuctionDuration  time.Duration
+
+	// the secret hashes of the parts of a partially fillable order, one for each
+	// child order filling a part of it
+	PartSecretHashes []string
+
+	// the accepted quote of an order created from a request for quote, the order
+	// can only be filled by the filler who quoted it
+	Quote *Quote
+
+	// the fillers a private order is restricted to, anyone can fill an order
+	// without allowed fillers
+	AllowedFillers []string
+
+	// lottery orders are assigned to one of the fillers who register their intent
+	// to fill them within the window after creation
+	LotteryWindow time.Duration
+}
+
+// Validate checks the terms of an order created at the given time which receives
+// the given amount, the zero terms are a market order. Auction orders expire when
+// their auction ends.
+func (terms OrderTerms) Validate(now time.Time, receiveAmount *big.Int) (OrderTerms, error) {
+	orderType, err := ParseOrderType(string(terms.Type))
+	if err != nil {
+		return OrderTerms{}, err
+	}
+	terms.Type = orderType
+	if terms.Type != AuctionOrder && (terms.AuctionEndAmount != nil || terms.AuctionDuration != 0) {
+		return OrderTerms{}, fmt.Errorf("only auction orders can have an auction")
+	}
+	switch terms.Type {
+	case LimitOrder:
+		if !terms.ExpiresAt.After(now) {
+			return OrderTerms{}, fmt.Errorf("limit order has to expire in the future")
+		}
+		if terms.ExpiresAt.Sub(now) > MaxLimitOrderExpiry {
+			return OrderTerms{}, fmt.Errorf("limit order has to expire within %s", MaxLimitOrderExpiry)
+		}
+	case AuctionOrder:
+		if !terms.ExpiresAt.IsZero() {
+			return OrderTerms{}, fmt.Errorf("auction orders expire when the auction ends")
+		}
+		if terms.AuctionDuration <= 0 || terms.AuctionDuration > MaxAuctionDuration {
+			return OrderTerms{}, fmt.Errorf("auction has to run for at most %s", MaxAuctionDuration)
+		}
+		if terms.AuctionEndAmount == nil || terms.AuctionEndAmount.Sign() <= 0 {
+			return OrderTerms{}, fmt.Errorf("invalid auction end amount")
+		}
+		if terms.AuctionEndAmount.Cmp(receiveAmount) >= 0 {
+			return OrderTerms{}, fmt.Errorf("auction has to end below the receive amount it starts at")
+		}
+		terms.ExpiresAt = now.Add(terms.AuctionDuration)
+	default:
+		if !terms.ExpiresAt.IsZero() {
+			return OrderTerms{}, fmt.Errorf("only limit orders can have an expiry")
+		}
+	}
+	if len(terms.PartSecretHashes) > MaxOrderParts {
+		return OrderTerms{}, fmt.Errorf("order can be filled in at most %d parts", MaxOrderParts)
+	}
+	if len(terms.PartSecretHashes) > 0 && terms.Type == AuctionOrder {
+		return OrderTerms{}, fmt.Errorf("auction orders cannot be filled partially")
+	}
+	if terms.Quote != nil && (terms.Type == AuctionOrder || len(terms.PartSecretHashes) > 0) {
+		return OrderTerms{}, fmt.Errorf("quoted orders cannot be auctioned or filled partially")
+	}
+	if len(terms.AllowedFillers) > MaxAllowedFillers {
+		return OrderTerms{}, fmt.Errorf("order can be restricted to at most %d fillers", MaxAllowedFillers)
+	}
+	if terms.Quote != nil && len(terms.AllowedFillers) > 0 {
+		return OrderTerms{}, fmt.Errorf("quoted orders are already restricted to the filler who quoted")
+	}
+	if terms.LotteryWindow < 0 || terms.LotteryWindow > MaxLotteryWindow {
+		return OrderTerms{}, fmt.Errorf("lottery window has to be at most %s", MaxLotteryWindow)
+	}
+	if terms.LotteryWindow > 0 && (terms.Quote != nil || len(terms.PartSecretHashes) > 0) {
+		return OrderTerms{}, fmt.Errorf("quoted and partially fillable orders cannot be assigned by lottery")
+	}
+	if terms.LotteryWindow > 0 && !terms.ExpiresAt.IsZero() && !terms.ExpiresAt.After(now.Add(terms.LotteryWindow)) {
+		return OrderTerms{}, fmt.Errorf("order has to expire after the lottery window")
+	}
+	return terms, nil
+}
+
 type VerifySiwe struct {
 	Message   string `json:"message" binding:"required"`
 	Signature string `json:"signature" binding:"required"`
@@ -183,7 +349,7 @@ type Order struct {
 
 	SecretHash           string  `json:"secretHash" gorm:"unique;not null"`
 	Secret               string  `json:"secret"`
-	Price                float64 `json:"price"`
+	Price                Decimal `json:"price"`
 	Status               Status  `json:"status"`
 	SecretNonce          uint64  `json:"secretNonce"`
 	UserBtcWalletAddress string  `json:"userBtcWalletAddress"`
@@ -193,9 +359,48 @@ type Order struct {
 	RandomMultiplier     uint64
 	RandomScore          uint64
 
+	Type      OrderType     `json:"type" gorm:"size:16;default:market"`
+	ExpiresAt *time.Time    `json:"expiresAt,omitempty"`
+	FilledAt  *time.Time    `json:"filledAt,omitempty"`
+	Auction   *AuctionCurve `json:"auction,omitempty" gorm:"embedded;embeddedPrefix:auction_"`
+
+	// partially fillable orders are filled by child orders, each filling a part of
+	// the send amount with the next of the secret hashes committed to by the maker
+	Partial          bool        `json:"partial"`
+	ParentOrderID    *uint       `json:"parentOrderId,omitempty" gorm:"index"`
+	FilledAmount     string      `json:"filledAmount,omitempty" gorm:"size:78"`
+	RemainingAmount  string      `json:"remainingAmount,omitempty" gorm:"size:78"`
+	PartSecretHashes StringArray `json:"-"`
+	Children         []Order     `json:"children,omitempty" gorm:"foreignKey:ParentOrderID"`
+
+	// the quote an order was created from
+	QuoteID *uint `json:"quoteId,omitempty"`
+
+	// private orders can only be filled by one of their allowed fillers and are
+	// not broadcast as open orders
+	AllowedFillers StringArray `json:"allowedFillers,omitempty"`
+
+	// lottery orders are drawn when the lottery ends among the fillers who
+	// registered their intent, with the seed committed to by its hash at creation.
+	// RandomScore and RandomMultiplier are the score and weight of the winner.
+	LotteryEndsAt   *time.Time `json:"lotteryEndsAt,omitempty"`
+	LotterySeedHash string     `json:"lotterySeedHash,omitempty" gorm:"size:64"`
+	LotterySeed     string     `json:"lotterySeed,omitempty" gorm:"size:64"`
+	LotterySecret   string     `json:"-" gorm:"size:64"`
+
 	Fee uint `json:"fee"`
 }
 
+// IsExpired reports whether a created order can no longer be filled, limit and
+// auction orders expire at their expiry and other orders the given timeout after
+// creation
+func (order Order) IsExpired(now time.Time, timeout time.Duration) bool {
+	if order.ExpiresAt != nil {
+		return !now.Before(*order.ExpiresAt)
+	}
+	return now.Sub(order.CreatedAt) > timeout
+}
+
 type AtomicSwap struct {
 	gorm.Model
 
@@ -213,10 +418,12 @@ type AtomicSwap struct {
 	InitiateTxHash       string     `json:"initiateTxHash" `
 	RedeemTxHash         string     `json:"redeemTxHash" `
 	RefundTxHash         string     `json:"refundTxHash" `
-	PriceByOracle        float64    `json:"priceByOracle"`
+	PriceByOracle        Decimal    `json:"priceByOracle"`
 	MinimumConfirmations uint64     `json:"minimumConfirmations"`
 	CurrentConfirmations uint64     `json:"currentConfirmation"`
 	InitiateBlockNumber  uint64     `json:"initiateBlockNumber"`
+	RedeemBlockNumber    uint64     `json:"redeemBlockNumber"`
+	RefundBlockNumber    uint64     `json:"refundBlockNumber"`
 	IsInstantWallet      bool       `json:"-"`
 }
 
@@ -234,9 +441,9 @@ func (sa *StringArray) Scan(value interface{}) error {
 
 	switch v := value.(type) {
 	case string:
-		*sa = strings.Split(v, ",")
+		*sa = splitStringArray(v)
 	case []byte:
-		*sa = strings.Split(string(v), ",")
+		*sa = splitStringArray(string(v))
 	default:
 		return fmt.Errorf("unsupported data type for StringArray: %T", value)
 	}
@@ -244,6 +451,14 @@ func (sa *StringArray) Scan(value interface{}) error {
 	return nil
 }
 
+// the empty string is the empty array
+func splitStringArray(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
 func ParseOrderPair(orderPair string) (Chain, Chain, Asset, Asset, error) {
 	chainAssets := strings.Split(orderPair, "-")
 	if len(chainAssets) != 2 {
@@ -286,11 +501,161 @@ func CompareOrder(a, b Order) bool {
 		a.FollowerAtomicSwap.FilledAmount == b.FollowerAtomicSwap.FilledAmount
 }
 
+// UserLimit overrides the configured trade limits for a wallet until it expires.
+// A limit with an order pair only applies to orders on that pair.
+type UserLimit struct {
+	gorm.Model
+
+	Wallet          string    `json:"wallet" gorm:"index"`
+	OrderPair       string    `json:"orderPair"`
+	MinTxLimit      string    `json:"minTxLimit"`
+	MaxTxLimit      string    `json:"maxTxLimit"`
+	DailyUSDLimit   string    `json:"dailyUSDLimit"`
+	WeeklyUSDLimit  string    `json:"weeklyUSDLimit"`
+	MonthlyUSDLimit string    `json:"monthlyUSDLimit"`
+	Expiry          time.Time `json:"expiry"`
+	GrantedBy       string    `json:"grantedBy"`
+	Reason          string    `json:"reason"`
+	RevokedBy       string    `json:"revokedBy"`
+}
+
+// IdempotentRequest is the first response to a request made by a wallet with an
+// idempotency key, replayed to retries of the request until it expires. A request
+// without a status is still in progress, retries can take it over once it has been
+// reserved for longer than the lock timeout.
+type IdempotentRequest struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	Wallet      string    `json:"wallet" gorm:"size:64;uniqueIndex:idx_idempotent_wallet_key"`
+	Key         string    `json:"key" gorm:"column:idempotency_key;size:255;uniqueIndex:idx_idempotent_wallet_key"`
+	Fingerprint string    `json:"fingerprint"`
+	Status      int       `json:"status"`
+	Response    []byte    `json:"response"`
+	CreatedAt   time.Time `json:"createdAt"`
+	ReservedAt  time.Time `json:"reservedAt"`
+	ExpiresAt   time.Time `json:"expiresAt" gorm:"index"`
+}
+
 type Blacklist struct {
 	gorm.Model
 	Address string `gorm:"unique"`
 }
 
+// BlockCursor is the last block an evm watcher has processed for a htlc contract
+type BlockCursor struct {
+	Chain       Chain  `gorm:"primaryKey"`
+	Contract    string `gorm:"primaryKey"`
+	BlockNumber uint64
+	UpdatedAt   time.Time
+}
+
+// ProcessedBlock is the hash of the last block of a range processed by an evm watcher,
+// used to detect reorgs on the following polls
+type ProcessedBlock struct {
+	Chain       Chain  `gorm:"primaryKey"`
+	Contract    string `gorm:"primaryKey"`
+	BlockNumber uint64 `gorm:"primaryKey;autoIncrement:false"`
+	BlockHash   string
+	CreatedAt   time.Time
+}
+
+// OrderEvent is an append-only record of an order state transition. Actor is the
+// party which made the change, i.e. maker:<address>, filler:<address>, watcher:<chain>
+// or reorg:<chain>
+type OrderEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	OrderID    uint      `json:"orderId" gorm:"index"`
+	PrevStatus Status    `json:"prevStatus"`
+	Status     Status    `json:"status"`
+	Taker      string    `json:"taker"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// SwapEvent is an append-only snapshot of an atomic swap taken whenever its
+// status, confirmations, filled amount or tx hashes change
+type SwapEvent struct {
+	ID                   uint       `json:"id" gorm:"primaryKey"`
+	SwapID               uint       `json:"swapId" gorm:"index"`
+	PrevStatus           SwapStatus `json:"prevStatus"`
+	Status               SwapStatus `json:"swapStatus"`
+	CurrentConfirmations uint64     `json:"currentConfirmation"`
+	FilledAmount         string     `json:"filledAmount"`
+	InitiateTxHash       string     `json:"initiateTxHash"`
+	RedeemTxHash         string     `json:"redeemTxHash"`
+	RefundTxHash         string     `json:"refundTxHash"`
+	Actor                string     `json:"actor"`
+	CreatedAt            time.Time  `json:"createdAt"`
+}
+
+// OrderHistory is the state transition history of an order and its atomic swaps
+type OrderHistory struct {
+	OrderID             uint         `json:"orderId"`
+	Order               []OrderEvent `json:"order"`
+	InitiatorAtomicSwap []SwapEvent  `json:"initiatorAtomicSwap"`
+	FollowerAtomicSwap  []SwapEvent  `json:"followerAtomicSwap"`
+	// the order itself, including cancelled ones, is not sent and only decides
+	// who can see its history
+	Subject Order `json:"-"`
+}
+
+// VisibleTo reports whether the user can see the history, see Order.VisibleTo
+func (history OrderHistory) VisibleTo(user string) bool {
+	return history.Subject.VisibleTo(user)
+}
+
+// sort orders of filtered orders, prefixed with a "-" for descending order
+const (
+	SortByID        = "id"
+	SortByCreatedAt = "created_at"
+	SortByPrice     = "price"
+)
+
+// ErrInvalidCursor is returned for cursors which were not returned with a page
+// of orders sorted in the same order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrNotFound is returned by the store for orders and fillers which do not exist
+var ErrNotFound = errors.New("not found")
+
+// OrderFilter selects the orders matching all of its non-zero fields. Chain, asset,
+// swap status and tx hash match either of the atomic swaps of an order, the swap
+// status is a pointer as swaps which have not started have the zero status.
+//
+// Orders are paginated by a cursor when Limit is set, Cursor is the NextCursor of
+// the previous page. Page and PerPage are the offset pagination of older clients.
+type OrderFilter struct {
+	Maker         string
+	Taker         string
+	OrderPair     string
+	SecretHash    string
+	Status        Status
+	MinPrice      float64
+	MaxPrice      float64
+	MinAmount     float64
+	MaxAmount     float64
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Chain         Chain
+	Asset         Asset
+	SwapStatus    *SwapStatus
+	TxHash        string
+	Type          OrderType
+	// orders without an expiry never expire
+	ExpiresAfter time.Time
+	// only orders which are neither assigned nor restricted to some fillers
+	Unassigned bool
+	// hides the private orders which are not visible to the viewer, see VisibleTo
+	HidePrivate bool
+	Viewer      string
+
+	Sort    string
+	Cursor  string
+	Limit   int
+	Page    int
+	PerPage int
+	Verbose bool
+}
+
 type LockedAmount struct {
 	Asset  string
 	Amount sql.NullInt64
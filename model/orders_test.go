@@ -0,0 +1,37 @@
+package model_test
+
+import (
+	"math/big"
+	"time"
+
+	. "github.com/catalogfi/orderbook/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Orders", func() {
+	It("should expire limit orders at their expiry and market orders after the timeout", func() {
+		now := time.Now()
+		expiresAt := now.Add(time.Hour)
+		limit := Order{Type: LimitOrder, ExpiresAt: &expiresAt}
+		limit.CreatedAt = now.Add(-time.Hour)
+		Expect(limit.IsExpired(now, time.Minute)).To(BeFalse())
+		Expect(limit.IsExpired(expiresAt, time.Minute)).To(BeTrue())
+
+		market := Order{Type: MarketOrder}
+		market.CreatedAt = now.Add(-time.Hour)
+		Expect(market.IsExpired(now, time.Minute)).To(BeTrue())
+		Expect(market.IsExpired(now, 2*time.Hour)).To(BeFalse())
+	})
+
+	It("should default to market orders without an expiry", func() {
+		now := time.Now()
+		terms, err := OrderTerms{}.Validate(now, big.NewInt(1000))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(terms.Type).To(Equal(MarketOrder))
+		_, err = OrderTerms{Type: "stop"}.Validate(now, big.NewInt(1000))
+		Expect(err).To(HaveOccurred())
+		_, err = OrderTerms{Type: LimitOrder, ExpiresAt: now}.Validate(now, big.NewInt(1000))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// MaxOrderParts is the number of child orders a partially fillable order can be
+// filled by at most
+const MaxOrderParts = 16
+
+// PartReceiveAmount returns the amount received for filling the given part of the
+// send amount of an order, at the price of the order rounded up so that the
+// maker never receives less for a part than for the whole order
+func PartReceiveAmount(sendAmount, receiveAmount, partAmount *big.Int) (*big.Int, error) {
+	if sendAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid send amount: %s", sendAmount)
+	}
+	if partAmount.Sign() <= 0 || partAmount.Cmp(sendAmount) > 0 {
+		return nil, fmt.Errorf("invalid part amount: %s", partAmount)
+	}
+	amount := new(big.Int).Mul(receiveAmount, partAmount)
+	amount.Add(amount, new(big.Int).Sub(sendAmount, big.NewInt(1)))
+	return amount.Div(amount, sendAmount), nil
+}
+
+// RollupChildren returns the status of a partially fillable order which is no
+// longer filled from the statuses of its children, and false while any of them
+// is still active. A hard failure of any part fails the order hard, otherwise
+// it is executed if any part is executed, and failed soft or cancelled if none is.
+func RollupChildren(children []Order) (Status, bool) {
+	statuses := map[Status]bool{}
+	for _, child := range children {
+		if child.Status == Created || child.Status == Filled {
+			return Unknown, false
+		}
+		statuses[child.Status] = true
+	}
+	for _, status := range []Status{FailedHard, Executed, FailedSoft} {
+		if statuses[status] {
+			return status, true
+		}
+	}
+	return Cancelled, true
+}
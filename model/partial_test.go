@@ -0,0 +1,41 @@
+package model_test
+
+import (
+	"math/big"
+
+	. "github.com/catalogfi/orderbook/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Partial fills", func() {
+	It("should receive at least the price of the order for a part", func() {
+		amount, err := PartReceiveAmount(big.NewInt(3), big.NewInt(10), big.NewInt(1))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(amount.Int64()).To(Equal(int64(4)))
+		amount, err = PartReceiveAmount(big.NewInt(100), big.NewInt(110), big.NewInt(30))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(amount.Int64()).To(Equal(int64(33)))
+		_, err = PartReceiveAmount(big.NewInt(100), big.NewInt(110), big.NewInt(101))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should roll up the statuses of the child orders", func() {
+		_, done := RollupChildren([]Order{{Status: Executed}, {Status: Filled}})
+		Expect(done).To(BeFalse())
+		for expected, statuses := range map[Status][]Status{
+			Executed:   {Executed, Cancelled},
+			FailedHard: {Executed, FailedHard},
+			FailedSoft: {FailedSoft, Cancelled},
+			Cancelled:  {Cancelled},
+		} {
+			children := []Order{}
+			for _, status := range statuses {
+				children = append(children, Order{Status: status})
+			}
+			status, done := RollupChildren(children)
+			Expect(done).To(BeTrue())
+			Expect(status).To(Equal(expected))
+		}
+	})
+})
@@ -0,0 +1,41 @@
+package model
+
+// MaxAllowedFillers is the number of fillers a private order can be restricted to
+// at most
+const MaxAllowedFillers = 16
+
+// IsPrivate reports whether only some fillers can fill the order, either because
+// the maker restricted it to them or because it was created from a quote
+func (order Order) IsPrivate() bool {
+	return order.Taker != "" || len(order.AllowedFillers) > 0
+}
+
+// CanBeFilledBy reports whether the filler is allowed to fill the order, orders
+// which are not restricted can be filled by anyone
+func (order Order) CanBeFilledBy(filler string) bool {
+	if len(order.AllowedFillers) == 0 {
+		return true
+	}
+	for _, allowed := range order.AllowedFillers {
+		if allowed == filler {
+			return true
+		}
+	}
+	return false
+}
+
+// VisibleTo reports whether the user can see the order. Orders restricted to some
+// fillers are only visible to their maker and those fillers, and open orders
+// created from a quote only to their maker and taker.
+func (order Order) VisibleTo(user string) bool {
+	if len(order.AllowedFillers) == 0 && (order.Taker == "" || order.Status != Created) {
+		return true
+	}
+	if user == "" {
+		return false
+	}
+	if user == order.Maker || user == order.Taker {
+		return true
+	}
+	return len(order.AllowedFillers) > 0 && order.CanBeFilledBy(user)
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gorm.io/gorm"
+)
+
+// MaxQuoteValidity is how long after it is submitted a quote can be accepted at most
+const MaxQuoteValidity = 5 * time.Minute
+
+type QuoteRequestStatus string
+
+const (
+	QuoteRequestOpen     QuoteRequestStatus = "open"
+	QuoteRequestAccepted QuoteRequestStatus = "accepted"
+)
+
+// QuoteRequest is a request of a user for fillers to quote the amount they would
+// receive for sending the given amount on the order pair
+type QuoteRequest struct {
+	gorm.Model
+
+	Requester  string `json:"requester" gorm:"index"`
+	OrderPair  string `json:"orderPair" gorm:"index"`
+	SendAmount string `json:"sendAmount" gorm:"size:78"`
+	// fillers can quote until the request expires
+	ExpiresAt       time.Time          `json:"expiresAt"`
+	Status          QuoteRequestStatus `json:"status" gorm:"size:16"`
+	AcceptedQuoteID *uint              `json:"acceptedQuoteId,omitempty"`
+	OrderID         *uint              `json:"orderId,omitempty"`
+
+	Quotes []Quote `json:"quotes,omitempty"`
+}
+
+// Quote is the receive amount a filler offers for a request for quote, signed by
+// the filler
+type Quote struct {
+	gorm.Model
+
+	QuoteRequestID uint      `json:"quoteRequestId" gorm:"index"`
+	Filler         string    `json:"filler" gorm:"index"`
+	ReceiveAmount  string    `json:"receiveAmount" gorm:"size:78"`
+	ValidUntil     time.Time `json:"validUntil"`
+	Signature      string    `json:"signature"`
+	Accepted       bool      `json:"accepted"`
+}
+
+// Message is the message a filler signs to quote
+func (quote Quote) Message() string {
+	return fmt.Sprintf("Orderbook quote\nRequest: %d\nReceive amount: %s\nValid until: %d", quote.QuoteRequestID, quote.ReceiveAmount, quote.ValidUntil.Unix())
+}
+
+// Sign signs the quote with an ethereum personal signature of its message
+func (quote Quote) Sign(key *ecdsa.PrivateKey) (string, error) {
+	return signMessage(quote.Message(), key)
+}
+
+// Signer returns the lower case address which signed the quote
+func (quote Quote) Signer() (string, error) {
+	signer, err := recoverSigner(quote.Message(), quote.Signature)
+	if err != nil {
+		return "", fmt.Errorf("invalid quote signature: %v", err)
+	}
+	return signer, nil
+}
+
+// sign the message with an ethereum personal signature
+func signMessage(message string, key *ecdsa.PrivateKey) (string, error) {
+	signature, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		return "", err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(signature), nil
+}
+
+// the lower case address which signed the message with an ethereum personal
+// signature
+func recoverSigner(message, signatureHex string) (string, error) {
+	signature, err := hexutil.Decode(signatureHex)
+	if err != nil {
+		return "", err
+	}
+	if len(signature) != crypto.SignatureLength {
+		return "", fmt.Errorf("invalid length %d", len(signature))
+	}
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	publicKey, err := crypto.SigToPub(accounts.TextHash([]byte(message)), signature)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(crypto.PubkeyToAddress(*publicKey).Hex()), nil
+}
@@ -0,0 +1,34 @@
+package model_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/catalogfi/orderbook/model"
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Quotes", func() {
+	It("should recover the filler who signed a quote", func() {
+		key, err := crypto.GenerateKey()
+		Expect(err).NotTo(HaveOccurred())
+		quote := Quote{QuoteRequestID: 1, ReceiveAmount: "99000000", ValidUntil: time.Unix(time.Now().Add(time.Minute).Unix(), 0)}
+		quote.Signature, err = quote.Sign(key)
+		Expect(err).NotTo(HaveOccurred())
+		signer, err := quote.Signer()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signer).To(Equal(strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())))
+
+		// the signature does not cover other amounts
+		quote.ReceiveAmount = "100000000"
+		signer, err = quote.Signer()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signer).NotTo(Equal(strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())))
+
+		quote.Signature = "0x1234"
+		_, err = quote.Signer()
+		Expect(err).To(HaveOccurred())
+	})
+})
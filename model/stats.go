@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Granularity is the length of the buckets of time order statistics are rolled up into
+type Granularity string
+
+const (
+	Hourly Granularity = "hour"
+	Daily  Granularity = "day"
+)
+
+func ParseGranularity(granularity string) (Granularity, error) {
+	switch Granularity(granularity) {
+	case Hourly, Daily:
+		return Granularity(granularity), nil
+	default:
+		return "", fmt.Errorf("unknown interval %v, expected %s or %s", granularity, Hourly, Daily)
+	}
+}
+
+func (g Granularity) Duration() time.Duration {
+	if g == Hourly {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Bucket returns the start of the bucket the time falls into
+func (g Granularity) Bucket(t time.Time) time.Time {
+	return t.UTC().Truncate(g.Duration())
+}
+
+// OrderRollup is the number of orders of a pair created in a bucket of time which
+// are in a status, and the USD value of the swaps their makers initiated
+type OrderRollup struct {
+	Granularity Granularity `gorm:"primaryKey;size:8"`
+	BucketStart time.Time   `gorm:"primaryKey;autoIncrement:false"`
+	OrderPair   string      `gorm:"primaryKey;size:255"`
+	Status      Status      `gorm:"primaryKey;autoIncrement:false"`
+	Trades      int64
+	Volume      Decimal
+}
+
+// UserRollup is the number of executed orders a wallet made or filled in a bucket
+// of time, and the USD value of the swaps the wallet initiated
+type UserRollup struct {
+	Granularity Granularity `gorm:"primaryKey;size:8"`
+	BucketStart time.Time   `gorm:"primaryKey;autoIncrement:false"`
+	Wallet      string      `gorm:"primaryKey;size:64"`
+	Trades      int64
+	Volume      Decimal
+}
+
+// RollupCursor is the time up to which updated orders have been rolled up
+type RollupCursor struct {
+	Name      string `gorm:"primaryKey;size:64"`
+	Until     time.Time
+	UpdatedAt time.Time
+}
+
+// OrderStats are the orders created in a bucket of time. Volume is the USD value
+// the makers of executed orders sent, the rates are shares of the finished orders.
+type OrderStats struct {
+	Start       time.Time `json:"start"`
+	Volume      Decimal   `json:"volume"`
+	Trades      int64     `json:"trades"`
+	Executed    int64     `json:"executed"`
+	Failed      int64     `json:"failed"`
+	Cancelled   int64     `json:"cancelled"`
+	SuccessRate float64   `json:"successRate"`
+	FailureRate float64   `json:"failureRate"`
+}
+
+// UserStats are the executed orders of a wallet created in a bucket of time
+type UserStats struct {
+	Start  time.Time `json:"start"`
+	Volume Decimal   `json:"volume"`
+	Trades int64     `json:"trades"`
+}
+
+// TVLSnapshot is the amount of an asset, in whole units, locked in the active swaps
+// on a chain, valued at the oracle price of the asset when the snapshot was taken.
+// Snapshots older than a day are downsampled to the first snapshot of each hour,
+// and older than a month to the first of each day, Resolution is the bucket they
+// represent.
+type TVLSnapshot struct {
+	ID         uint        `json:"-" gorm:"primaryKey"`
+	TakenAt    time.Time   `json:"takenAt" gorm:"index"`
+	Chain      Chain       `json:"chain" gorm:"size:64;index"`
+	Asset      Asset       `json:"asset" gorm:"size:255"`
+	Amount     Decimal     `json:"amount"`
+	Price      Decimal     `json:"price"`
+	Value      Decimal     `json:"value"`
+	Resolution Granularity `json:"-" gorm:"size:8"`
+}
+
+// TVLPoint is the USD value locked on a chain at a point in time
+type TVLPoint struct {
+	Time   time.Time     `json:"time"`
+	Chain  Chain         `json:"chain"`
+	Value  Decimal       `json:"value"`
+	Assets []TVLSnapshot `json:"assets"`
+}
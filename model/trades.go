@@ -0,0 +1,139 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Trade is an executed order. Amounts are in whole units of the assets of the
+// initiator and follower swaps, the price is the follower amount paid per unit
+// of the initiator amount.
+type Trade struct {
+	OrderID       uint      `json:"orderId" gorm:"primaryKey;autoIncrement:false"`
+	OrderPair     string    `json:"orderPair" gorm:"size:255;index:idx_trades_pair_executed_at,priority:1"`
+	SendAmount    Decimal   `json:"sendAmount"`
+	ReceiveAmount Decimal   `json:"receiveAmount"`
+	Price         Decimal   `json:"price"`
+	ExecutedAt    time.Time `json:"executedAt" gorm:"index:idx_trades_pair_executed_at,priority:2"`
+}
+
+// NewTrade returns the trade of an executed order with its atomic swaps
+func NewTrade(order Order, config Network) (Trade, error) {
+	if order.Status != Executed {
+		return Trade{}, fmt.Errorf("order %d is not executed", order.ID)
+	}
+	if order.InitiatorAtomicSwap == nil || order.FollowerAtomicSwap == nil {
+		return Trade{}, fmt.Errorf("order %d is missing its atomic swaps", order.ID)
+	}
+	sendAmount, err := swapAmount(*order.InitiatorAtomicSwap, config)
+	if err != nil {
+		return Trade{}, err
+	}
+	receiveAmount, err := swapAmount(*order.FollowerAtomicSwap, config)
+	if err != nil {
+		return Trade{}, err
+	}
+	price, err := receiveAmount.Quo(sendAmount)
+	if err != nil {
+		return Trade{}, fmt.Errorf("order %d has no send amount", order.ID)
+	}
+	return Trade{
+		OrderID:       order.ID,
+		OrderPair:     order.OrderPair,
+		SendAmount:    sendAmount,
+		ReceiveAmount: receiveAmount,
+		Price:         price,
+		ExecutedAt:    order.UpdatedAt.UTC(),
+	}, nil
+}
+
+// the amount of a swap in whole units, assets without configured decimals are
+// assumed to have 8 like the usd value of legacy assets
+func swapAmount(swap AtomicSwap, config Network) (Decimal, error) {
+	amount, ok := new(big.Int).SetString(swap.Amount, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid amount %q of swap %d", swap.Amount, swap.ID)
+	}
+	decimals := config[swap.Chain].Assets[swap.Asset].Decimals
+	if decimals == 0 {
+		decimals = 8
+	}
+	return NewDecimalFromBigInt(amount, decimals), nil
+}
+
+// CandleInterval is the length of the candles trades are aggregated into
+type CandleInterval string
+
+const (
+	Minute1 CandleInterval = "1m"
+	Hour1   CandleInterval = "1h"
+	Day1    CandleInterval = "1d"
+)
+
+func ParseCandleInterval(interval string) (CandleInterval, error) {
+	switch CandleInterval(interval) {
+	case Minute1, Hour1, Day1:
+		return CandleInterval(interval), nil
+	default:
+		return "", fmt.Errorf("unknown interval %v, expected %s, %s or %s", interval, Minute1, Hour1, Day1)
+	}
+}
+
+func (i CandleInterval) Duration() time.Duration {
+	switch i {
+	case Minute1:
+		return time.Minute
+	case Hour1:
+		return time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// Bucket returns the start of the candle the time falls into
+func (i CandleInterval) Bucket(t time.Time) time.Time {
+	return t.UTC().Truncate(i.Duration())
+}
+
+// Candle is the open, high, low and close price of the trades of a pair executed
+// in an interval, volume is the sum of their send amounts
+type Candle struct {
+	Start  time.Time `json:"start"`
+	Open   Decimal   `json:"open"`
+	High   Decimal   `json:"high"`
+	Low    Decimal   `json:"low"`
+	Close  Decimal   `json:"close"`
+	Volume Decimal   `json:"volume"`
+	Trades int64     `json:"trades"`
+}
+
+// Apply adds a trade executed after the trades already in the candle
+func (c *Candle) Apply(trade Trade) {
+	if c.Trades == 0 {
+		c.Open, c.High, c.Low = trade.Price, trade.Price, trade.Price
+	}
+	if trade.Price.Cmp(c.High) > 0 {
+		c.High = trade.Price
+	}
+	if trade.Price.Cmp(c.Low) < 0 {
+		c.Low = trade.Price
+	}
+	c.Close = trade.Price
+	c.Volume = c.Volume.Add(trade.SendAmount)
+	c.Trades++
+}
+
+// BuildCandles aggregates trades sorted by execution time into candles, intervals
+// without trades are omitted
+func BuildCandles(trades []Trade, interval CandleInterval) []Candle {
+	candles := []Candle{}
+	for _, trade := range trades {
+		start := interval.Bucket(trade.ExecutedAt)
+		if len(candles) == 0 || !candles[len(candles)-1].Start.Equal(start) {
+			candles = append(candles, Candle{Start: start})
+		}
+		candles[len(candles)-1].Apply(trade)
+	}
+	return candles
+}
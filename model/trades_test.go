@@ -0,0 +1,55 @@
+package model_test
+
+import (
+	"time"
+
+	. "github.com/catalogfi/orderbook/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trades", func() {
+	It("should price trades in whole units", func() {
+		order := Order{
+			OrderPair:           "bitcoin-ethereum",
+			Status:              Executed,
+			InitiatorAtomicSwap: &AtomicSwap{Chain: Bitcoin, Asset: Primary, Amount: "50000000"},
+			FollowerAtomicSwap:  &AtomicSwap{Chain: Ethereum, Asset: Primary, Amount: "10000000000000000000"},
+		}
+		trade, err := NewTrade(order, Network{Ethereum: NetworkConfig{Assets: map[Asset]Token{Primary: {Decimals: 18}}}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trade.SendAmount.String()).To(Equal("0.5"))
+		Expect(trade.ReceiveAmount.String()).To(Equal("10"))
+		Expect(trade.Price.String()).To(Equal("20"))
+
+		order.Status = Filled
+		_, err = NewTrade(order, Network{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should aggregate trades into candles", func() {
+		start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		trades := []Trade{
+			{Price: decimal("20"), SendAmount: decimal("1"), ExecutedAt: start.Add(time.Minute)},
+			{Price: decimal("25"), SendAmount: decimal("2"), ExecutedAt: start.Add(10 * time.Minute)},
+			{Price: decimal("18"), SendAmount: decimal("1"), ExecutedAt: start.Add(20 * time.Minute)},
+			{Price: decimal("21"), SendAmount: decimal("1"), ExecutedAt: start.Add(90 * time.Minute)},
+		}
+		candles := BuildCandles(trades, Hour1)
+		Expect(candles).To(HaveLen(2))
+		Expect(candles[0].Start).To(Equal(start))
+		Expect(candles[0].Open.String()).To(Equal("20"))
+		Expect(candles[0].High.String()).To(Equal("25"))
+		Expect(candles[0].Low.String()).To(Equal("18"))
+		Expect(candles[0].Close.String()).To(Equal("18"))
+		Expect(candles[0].Volume.String()).To(Equal("4"))
+		Expect(candles[0].Trades).To(Equal(int64(3)))
+		Expect(candles[1].Start).To(Equal(start.Add(time.Hour)))
+		Expect(candles[1].Open.String()).To(Equal("21"))
+
+		Expect(BuildCandles(trades, Day1)).To(HaveLen(1))
+		Expect(BuildCandles(trades, Minute1)).To(HaveLen(4))
+		_, err := ParseCandleInterval("5m")
+		Expect(err).To(HaveOccurred())
+	})
+})
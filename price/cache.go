@@ -0,0 +1,73 @@
+package price
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+)
+
+// Cache is a concurrency safe price cache, shared by everything quoting the same prices
+type Cache struct {
+	mu     *sync.RWMutex
+	prices map[string]cachedPrice
+}
+
+type cachedPrice struct {
+	price     Price
+	fetchedAt time.Time
+}
+
+func NewCache() *Cache {
+	return &Cache{mu: new(sync.RWMutex), prices: map[string]cachedPrice{}}
+}
+
+// Get returns the cached price under the given key, or fetches it from the
+// oracle if it is missing or was fetched more than ttl ago
+func (c *Cache) Get(ctx context.Context, key string, ttl time.Duration, oracle PriceOracle) (Price, error) {
+	c.mu.RLock()
+	cached, ok := c.prices[key]
+	c.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) <= ttl {
+		return cached.price, nil
+	}
+
+	price, err := oracle.GetUSDPrice(ctx)
+	if err != nil {
+		return Price{}, err
+	}
+	c.mu.Lock()
+	c.prices[key] = cachedPrice{price: price, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return price, nil
+}
+
+type cachedFetcher struct {
+	fetcher PriceFetcher
+	cache   *Cache
+	key     string
+	ttl     time.Duration
+}
+
+// NewCachedPriceFetcher caches the prices of the given fetcher in the given cache,
+// the L1 block number of the fetched prices is not cached
+func NewCachedPriceFetcher(fetcher PriceFetcher, cache *Cache, key string, ttl time.Duration) PriceFetcher {
+	return &cachedFetcher{fetcher: fetcher, cache: cache, key: key, ttl: ttl}
+}
+
+func (f *cachedFetcher) GetPrice(ctx context.Context) (PriceData, error) {
+	price, err := f.cache.Get(ctx, f.key, f.ttl, f)
+	if err != nil {
+		return PriceData{}, err
+	}
+	return PriceData{Price: price.Price.Float64()}, nil
+}
+
+func (f *cachedFetcher) GetUSDPrice(ctx context.Context) (Price, error) {
+	data, err := f.fetcher.GetPrice(ctx)
+	if err != nil {
+		return Price{}, err
+	}
+	return Price{Price: model.NewDecimalFromFloat(data.Price), Timestamp: time.Now().Unix()}, nil
+}
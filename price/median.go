@@ -0,0 +1,120 @@
+package price
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+)
+
+type medianOracle struct {
+	oracles      []PriceOracle
+	ttl          time.Duration
+	maxDeviation float64
+}
+
+// NewMedianOracle aggregates the given oracles to the median of their prices.
+// Prices older than the ttl are rejected, as are prices deviating from the median
+// by more than maxDeviation (a fraction, i.e. 0.05 for 5%). A price is returned only
+// if a majority of the fresh prices agree. Zero ttl or maxDeviation disables the check.
+func NewMedianOracle(oracles []PriceOracle, ttl time.Duration, maxDeviation float64) PriceOracle {
+	return &medianOracle{oracles: oracles, ttl: ttl, maxDeviation: maxDeviation}
+}
+
+// NewMedianOracleFromSources parses the given sources and aggregates them, see ParseOracle
+func NewMedianOracleFromSources(sources []string, ttl time.Duration, maxDeviation float64) (PriceOracle, error) {
+	oracles := make([]PriceOracle, 0, len(sources))
+	for _, source := range sources {
+		if source == "" {
+			continue
+		}
+		oracle, err := ParseOracle(source)
+		if err != nil {
+			return nil, err
+		}
+		oracles = append(oracles, oracle)
+	}
+	if len(oracles) == 0 {
+		return nil, fmt.Errorf("no price sources")
+	}
+	return NewMedianOracle(oracles, ttl, maxDeviation), nil
+}
+
+func (o *medianOracle) GetUSDPrice(ctx context.Context) (Price, error) {
+	results := make([]Price, len(o.oracles))
+	errs := make([]error, len(o.oracles))
+	wg := new(sync.WaitGroup)
+	for i, oracle := range o.oracles {
+		wg.Add(1)
+		go func(i int, oracle PriceOracle) {
+			defer wg.Done()
+			results[i], errs[i] = oracle.GetUSDPrice(ctx)
+		}(i, oracle)
+	}
+	wg.Wait()
+
+	now := time.Now().Unix()
+	fresh := []Price{}
+	failures := []string{}
+	for i, result := range results {
+		switch {
+		case errs[i] != nil:
+			failures = append(failures, errs[i].Error())
+		case result.Price.Sign() <= 0:
+			failures = append(failures, fmt.Sprintf("invalid price %v", result.Price))
+		case o.ttl > 0 && now-result.Timestamp > int64(o.ttl.Seconds()):
+			failures = append(failures, fmt.Sprintf("stale price from %v", time.Unix(result.Timestamp, 0).UTC()))
+		default:
+			fresh = append(fresh, result)
+		}
+	}
+	if len(fresh) == 0 {
+		return Price{}, fmt.Errorf("no valid price: %s", strings.Join(failures, ", "))
+	}
+
+	mid := median(fresh)
+	agreeing := fresh
+	if o.maxDeviation > 0 {
+		// |price - mid| <= maxDeviation * mid, the median of positive prices is positive
+		maxDistance := mid.Mul(model.NewDecimalFromFloat(o.maxDeviation))
+		agreeing = []Price{}
+		for _, result := range fresh {
+			distance := result.Price.Sub(mid)
+			if distance.Sign() < 0 {
+				distance = mid.Sub(result.Price)
+			}
+			if distance.Cmp(maxDistance) <= 0 {
+				agreeing = append(agreeing, result)
+			}
+		}
+		if len(agreeing) <= len(fresh)/2 {
+			return Price{}, fmt.Errorf("price sources deviate by more than %v from the median %v", o.maxDeviation, mid)
+		}
+	}
+
+	// the aggregate is only as fresh as its oldest price
+	price := Price{Price: median(agreeing), Timestamp: agreeing[0].Timestamp}
+	for _, result := range agreeing {
+		if result.Timestamp < price.Timestamp {
+			price.Timestamp = result.Timestamp
+		}
+	}
+	return price, nil
+}
+
+func median(prices []Price) model.Decimal {
+	values := make([]model.Decimal, len(prices))
+	for i, price := range prices {
+		values[i] = price.Price
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+	if len(values)%2 == 1 {
+		return values[len(values)/2]
+	}
+	mid, _ := values[len(values)/2-1].Add(values[len(values)/2]).Quo(model.NewDecimalFromFloat(2))
+	return mid
+}
@@ -0,0 +1,231 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+)
+
+// PriceOracle fetches the USD price of an asset from a single source
+type PriceOracle interface {
+	GetUSDPrice(ctx context.Context) (Price, error)
+}
+
+// Price is a USD price and the unix time (in seconds) it was observed at
+type Price struct {
+	Price     model.Decimal
+	Timestamp int64
+}
+
+const (
+	CoinCapURL   = "https://api.coincap.io/v2/assets"
+	CoinGeckoURL = "https://api.coingecko.com/api/v3"
+	BinanceURL   = "https://api.binance.com/api/v3"
+)
+
+// ParseOracle builds an oracle from a source of the format <provider>:<id>
+//
+//	coincap:bitcoin          price of the coincap asset
+//	coingecko:bitcoin        price of the coingecko coin
+//	binance:BTCUSDT          last traded price of the binance symbol
+//	static:42000.5           constant price, for tests and local setups
+//	file:prices.json#bitcoin price under the key of a json object in the file
+//
+// http(s) urls are treated as coincap asset urls.
+func ParseOracle(source string) (PriceOracle, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return NewCoinCapOracle(source), nil
+	}
+	provider, id, ok := strings.Cut(source, ":")
+	if !ok || id == "" {
+		return nil, fmt.Errorf("invalid price source %q, should be of the format <provider>:<id>", source)
+	}
+	switch provider {
+	case "coincap":
+		return NewCoinCapOracle(fmt.Sprintf("%s/%s", CoinCapURL, id)), nil
+	case "coingecko":
+		return NewCoinGeckoOracle(CoinGeckoURL, id), nil
+	case "binance":
+		return NewBinanceOracle(BinanceURL, id), nil
+	case "static":
+		price, err := model.NewDecimal(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid static price %q: %v", id, err)
+		}
+		return NewStaticOracle(price), nil
+	case "file":
+		path, key, ok := strings.Cut(id, "#")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid file price source %q, should be of the format file:<path>#<key>", source)
+		}
+		return NewFileOracle(path, key), nil
+	default:
+		return nil, fmt.Errorf("unknown price provider %q", provider)
+	}
+}
+
+type coinCapOracle struct {
+	url string
+}
+
+// NewCoinCapOracle returns an oracle for the given coincap asset url
+func NewCoinCapOracle(url string) PriceOracle {
+	return &coinCapOracle{url: url}
+}
+
+func (o *coinCapOracle) GetUSDPrice(ctx context.Context) (Price, error) {
+	var res struct {
+		Data struct {
+			PriceUsd string `json:"priceUsd"`
+		} `json:"data"`
+		Timestamp int64 `json:"timestamp"`
+	}
+	if err := getJSON(ctx, o.url, &res); err != nil {
+		return Price{}, err
+	}
+	price, err := model.NewDecimal(res.Data.PriceUsd)
+	if err != nil {
+		return Price{}, fmt.Errorf("failed to parse price %q: %v", res.Data.PriceUsd, err)
+	}
+	return Price{Price: price, Timestamp: unixSeconds(res.Timestamp)}, nil
+}
+
+type coinGeckoOracle struct {
+	url string
+	id  string
+}
+
+// NewCoinGeckoOracle returns an oracle for the given coingecko coin id
+func NewCoinGeckoOracle(url, id string) PriceOracle {
+	return &coinGeckoOracle{url: url, id: id}
+}
+
+func (o *coinGeckoOracle) GetUSDPrice(ctx context.Context) (Price, error) {
+	// prices are numbers, they are kept as they are written instead of as floats
+	res := map[string]struct {
+		USD           json.Number `json:"usd"`
+		LastUpdatedAt int64       `json:"last_updated_at"`
+	}{}
+	if err := getJSON(ctx, fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=usd&include_last_updated_at=true", o.url, o.id), &res); err != nil {
+		return Price{}, err
+	}
+	coin, ok := res[o.id]
+	if !ok || coin.USD == "" {
+		return Price{}, fmt.Errorf("price of %s not found", o.id)
+	}
+	price, err := model.NewDecimal(coin.USD.String())
+	if err != nil {
+		return Price{}, fmt.Errorf("failed to parse price %q: %v", coin.USD, err)
+	}
+	if price.Sign() == 0 {
+		return Price{}, fmt.Errorf("price of %s not found", o.id)
+	}
+	return Price{Price: price, Timestamp: coin.LastUpdatedAt}, nil
+}
+
+type binanceOracle struct {
+	url    string
+	symbol string
+}
+
+// NewBinanceOracle returns an oracle for the given binance usd(t) symbol
+func NewBinanceOracle(url, symbol string) PriceOracle {
+	return &binanceOracle{url: url, symbol: symbol}
+}
+
+func (o *binanceOracle) GetUSDPrice(ctx context.Context) (Price, error) {
+	var res struct {
+		Price string `json:"price"`
+	}
+	if err := getJSON(ctx, fmt.Sprintf("%s/ticker/price?symbol=%s", o.url, o.symbol), &res); err != nil {
+		return Price{}, err
+	}
+	price, err := model.NewDecimal(res.Price)
+	if err != nil {
+		return Price{}, fmt.Errorf("failed to parse price %q: %v", res.Price, err)
+	}
+	// ticker prices are live
+	return Price{Price: price, Timestamp: time.Now().Unix()}, nil
+}
+
+type staticOracle struct {
+	price model.Decimal
+}
+
+// NewStaticOracle returns an oracle which always returns the given price
+func NewStaticOracle(price model.Decimal) PriceOracle {
+	return &staticOracle{price: price}
+}
+
+func (o *staticOracle) GetUSDPrice(ctx context.Context) (Price, error) {
+	return Price{Price: o.price, Timestamp: time.Now().Unix()}, nil
+}
+
+type fileOracle struct {
+	path string
+	key  string
+}
+
+// NewFileOracle returns an oracle reading the price under the given key of a json
+// object in the given file, the price is as old as the last modification of the file
+func NewFileOracle(path, key string) PriceOracle {
+	return &fileOracle{path: path, key: key}
+}
+
+func (o *fileOracle) GetUSDPrice(ctx context.Context) (Price, error) {
+	info, err := os.Stat(o.path)
+	if err != nil {
+		return Price{}, err
+	}
+	data, err := os.ReadFile(o.path)
+	if err != nil {
+		return Price{}, err
+	}
+	prices := map[string]json.Number{}
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return Price{}, fmt.Errorf("failed to decode %s: %v", o.path, err)
+	}
+	value, ok := prices[o.key]
+	if !ok {
+		return Price{}, fmt.Errorf("price of %s not found in %s", o.key, o.path)
+	}
+	price, err := model.NewDecimal(value.String())
+	if err != nil {
+		return Price{}, fmt.Errorf("failed to parse price %q: %v", value, err)
+	}
+	return Price{Price: price, Timestamp: info.ModTime().Unix()}, nil
+}
+
+func getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// providers report timestamps either in seconds or milliseconds
+func unixSeconds(timestamp int64) int64 {
+	if timestamp > 1e12 {
+		return timestamp / 1000
+	}
+	return timestamp
+}
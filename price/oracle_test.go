@@ -0,0 +1,194 @@
+package price_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/price"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type mockOracle struct {
+	price Price
+	err   error
+	calls *int64
+}
+
+func (o mockOracle) GetUSDPrice(ctx context.Context) (Price, error) {
+	if o.calls != nil {
+		atomic.AddInt64(o.calls, 1)
+	}
+	return o.price, o.err
+}
+
+func fresh(price float64) PriceOracle {
+	return mockOracle{price: Price{Price: model.NewDecimalFromFloat(price), Timestamp: time.Now().Unix()}}
+}
+
+var _ = Describe("Price oracles", func() {
+	Describe("providers", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/assets/bitcoin":
+					fmt.Fprintf(w, `{"data":{"priceUsd":"42000.5"},"timestamp":%d}`, time.Now().UnixMilli())
+				case "/simple/price":
+					fmt.Fprintf(w, `{"%s":{"usd":42001,"last_updated_at":%d}}`, r.URL.Query().Get("ids"), time.Now().Unix())
+				case "/ticker/price":
+					fmt.Fprintf(w, `{"symbol":"%s","price":"42002.00"}`, r.URL.Query().Get("symbol"))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should get prices from coincap, coingecko and binance", func() {
+			price, err := NewCoinCapOracle(server.URL + "/assets/bitcoin").GetUSDPrice(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("42000.5"))
+			Expect(price.Timestamp).To(BeNumerically("~", time.Now().Unix(), 2))
+
+			price, err = NewCoinGeckoOracle(server.URL, "bitcoin").GetUSDPrice(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("42001"))
+
+			price, err = NewBinanceOracle(server.URL, "BTCUSDT").GetUSDPrice(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("42002"))
+		})
+
+		It("should fail on unexpected responses", func() {
+			_, err := NewCoinCapOracle(server.URL + "/assets/unknown").GetUSDPrice(context.Background())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail when the provider is unreachable", func() {
+			server.Close()
+			_, err := NewCoinCapOracle(server.URL + "/assets/bitcoin").GetUSDPrice(context.Background())
+			Expect(err).To(HaveOccurred())
+
+			_, err = NewPriceFetcher(Options{URL: server.URL}).GetPrice(context.Background())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("should parse price sources", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "prices.json")
+		Expect(os.WriteFile(path, []byte(`{"bitcoin": 41000, "dust": 0.100000000000000001}`), 0644)).To(Succeed())
+
+		oracle, err := ParseOracle("file:" + path + "#bitcoin")
+		Expect(err).NotTo(HaveOccurred())
+		price, err := oracle.GetUSDPrice(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(price.Price.String()).To(Equal("41000"))
+
+		// prices keep the digits a float would lose
+		oracle, err = ParseOracle("file:" + path + "#dust")
+		Expect(err).NotTo(HaveOccurred())
+		price, err = oracle.GetUSDPrice(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(price.Price.String()).To(Equal("0.100000000000000001"))
+
+		oracle, err = ParseOracle("static:1.5")
+		Expect(err).NotTo(HaveOccurred())
+		price, err = oracle.GetUSDPrice(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(price.Price.String()).To(Equal("1.5"))
+
+		for _, source := range []string{"coincap:bitcoin", "coingecko:bitcoin", "binance:BTCUSDT", "https://api.coincap.io/v2/assets/bitcoin"} {
+			_, err := ParseOracle(source)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		for _, source := range []string{"", "bitcoin", "unknown:bitcoin", "static:abc", "file:prices.json"} {
+			_, err := ParseOracle(source)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+
+	Describe("median", func() {
+		It("should return the median of the sources", func() {
+			price, err := NewMedianOracle([]PriceOracle{fresh(100), fresh(102), fresh(101)}, time.Minute, 0.05).GetUSDPrice(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("101"))
+
+			price, err = NewMedianOracle([]PriceOracle{fresh(100), fresh(102)}, time.Minute, 0.05).GetUSDPrice(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("101"))
+		})
+
+		It("should ignore failing and stale sources", func() {
+			stale := mockOracle{price: Price{Price: model.NewDecimalFromFloat(50), Timestamp: time.Now().Add(-time.Hour).Unix()}}
+			failing := mockOracle{err: fmt.Errorf("unavailable")}
+			price, err := NewMedianOracle([]PriceOracle{fresh(100), stale, failing}, time.Minute, 0.05).GetUSDPrice(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("100"))
+
+			_, err = NewMedianOracle([]PriceOracle{stale, failing}, time.Minute, 0.05).GetUSDPrice(context.Background())
+			Expect(err).To(MatchError(ContainSubstring("no valid price")))
+		})
+
+		It("should drop outliers and reject disagreeing sources", func() {
+			price, err := NewMedianOracle([]PriceOracle{fresh(100), fresh(101), fresh(150)}, time.Minute, 0.05).GetUSDPrice(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("100.5"))
+
+			_, err = NewMedianOracle([]PriceOracle{fresh(100), fresh(150)}, time.Minute, 0.05).GetUSDPrice(context.Background())
+			Expect(err).To(MatchError(ContainSubstring("deviate")))
+		})
+	})
+
+	Describe("cache", func() {
+		It("should fetch the price once per ttl", func() {
+			cache := NewCache()
+			calls := int64(0)
+			oracle := mockOracle{price: Price{Price: model.NewDecimalFromFloat(100), Timestamp: time.Now().Unix()}, calls: &calls}
+
+			wg := new(sync.WaitGroup)
+			for i := 0; i < 16; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					price, err := cache.Get(context.Background(), "bitcoin", time.Minute, oracle)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(price.Price.String()).To(Equal("100"))
+				}()
+			}
+			wg.Wait()
+			Expect(atomic.LoadInt64(&calls)).To(BeNumerically(">=", 1))
+
+			calls = 0
+			_, err := cache.Get(context.Background(), "bitcoin", time.Minute, oracle)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(BeZero())
+
+			_, err = cache.Get(context.Background(), "bitcoin", 0, oracle)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(int64(1)))
+		})
+
+		It("should not cache errors", func() {
+			cache := NewCache()
+			_, err := cache.Get(context.Background(), "bitcoin", time.Minute, mockOracle{err: fmt.Errorf("unavailable")})
+			Expect(err).To(HaveOccurred())
+			price, err := cache.Get(context.Background(), "bitcoin", time.Minute, fresh(100))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(price.Price.String()).To(Equal("100"))
+		})
+	})
+})
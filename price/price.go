@@ -43,7 +43,7 @@ func (pf *priceFetcher) GetPrice(ctx context.Context) (PriceData, error) {
 	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return PriceData{}, nil
+		return PriceData{}, err
 	}
 	defer resp.Body.Close()
 
@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type GrantUserLimit struct {
+	Wallet          string `json:"wallet" binding:"required"`
+	OrderPair       string `json:"orderPair"`
+	MinTxLimit      string `json:"minTxLimit"`
+	MaxTxLimit      string `json:"maxTxLimit"`
+	DailyUSDLimit   string `json:"dailyUSDLimit"`
+	WeeklyUSDLimit  string `json:"weeklyUSDLimit"`
+	MonthlyUSDLimit string `json:"monthlyUSDLimit"`
+	Expiry          int64  `json:"expiry" binding:"required"`
+	Reason          string `json:"reason" binding:"required"`
+}
+
+// allows only the configured admin wallets, should be used after authenticate
+func (s *Server) authorizeAdmin(ctx *gin.Context) {
+	wallet := ctx.GetString("userWallet")
+	for _, admin := range s.config.Admins {
+		if wallet != "" && strings.ToLower(admin) == wallet {
+			ctx.Next()
+			return
+		}
+	}
+	s.logger.Debug("authorization failure", zap.String("wallet", wallet), zap.Error(fmt.Errorf("not an admin")))
+	ctx.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+	ctx.Abort()
+}
+
+func (s *Server) grantUserLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := GrantUserLimit{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		limit := model.UserLimit{
+			Wallet:          req.Wallet,
+			OrderPair:       req.OrderPair,
+			MinTxLimit:      req.MinTxLimit,
+			MaxTxLimit:      req.MaxTxLimit,
+			DailyUSDLimit:   req.DailyUSDLimit,
+			WeeklyUSDLimit:  req.WeeklyUSDLimit,
+			MonthlyUSDLimit: req.MonthlyUSDLimit,
+			Expiry:          time.Unix(req.Expiry, 0).UTC(),
+			GrantedBy:       c.GetString("userWallet"),
+			Reason:          req.Reason,
+		}
+		if err := s.store.GrantUserLimit(&limit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to grant limit: %v", err)})
+			return
+		}
+		s.logger.Info("granted user limit", zap.Uint("id", limit.ID), zap.String("wallet", limit.Wallet), zap.String("grantedBy", limit.GrantedBy), zap.String("reason", limit.Reason))
+		c.JSON(http.StatusCreated, limit)
+	}
+}
+
+func (s *Server) getUserLimits() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		history := false
+		if c.Query("history") != "" {
+			var err error
+			if history, err = strconv.ParseBool(c.Query("history")); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode history: %v", err)})
+				return
+			}
+		}
+		limits, err := s.store.GetUserLimits(c.Query("wallet"), history)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get limits: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, limits)
+	}
+}
+
+func (s *Server) revokeUserLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode id has to be a number: %v", err.Error())})
+			return
+		}
+		if err := s.store.RevokeUserLimit(uint(id), c.GetString("userWallet")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to revoke limit: %v", err)})
+			return
+		}
+		s.logger.Info("revoked user limit", zap.Uint64("id", id), zap.String("revokedBy", c.GetString("userWallet")))
+		c.JSON(http.StatusNoContent, gin.H{})
+	}
+}
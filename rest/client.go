@@ -3,8 +3,11 @@ package rest
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"strconv"
 	"time"
@@ -19,9 +22,27 @@ import (
 
 type Client interface {
 	FillOrder(orderID uint, sendAddress, receiveAddress string) error
+	FillOrderPart(orderID uint, sendAddress, receiveAddress, amount string) (uint, error)
 	CreateOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string) (uint, error)
+	CreateLimitOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string, expiresAt time.Time) (uint, error)
+	CreateAuctionOrder(sendAddress, receiveAddress, orderPair, sendAmount, startReceiveAmount, endReceiveAmount, secretHash string, duration time.Duration) (uint, error)
+	CreatePartialOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string, partSecretHashes []string) (uint, error)
+	CreatePrivateOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string, allowedFillers []string) (uint, error)
+	CreateLotteryOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string) (uint, error)
+	RegisterFillIntent(orderID uint, sendAddress, receiveAddress string) error
+	RequestQuote(orderPair, sendAmount string) (model.QuoteRequest, error)
+	GetQuoteRequest(id uint) (model.QuoteRequest, error)
+	AcceptQuote(requestID, quoteID uint, sendAddress, receiveAddress, secretHash string) (uint, error)
 	GetOrder(id uint) (model.Order, error)
+	GetOrderHistory(id uint) (model.OrderHistory, error)
 	GetOrders(filter GetOrdersFilter) ([]model.Order, error)
+	GetOrdersPage(filter GetOrdersFilter) (OrdersPage, error)
+	GetBestOrders(orderPair string, limit int) ([]model.Order, error)
+	RegisterFiller(name, contact, bondAddress, bondSignature string, pairs []string) error
+	GetFillers() ([]model.Filler, error)
+	GetBond(filler string) (*model.Bond, error)
+	PublishInventory(chain model.Chain, asset model.Asset, amount *big.Int) error
+	TrackInventory(chain model.Chain, asset model.Asset, address string) error
 	GetFollowerInitiateOrders() ([]model.Order, error)
 	GetFollowerRedeemOrders() ([]model.Order, error)
 	GetInitiatorInitiateOrders() ([]model.Order, error)
@@ -50,6 +71,9 @@ func NewClient(url, privKey string) Client {
 	return &client{url: url, privKey: privKey}
 }
 
+// number of times a request that fails to reach the server is sent
+const MaxRequestAttempts = 3
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
@@ -60,33 +84,76 @@ func (c *client) FillOrder(orderID uint, sendAddress, receiveAddress string) err
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/orders/%d", c.url, orderID), &buf)
+	resp, err := c.sendIdempotent(http.MethodPut, fmt.Sprintf("%s/orders/%d", c.url, orderID), buf.Bytes())
 	if err != nil {
+		return fmt.Errorf("failed to fill order: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return fmt.Errorf("failed to fill order: %v", errorResponse.Error)
+	}
+	return nil
+}
+
+// registers the intent to fill a lottery order with the given addresses, the
+// order is filled with them if the filler wins the lottery
+func (c *client) RegisterFillIntent(orderID uint, sendAddress, receiveAddress string) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(FillIntent{SendAddress: sendAddress, ReceiveAddress: receiveAddress}); err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", c.JwtToken)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.sendIdempotent(http.MethodPost, fmt.Sprintf("%s/orders/%d/intents", c.url, orderID), buf.Bytes())
 	if err != nil {
-		return fmt.Errorf("failed to fill order: %v", err)
+		return fmt.Errorf("failed to register fill intent: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusAccepted {
-		if resp.StatusCode == http.StatusUnauthorized {
-			c.ReLogin()
-			return c.FillOrder(orderID, sendAddress, receiveAddress)
-		}
+	if resp.StatusCode != http.StatusCreated {
 		var errorResponse ErrorResponse
 		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
 			return fmt.Errorf("failed to decode error response: %v", err)
 		}
-		return fmt.Errorf("failed to create order: %v", errorResponse.Error)
+		return fmt.Errorf("failed to register fill intent: %v", errorResponse.Error)
 	}
 	return nil
 }
 
+// fills the given part of the send amount of a partially fillable order and
+// returns the id of the child order filling it
+func (c *client) FillOrderPart(orderID uint, sendAddress, receiveAddress, amount string) (uint, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(FillOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, Amount: amount}); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.sendIdempotent(http.MethodPut, fmt.Sprintf("%s/orders/%d", c.url, orderID), buf.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fill order: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return 0, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return 0, fmt.Errorf("failed to fill order: %v", errorResponse.Error)
+	}
+	var response struct {
+		OrderID uint `json:"orderId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, fmt.Errorf("failed to decode fill response: %v", err)
+	}
+	return response.OrderID, nil
+}
+
 func (c *client) GetSecrets(lastUpdated time.Time) ([]model.SecretRevealed, error) {
 	resp, err := http.Get(fmt.Sprintf("%s/secrets?lastUpdated=%d", c.url, lastUpdated.UnixNano()))
 	if err != nil {
@@ -104,34 +171,145 @@ func (c *client) GetSecrets(lastUpdated time.Time) ([]model.SecretRevealed, erro
 }
 
 func (c *client) CreateOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string) (uint, error) {
+	return c.createOrder(CreateOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, OrderPair: orderPair, SendAmount: sendAmount, ReceiveAmount: receiveAmount, SecretHash: secretHash})
+}
+
+// creates a limit order which can be filled until it expires
+func (c *client) CreateLimitOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string, expiresAt time.Time) (uint, error) {
+	return c.createOrder(CreateOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, OrderPair: orderPair, SendAmount: sendAmount, ReceiveAmount: receiveAmount, SecretHash: secretHash, Type: string(model.LimitOrder), ExpiresAt: expiresAt.Unix()})
+}
+
+// creates a Dutch auction order receiving from the start amount down to the end
+// amount over the duration
+func (c *client) CreateAuctionOrder(sendAddress, receiveAddress, orderPair, sendAmount, startReceiveAmount, endReceiveAmount, secretHash string, duration time.Duration) (uint, error) {
+	return c.createOrder(CreateOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, OrderPair: orderPair, SendAmount: sendAmount, ReceiveAmount: startReceiveAmount, SecretHash: secretHash, Type: string(model.AuctionOrder), AuctionEndAmount: endReceiveAmount, AuctionDuration: int64(duration / time.Second)})
+}
+
+// creates an order which can be filled in parts, one for each of the part secret hashes
+func (c *client) CreatePartialOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string, partSecretHashes []string) (uint, error) {
+	return c.createOrder(CreateOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, OrderPair: orderPair, SendAmount: sendAmount, ReceiveAmount: receiveAmount, SecretHash: secretHash, PartSecretHashes: partSecretHashes})
+}
+
+// creates an order which only the allowed fillers can fill
+func (c *client) CreatePrivateOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string, allowedFillers []string) (uint, error) {
+	return c.createOrder(CreateOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, OrderPair: orderPair, SendAmount: sendAmount, ReceiveAmount: receiveAmount, SecretHash: secretHash, AllowedFillers: allowedFillers})
+}
+
+// creates an order which is assigned by lottery to one of the fillers who
+// register their intent to fill it
+func (c *client) CreateLotteryOrder(sendAddress, receiveAddress, orderPair, sendAmount, receiveAmount, secretHash string) (uint, error) {
+	return c.createOrder(CreateOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, OrderPair: orderPair, SendAmount: sendAmount, ReceiveAmount: receiveAmount, SecretHash: secretHash, Lottery: true})
+}
+
+func (c *client) createOrder(req CreateOrder) (uint, error) {
 	var buf bytes.Buffer
 
-	fromchain, _, _, _, err := model.ParseOrderPair(orderPair)
+	fromchain, _, _, _, err := model.ParseOrderPair(req.OrderPair)
 	if err != nil {
 		return 0, err
 	}
 
-	var userBtcAddress string
 	if fromchain.IsBTC() {
-		userBtcAddress = sendAddress
+		req.UserWalletBTCAddress = req.SendAddress
 	} else {
-		userBtcAddress = receiveAddress
+		req.UserWalletBTCAddress = req.ReceiveAddress
 	}
-	if err := json.NewEncoder(&buf).Encode(CreateOrder{SendAddress: sendAddress, ReceiveAddress: receiveAddress, OrderPair: orderPair, SendAmount: sendAmount, ReceiveAmount: receiveAmount, SecretHash: secretHash, UserWalletBTCAddress: userBtcAddress}); err != nil {
+	if err := json.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/orders", c.url), &buf)
+	resp, err := c.sendIdempotent(http.MethodPost, fmt.Sprintf("%s/orders", c.url), buf.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("failed to create order: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return 0, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return 0, fmt.Errorf("failed to create order: %v", errorResponse.Error)
+	}
+
+	var orderResponse struct {
+		ID uint `json:"orderId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&orderResponse); err != nil {
+		return 0, fmt.Errorf("failed to decode order response: %v", err)
+	}
+	return orderResponse.ID, nil
+}
+
+// requests fillers to quote the send amount on the order pair
+func (c *client) RequestQuote(orderPair, sendAmount string) (model.QuoteRequest, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(RequestQuote{OrderPair: orderPair, SendAmount: sendAmount}); err != nil {
+		return model.QuoteRequest{}, err
+	}
+	resp, err := c.sendIdempotent(http.MethodPost, fmt.Sprintf("%s/quotes", c.url), buf.Bytes())
 	if err != nil {
-		return 0, fmt.Errorf("failed to create request: %v", err)
+		return model.QuoteRequest{}, fmt.Errorf("failed to request quotes: %v", err)
 	}
+	defer resp.Body.Close()
 
-	req.Header.Set("Authorization", c.JwtToken)
-	req.Header.Set("Content-Type", "application/json")
+	if resp.StatusCode != http.StatusCreated {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return model.QuoteRequest{}, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return model.QuoteRequest{}, fmt.Errorf("failed to request quotes: %v", errorResponse.Error)
+	}
+	var request model.QuoteRequest
+	if err := json.NewDecoder(resp.Body).Decode(&request); err != nil {
+		return model.QuoteRequest{}, fmt.Errorf("failed to decode quote request: %v", err)
+	}
+	return request, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// gets a request for quote of the user with its quotes, the best quote first
+func (c *client) GetQuoteRequest(id uint) (model.QuoteRequest, error) {
+	resp, err := c.sendAuthenticated(http.MethodGet, fmt.Sprintf("%s/quotes/%d", c.url, id), nil)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create order: %v", err)
+		return model.QuoteRequest{}, fmt.Errorf("failed to get quote request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return model.QuoteRequest{}, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return model.QuoteRequest{}, fmt.Errorf("failed to get quote request: %v", errorResponse.Error)
+	}
+	var request model.QuoteRequest
+	if err := json.NewDecoder(resp.Body).Decode(&request); err != nil {
+		return model.QuoteRequest{}, fmt.Errorf("failed to decode quote request: %v", err)
+	}
+	return request, nil
+}
+
+// accepts a quote of a request for quote and returns the id of the order created for it
+func (c *client) AcceptQuote(requestID, quoteID uint, sendAddress, receiveAddress, secretHash string) (uint, error) {
+	request, err := c.GetQuoteRequest(requestID)
+	if err != nil {
+		return 0, err
+	}
+	fromchain, _, _, _, err := model.ParseOrderPair(request.OrderPair)
+	if err != nil {
+		return 0, err
+	}
+	req := AcceptQuote{QuoteID: quoteID, SendAddress: sendAddress, ReceiveAddress: receiveAddress, SecretHash: secretHash, UserWalletBTCAddress: receiveAddress}
+	if fromchain.IsBTC() {
+		req.UserWalletBTCAddress = sendAddress
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(req); err != nil {
+		return 0, err
+	}
+	resp, err := c.sendIdempotent(http.MethodPost, fmt.Sprintf("%s/quotes/%d/accept", c.url, requestID), buf.Bytes())
+	if err != nil {
+		return 0, fmt.Errorf("failed to accept quote: %v", err)
 	}
 	defer resp.Body.Close()
 
@@ -140,9 +318,8 @@ func (c *client) CreateOrder(sendAddress, receiveAddress, orderPair, sendAmount,
 		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
 			return 0, fmt.Errorf("failed to decode error response: %v", err)
 		}
-		return 0, fmt.Errorf("failed to create order: %v", errorResponse.Error)
+		return 0, fmt.Errorf("failed to accept quote: %v", errorResponse.Error)
 	}
-
 	var orderResponse struct {
 		ID uint `json:"orderId"`
 	}
@@ -177,20 +354,55 @@ func (c *client) GetOrder(id uint) (model.Order, error) {
 	return order, nil
 }
 
+func (c *client) GetOrderHistory(id uint) (model.OrderHistory, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/orders/%d/history", c.url, id))
+	if err != nil {
+		return model.OrderHistory{}, fmt.Errorf("failed to get order history: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return model.OrderHistory{}, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return model.OrderHistory{}, fmt.Errorf("failed to get order history: %v", errorResponse.Error)
+	}
+
+	var history model.OrderHistory
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		return model.OrderHistory{}, fmt.Errorf("failed to decode order history: %v", err)
+	}
+	return history, nil
+}
+
+// GetOrdersFilter are the query parameters of GET /orders. OrderBy is one of
+// model.SortByID or model.SortByCreatedAt, prefixed with a "-" for descending
+// order. Chain, Asset, SwapStatus and TxHash match either swap of an order.
+// Orders are paginated by a cursor when Limit or Cursor is set.
 type GetOrdersFilter struct {
-	Maker      string
-	Taker      string
-	OrderPair  string
-	SecretHash string
-	OrderBy    string
-	Verbose    bool
-	Status     int
-	MinPrice   float64
-	MaxPrice   float64
-	MinAmount  float64
-	MaxAmount  float64
-	Page       int
-	PerPage    int
+	Maker         string
+	Taker         string
+	OrderPair     string
+	SecretHash    string
+	OrderBy       string
+	Verbose       bool
+	Status        int
+	MinPrice      float64
+	MaxPrice      float64
+	MinAmount     float64
+	MaxAmount     float64
+	Page          int
+	PerPage       int
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Chain         model.Chain
+	Asset         model.Asset
+	SwapStatus    *model.SwapStatus
+	TxHash        string
+	Cursor        string
+	Limit         int
+	Type          model.OrderType
 }
 
 func appendFilterString(filterString, filterName, filterValue string) string {
@@ -203,7 +415,7 @@ func appendFilterString(filterString, filterName, filterValue string) string {
 	return filterString
 }
 
-func (c *client) GetOrders(filter GetOrdersFilter) ([]model.Order, error) {
+func (filter GetOrdersFilter) queryString() string {
 	filterString := ""
 	if filter.Maker != "" {
 		filterString = appendFilterString(filterString, "maker", filter.Maker)
@@ -255,7 +467,46 @@ func (c *client) GetOrders(filter GetOrdersFilter) ([]model.Order, error) {
 	if filter.MaxAmount != 0 {
 		filterString = appendFilterString(filterString, "max_amount", strconv.FormatFloat(filter.MaxAmount, 'f', -1, 64))
 	}
+	if !filter.CreatedAfter.IsZero() {
+		filterString = appendFilterString(filterString, "created_after", strconv.FormatInt(filter.CreatedAfter.Unix(), 10))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		filterString = appendFilterString(filterString, "created_before", strconv.FormatInt(filter.CreatedBefore.Unix(), 10))
+	}
+	if filter.Chain != "" {
+		filterString = appendFilterString(filterString, "chain", string(filter.Chain))
+	}
+	if filter.Asset != "" {
+		filterString = appendFilterString(filterString, "asset", string(filter.Asset))
+	}
+	if filter.SwapStatus != nil {
+		filterString = appendFilterString(filterString, "swap_status", strconv.Itoa(int(*filter.SwapStatus)))
+	}
+	if filter.TxHash != "" {
+		filterString = appendFilterString(filterString, "tx_hash", filter.TxHash)
+	}
+	if filter.Cursor != "" {
+		filterString = appendFilterString(filterString, "cursor", filter.Cursor)
+	}
+	if filter.Limit != 0 {
+		filterString = appendFilterString(filterString, "limit", strconv.Itoa(filter.Limit))
+	}
+	if filter.Type != "" {
+		filterString = appendFilterString(filterString, "type", string(filter.Type))
+	}
+	return filterString
+}
 
+func (c *client) GetOrders(filter GetOrdersFilter) ([]model.Order, error) {
+	if filter.Limit != 0 || filter.Cursor != "" {
+		page, err := c.GetOrdersPage(filter)
+		if err != nil {
+			return nil, err
+		}
+		return page.Orders, nil
+	}
+
+	filterString := filter.queryString()
 	resp, err := http.Get(fmt.Sprintf("%s/orders%s", c.url, filterString))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get orders: %v", err)
@@ -277,6 +528,169 @@ func (c *client) GetOrders(filter GetOrdersFilter) ([]model.Order, error) {
 	return orders, nil
 }
 
+// GetOrdersPage returns a page of the orders matching the filter, the next page
+// is requested with the cursor of the page
+func (c *client) GetOrdersPage(filter GetOrdersFilter) (OrdersPage, error) {
+	if filter.Limit == 0 && filter.Cursor == "" {
+		filter.Limit = DefaultOrdersLimit
+	}
+	resp, err := http.Get(fmt.Sprintf("%s/orders%s", c.url, filter.queryString()))
+	if err != nil {
+		return OrdersPage{}, fmt.Errorf("failed to get orders: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return OrdersPage{}, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return OrdersPage{}, fmt.Errorf("failed to get orders: %v", errorResponse.Error)
+	}
+
+	var page OrdersPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return OrdersPage{}, fmt.Errorf("failed to decode orders: %v", err)
+	}
+	return page, nil
+}
+
+func (c *client) GetBestOrders(orderPair string, limit int) ([]model.Order, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/orderbook?order_pair=%s&limit=%d", c.url, orderPair, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orders: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return nil, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return nil, fmt.Errorf("failed to get orders: %v", errorResponse.Error)
+	}
+
+	var orders []model.Order
+	if err := json.NewDecoder(resp.Body).Decode(&orders); err != nil {
+		return nil, fmt.Errorf("failed to decode orders: %v", err)
+	}
+	return orders, nil
+}
+
+// registers or updates the profile of the wallet of the client as a filler, a bond
+// address other than the wallet signs model.BondAddressMessage of the wallet
+func (c *client) RegisterFiller(name, contact, bondAddress, bondSignature string, pairs []string) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(RegisterFiller{Name: name, Contact: contact, Pairs: pairs, BondAddress: bondAddress, BondSignature: bondSignature}); err != nil {
+		return err
+	}
+
+	resp, err := c.sendIdempotent(http.MethodPost, fmt.Sprintf("%s/fillers", c.url), buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to register filler: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return fmt.Errorf("failed to register filler: %v", errorResponse.Error)
+	}
+	return nil
+}
+
+// gets the fillers with their reputation, the best scored first
+func (c *client) GetFillers() ([]model.Filler, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/fillers", c.url))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fillers: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return nil, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return nil, fmt.Errorf("failed to get fillers: %v", errorResponse.Error)
+	}
+
+	var fillers []model.Filler
+	if err := json.NewDecoder(resp.Body).Decode(&fillers); err != nil {
+		return nil, fmt.Errorf("failed to decode fillers: %v", err)
+	}
+	return fillers, nil
+}
+
+// publishes the amount of an asset the wallet of the client has available to fill
+// orders, signed with the key of the client
+func (c *client) PublishInventory(chain model.Chain, asset model.Asset, amount *big.Int) error {
+	key, err := crypto.HexToECDSA(c.privKey)
+	if err != nil {
+		return err
+	}
+	publishedAt := time.Unix(time.Now().Unix(), 0)
+	inventory := model.Inventory{Chain: chain, Asset: asset, Amount: amount.String(), PublishedAt: &publishedAt}
+	signature, err := inventory.Sign(key)
+	if err != nil {
+		return err
+	}
+	return c.postInventory(PublishInventory{Chain: chain, Asset: asset, Amount: inventory.Amount, PublishedAt: publishedAt.Unix(), Signature: signature})
+}
+
+// publishes the address holding an asset the wallet of the client has available
+// to fill orders, its balance is read on chain
+func (c *client) TrackInventory(chain model.Chain, asset model.Asset, address string) error {
+	return c.postInventory(PublishInventory{Chain: chain, Asset: asset, Address: address})
+}
+
+func (c *client) postInventory(req PublishInventory) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(req); err != nil {
+		return err
+	}
+
+	resp, err := c.sendIdempotent(http.MethodPost, fmt.Sprintf("%s/fillers/inventory", c.url), buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to publish inventory: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return fmt.Errorf("failed to publish inventory: %v", errorResponse.Error)
+	}
+	return nil
+}
+
+// gets the bond of a filler as last verified on chain
+func (c *client) GetBond(filler string) (*model.Bond, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/fillers/%s/bond", c.url, filler))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bond: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResponse ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
+			return nil, fmt.Errorf("failed to decode error response: %v", err)
+		}
+		return nil, fmt.Errorf("failed to get bond: %v", errorResponse.Error)
+	}
+
+	var bond model.Bond
+	if err := json.NewDecoder(resp.Body).Decode(&bond); err != nil {
+		return nil, fmt.Errorf("failed to decode bond: %v", err)
+	}
+	return &bond, nil
+}
+
 func (c *client) GetFollowerInitiateOrders() ([]model.Order, error) {
 	resp, err := http.Get(fmt.Sprintf("%s/orders?taker=%s&status=3&verbose=true", c.url, c.id))
 	if err != nil {
@@ -548,6 +962,67 @@ func (c *client) ReLogin() error {
 	return nil
 }
 
+// sendIdempotent sends an authenticated request with a new idempotency key, requests
+// that fail to reach the server or are unauthorized are retried with the same key so
+// that the server applies them at most once
+func (c *client) sendIdempotent(method, url string, body []byte) (*http.Response, error) {
+	key, err := NewIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+	return c.send(method, url, body, key)
+}
+
+// sendAuthenticated sends an authenticated request without an idempotency key, for
+// reads which can be retried as they are
+func (c *client) sendAuthenticated(method, url string, body []byte) (*http.Response, error) {
+	return c.send(method, url, body, "")
+}
+
+// send retries the request when it fails to reach the server and logs in again
+// once when it is unauthorized, the idempotency key is only set if it is not empty
+func (c *client) send(method, url string, body []byte, key string) (*http.Response, error) {
+	relogged := false
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest(method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+		req.Header.Set("Authorization", c.JwtToken)
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if attempt >= MaxRequestAttempts {
+				return nil, err
+			}
+			time.Sleep(time.Duration(attempt) * time.Second)
+			continue
+		}
+		if resp.StatusCode == http.StatusUnauthorized && !relogged && c.privKey != "" {
+			resp.Body.Close()
+			if err := c.ReLogin(); err != nil {
+				return nil, err
+			}
+			relogged = true
+			continue
+		}
+		return resp, nil
+	}
+}
+
+// NewIdempotencyKey returns a random key to send with the Idempotency-Key header
+func NewIdempotencyKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency key: %v", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
 func GetUserWalletFromJWT(jwtString string) (string, error) {
 	token, _, err := new(jwt.Parser).ParseUnverified(jwtString, &Claims{})
 	if err != nil {
@@ -561,6 +1036,20 @@ func GetUserWalletFromJWT(jwtString string) (string, error) {
 	return "", fmt.Errorf("unable to extract UserWallet from JWT")
 }
 
+// GetExpiryFromJWT returns when the given jwt expires
+func GetExpiryFromJWT(jwtString string) (time.Time, error) {
+	token, _, err := new(jwt.Parser).ParseUnverified(jwtString, &Claims{})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if claims, ok := token.Claims.(*Claims); ok && claims.ExpiresAt != 0 {
+		return time.Unix(claims.ExpiresAt, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unable to extract expiry from JWT")
+}
+
 func signHash(data []byte) common.Hash {
 	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)
 	return crypto.Keccak256Hash([]byte(msg))
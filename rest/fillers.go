@@ -0,0 +1,208 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RegisterFiller struct {
+	Name    string   `json:"name" binding:"required"`
+	Pairs   []string `json:"pairs"`
+	Contact string   `json:"contact"`
+	// address holding the bond of the filler, the filler itself when empty
+	BondAddress string `json:"bondAddress"`
+	// signature of model.BondAddressMessage by the bond address, required when it
+	// is not the filler
+	BondSignature string `json:"bondSignature"`
+}
+
+type SettlePenalty struct {
+	Status model.PenaltyStatus `json:"status" binding:"required"`
+	// amount slashed from the bond of the filler, in the smallest unit of the bond
+	Amount string `json:"amount"`
+	Note   string `json:"note"`
+}
+
+// checks that the filler is in good standing to fill orders
+func (s *Server) checkFillerStanding(filler string) error {
+	if err := s.checkFillerScore(filler); err != nil {
+		return err
+	}
+	return s.checkFillerBond(filler)
+}
+
+// checks that the filler is scored at least the configured minimum score, fillers
+// which have not been scored yet are checked with the score of new fillers
+func (s *Server) checkFillerScore(filler string) error {
+	if s.config.MinFillerScore == 0 {
+		return nil
+	}
+	score := s.newFillerScore()
+	profile, err := s.store.GetFiller(filler)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return err
+	}
+	if err == nil && profile.ScoredAt != nil {
+		score = profile.Score
+	}
+	if score < s.config.MinFillerScore {
+		return fmt.Errorf("filler score %d is below the minimum score %d", score, s.config.MinFillerScore)
+	}
+	return nil
+}
+
+func (s *Server) newFillerScore() uint64 {
+	if s.config.NewFillerScore > 0 {
+		return s.config.NewFillerScore
+	}
+	return model.FillerScore(0, 0, 0)
+}
+
+// checks that the bond of the filler covers the configured minimum bond after
+// its slashed penalties and that it has no penalties pending review
+func (s *Server) checkFillerBond(filler string) error {
+	if !s.config.Bond.Required() {
+		return nil
+	}
+	minAmount, ok := new(big.Int).SetString(s.config.Bond.MinAmount, 10)
+	if !ok {
+		return fmt.Errorf("invalid minimum bond %s", s.config.Bond.MinAmount)
+	}
+	bond, err := s.store.GetBond(filler)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("filler %s has not posted a bond", filler)
+	}
+	if err != nil {
+		return err
+	}
+	if bond.Pending > 0 {
+		return fmt.Errorf("filler %s has %d penalties pending review", filler, bond.Pending)
+	}
+	if bond.VerifiedAt == nil {
+		return fmt.Errorf("bond of filler %s has not been verified yet", filler)
+	}
+	available, ok := new(big.Int).SetString(bond.Available, 10)
+	if !ok {
+		return fmt.Errorf("invalid bond %s", bond.Available)
+	}
+	if available.Cmp(minAmount) < 0 {
+		return fmt.Errorf("available bond %s is below the minimum bond %s", bond.Available, s.config.Bond.MinAmount)
+	}
+	return nil
+}
+
+// registers or updates the profile of the filler in the filler registry
+func (s *Server) postFiller() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filler, exists := c.Get("userWallet")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		req := RegisterFiller{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		address, bondAddress := strings.ToLower(filler.(string)), strings.ToLower(req.BondAddress)
+		// the balance of the bond address counts as the bond of the filler, so it
+		// has to agree to hold it
+		if bondAddress != "" && bondAddress != address {
+			signer, err := model.BondAddressSigner(address, req.BondSignature)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if signer != bondAddress {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("bond address is signed by %s", signer)})
+				return
+			}
+		}
+		if err := s.store.RegisterFiller(address, req.Name, req.Contact, bondAddress, req.Pairs); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to register filler: %v", err.Error())})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	}
+}
+
+// returns the fillers with their reputation, the best scored first
+func (s *Server) getFillers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fillers, err := s.store.GetFillers()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get fillers: %v", err.Error())})
+			return
+		}
+		if c.Query("registered") == "true" {
+			registered := []model.Filler{}
+			for _, filler := range fillers {
+				if filler.Registered {
+					registered = append(registered, filler)
+				}
+			}
+			fillers = registered
+		}
+		c.JSON(http.StatusOK, fillers)
+	}
+}
+
+// returns the bond of a filler as last verified on chain
+func (s *Server) getBond() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bond, err := s.store.GetBond(strings.ToLower(c.Param("address")))
+		if errors.Is(err, model.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("filler %s not found", c.Param("address"))})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get bond: %v", err.Error())})
+			return
+		}
+		c.JSON(http.StatusOK, bond)
+	}
+}
+
+// returns the penalties of the fillers to review, optionally of a filler or with
+// a status
+func (s *Server) getPenalties() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		penalties, err := s.store.GetPenalties(strings.ToLower(c.Query("filler")), model.PenaltyStatus(c.Query("status")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get penalties: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, penalties)
+	}
+}
+
+// settles a pending penalty by slashing the bond of the filler or waiving it
+func (s *Server) settlePenalty() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode id: %v", err)})
+			return
+		}
+		req := SettlePenalty{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		penalty, err := s.store.SettlePenalty(uint(id), req.Status, req.Amount, c.GetString("userWallet"), req.Note)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to settle penalty: %v", err)})
+			return
+		}
+		s.logger.Info("settled penalty", zap.Uint("id", penalty.ID), zap.String("filler", penalty.Filler), zap.String("status", string(penalty.Status)), zap.String("amount", penalty.Amount), zap.String("settledBy", penalty.SettledBy))
+		c.JSON(http.StatusOK, penalty)
+	}
+}
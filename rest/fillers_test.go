@@ -0,0 +1,108 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/feehub"
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/rest"
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("filler registry", func() {
+	register := func(req rest.RegisterFiller) int {
+		body, err := json.Marshal(req)
+		Expect(err).NotTo(HaveOccurred())
+		httpReq, err := http.NewRequest(http.MethodPost, "http://localhost:8080/fillers", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		httpReq.Header.Set("Authorization", authToken(mockAddress))
+		resp, err := http.DefaultClient.Do(httpReq)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	It("should only register a bond address which signed for the filler", func() {
+		key, err := crypto.GenerateKey()
+		Expect(err).NotTo(HaveOccurred())
+		bondAddress := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+		other, err := crypto.GenerateKey()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(register(rest.RegisterFiller{Name: "Filler", BondAddress: bondAddress})).To(Equal(http.StatusBadRequest))
+		signature, err := model.SignBondAddress(mockAddress, other)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(register(rest.RegisterFiller{Name: "Filler", BondAddress: bondAddress, BondSignature: signature})).To(Equal(http.StatusUnauthorized))
+
+		mockStore.EXPECT().RegisterFiller(mockAddress, "Filler", "", bondAddress, nil).Return(nil).Times(1)
+		signature, err = model.SignBondAddress(mockAddress, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(register(rest.RegisterFiller{Name: "Filler", BondAddress: bondAddress, BondSignature: signature})).To(Equal(http.StatusCreated))
+	})
+
+	It("should register the filler as its own bond address without a signature", func() {
+		mockStore.EXPECT().RegisterFiller(mockAddress, "Filler", "", "", nil).Return(nil).Times(1)
+		Expect(register(rest.RegisterFiller{Name: "Filler"})).To(Equal(http.StatusCreated))
+	})
+
+	DescribeTable("should check fillers which have not been scored with the score of new fillers",
+		func(newFillerScore uint64, profile *model.Filler, accepted bool) {
+			key, err := crypto.GenerateKey()
+			Expect(err).NotTo(HaveOccurred())
+			filler := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+			// a server requiring a score of 60 from the fillers
+			serverCtx, stop := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			server := rest.NewServer(mockStore, model.Config{MinFillerScore: 60, NewFillerScore: newFillerScore, Fillers: []string{filler}}, zap.NewNop(), mockSecret, rest.NewSocketPool(), mockScreener, feehub.NewFeehubClient(""), nil)
+			go func() {
+				defer close(stopped)
+				server.Run(serverCtx, ":8081")
+			}()
+			DeferCleanup(func() {
+				stop()
+				Eventually(stopped).Should(BeClosed())
+			})
+			Eventually(func() error {
+				resp, err := http.Get("http://localhost:8081/health")
+				if err != nil {
+					return err
+				}
+				return resp.Body.Close()
+			}).Should(Succeed())
+
+			if profile != nil {
+				mockStore.EXPECT().GetFiller(filler).Return(profile, nil).Times(1)
+			} else {
+				mockStore.EXPECT().GetFiller(filler).Return(nil, model.ErrNotFound).Times(1)
+			}
+			if accepted {
+				mockStore.EXPECT().CreateQuote(gomock.Any()).Return(nil).Times(1)
+			}
+
+			msg, err := rest.NewQuoteMessage(7, "1000", time.Now().Add(time.Minute), key)
+			Expect(err).NotTo(HaveOccurred())
+			ws := rest.NewWSClient("ws://localhost:8081", zap.NewNop())
+			ws.Subscribe(msg)
+			submitted := (<-ws.Listen()).(rest.SubmittedQuote)
+			if accepted {
+				Expect(submitted.Error).To(BeEmpty())
+			} else {
+				Expect(submitted.Error).To(ContainSubstring("below the minimum score"))
+			}
+		},
+		Entry("unknown filler", uint64(0), nil, false),
+		Entry("unknown filler with a configured score", uint64(70), nil, true),
+		Entry("registered filler which was not scored", uint64(0), &model.Filler{Registered: true, Score: 100}, false),
+		Entry("scored filler", uint64(0), &model.Filler{Registered: true, Score: 80, ScoredAt: &time.Time{}}, true),
+	)
+})
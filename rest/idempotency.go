@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// header set on responses replayed for a retried request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	DefaultIdempotencyTTL         = 24 * time.Hour
+	DefaultIdempotencyLockTimeout = time.Minute
+	MaxIdempotencyKeyLen          = 255
+)
+
+// records the response written by the handlers
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (s *Server) idempotencyTTL() time.Duration {
+	if s.config.IdempotencyTTL > 0 {
+		return time.Duration(s.config.IdempotencyTTL) * time.Second
+	}
+	return DefaultIdempotencyTTL
+}
+
+func (s *Server) idempotencyLockTimeout() time.Duration {
+	if s.config.IdempotencyLockTimeout > 0 {
+		return time.Duration(s.config.IdempotencyLockTimeout) * time.Second
+	}
+	return DefaultIdempotencyLockTimeout
+}
+
+// idempotent stores the first response to a request with an Idempotency-Key header
+// and replays it to retries by the same wallet, should be used after authenticate.
+// Reusing a key for a different request is rejected, and server errors are not
+// stored so that the request can be retried.
+func (s *Server) idempotent(ctx *gin.Context) {
+	key := ctx.GetHeader(IdempotencyKeyHeader)
+	if key == "" {
+		ctx.Next()
+		return
+	}
+	if len(key) > MaxIdempotencyKeyLen {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("idempotency key longer than %d characters", MaxIdempotencyKeyLen)})
+		ctx.Abort()
+		return
+	}
+	wallet := ctx.GetString("userWallet")
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read request: %v", err)})
+		ctx.Abort()
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	hash.Write([]byte(ctx.Request.Method + " " + ctx.Request.URL.Path + "\n"))
+	hash.Write(body)
+	fingerprint := hex.EncodeToString(hash.Sum(nil))
+
+	request, reserved, err := s.store.ReserveIdempotencyKey(wallet, key, fingerprint, s.idempotencyTTL(), s.idempotencyLockTimeout())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to reserve idempotency key: %v", err)})
+		ctx.Abort()
+		return
+	}
+	if !reserved {
+		switch {
+		case request.Fingerprint != fingerprint:
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "idempotency key was used for a different request"})
+		case request.Status == 0:
+			ctx.JSON(http.StatusConflict, gin.H{"error": "a request with this idempotency key is in progress"})
+		default:
+			ctx.Header(IdempotentReplayedHeader, "true")
+			ctx.Data(request.Status, "application/json; charset=utf-8", request.Response)
+		}
+		ctx.Abort()
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: ctx.Writer, body: new(bytes.Buffer)}
+	ctx.Writer = recorder
+	completed := false
+	defer func() {
+		// a panicking handler does not complete, its response is left to the recovery middleware
+		if !completed || recorder.Status() >= http.StatusInternalServerError {
+			if err := s.store.ReleaseIdempotencyKey(wallet, key); err != nil {
+				s.logger.Error("failed to release idempotency key", zap.String("wallet", wallet), zap.String("key", key), zap.Error(err))
+			}
+			return
+		}
+		if err := s.store.CompleteIdempotencyKey(wallet, key, recorder.Status(), recorder.body.Bytes()); err != nil {
+			s.logger.Error("failed to store idempotent response", zap.String("wallet", wallet), zap.String("key", key), zap.Error(err))
+		}
+	}()
+	ctx.Next()
+	completed = true
+}
@@ -0,0 +1,47 @@
+package rest_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/rest"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("idempotent requests", func() {
+	DescribeTable("should replay the stored response to a retried request",
+		func(path string) {
+			body := []byte(`{"orderPair":"bitcoin-ethereum"}`)
+			hash := sha256.Sum256(append([]byte(http.MethodPost+" "+path+"\n"), body...))
+			mockStore.EXPECT().ReserveIdempotencyKey(mockAddress, "key", hex.EncodeToString(hash[:]), gomock.Any(), gomock.Any()).Return(&model.IdempotentRequest{
+				Fingerprint: hex.EncodeToString(hash[:]),
+				Status:      http.StatusCreated,
+				Response:    []byte(`{"id":1}`),
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}, false, nil).Times(1)
+
+			req, err := http.NewRequest(http.MethodPost, "http://localhost:8080"+path, bytes.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", authToken(mockAddress))
+			req.Header.Set(rest.IdempotencyKeyHeader, "key")
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			Expect(resp.Header.Get(rest.IdempotentReplayedHeader)).To(Equal("true"))
+			response, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(MatchJSON(`{"id":1}`))
+		},
+		Entry("quote requests", "/quotes"),
+		Entry("fill intents", "/orders/5/intents"),
+	)
+})
@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultInventoryTTL is how long the inventory of a filler counts as available
+// after it was published when it is not configured
+const DefaultInventoryTTL = 10 * time.Minute
+
+// PublishInventory publishes a signed amount of an asset the filler has available,
+// or the address holding it for its balance to be read on chain
+type PublishInventory struct {
+	Chain model.Chain `json:"chain" binding:"required"`
+	Asset model.Asset `json:"asset" binding:"required"`
+	// amount signed by the filler at the publish time
+	Amount      string `json:"amount"`
+	PublishedAt int64  `json:"publishedAt"`
+	Signature   string `json:"signature"`
+	// address whose balance is read on chain instead
+	Address string `json:"address"`
+}
+
+type AssetsWithLiquidity struct {
+	Assets    map[model.Chain][]model.Asset `json:"assets"`
+	Liquidity []model.Liquidity             `json:"liquidity"`
+}
+
+func (s *Server) inventoryTTL() time.Duration {
+	if s.config.InventoryTTL > 0 {
+		return time.Duration(s.config.InventoryTTL) * time.Second
+	}
+	return DefaultInventoryTTL
+}
+
+// the registered fillers and their inventories which are still available
+func (s *Server) availableInventories() ([]model.Filler, []model.Inventory, error) {
+	fillers, err := s.store.GetFillers()
+	if err != nil {
+		return nil, nil, err
+	}
+	registered := []model.Filler{}
+	for _, filler := range fillers {
+		if filler.Registered {
+			registered = append(registered, filler)
+		}
+	}
+	inventories, err := s.store.GetInventories("", time.Now().Add(-s.inventoryTTL()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return registered, inventories, nil
+}
+
+// reports whether a registered filler, or one of the allowed fillers of a private
+// order, has the receive amount of an order available. The parts of partially
+// fillable orders can be covered by different fillers.
+func (s *Server) isCovered(orderPair string, receiveAmount *big.Int, terms model.OrderTerms) (bool, error) {
+	fillers, inventories, err := s.availableInventories()
+	if err != nil {
+		return false, err
+	}
+	available, err := model.FillerInventories(orderPair, fillers, inventories)
+	if err != nil {
+		return false, err
+	}
+	if len(terms.PartSecretHashes) > 0 {
+		total, _ := new(big.Int).SetString(model.PairLiquidity(orderPair, available).Amount, 10)
+		return total.Cmp(receiveAmount) >= 0, nil
+	}
+	return model.CanCover(available, receiveAmount, terms.AllowedFillers), nil
+}
+
+// publishes the inventory of the filler for a chain and asset
+func (s *Server) postInventory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filler, exists := c.Get("userWallet")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		req := PublishInventory{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		inventory := model.Inventory{
+			Filler:  strings.ToLower(filler.(string)),
+			Chain:   req.Chain,
+			Asset:   req.Asset,
+			Source:  model.InventoryOnChain,
+			Address: req.Address,
+		}
+		if req.Address == "" {
+			publishedAt := time.Unix(req.PublishedAt, 0).UTC()
+			inventory.Source = model.InventorySigned
+			inventory.Amount = req.Amount
+			inventory.PublishedAt = &publishedAt
+			inventory.Signature = req.Signature
+			signer, err := inventory.Signer()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if signer != inventory.Filler {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("inventory is signed by %s", signer)})
+				return
+			}
+		}
+		if err := s.store.PublishInventory(&inventory, s.config.Network); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to publish inventory: %v", err)})
+			return
+		}
+		c.JSON(http.StatusCreated, inventory)
+	}
+}
+
+// returns the inventories of a filler
+func (s *Server) getInventory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inventories, err := s.store.GetInventories(strings.ToLower(c.Param("address")), time.Time{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get inventory: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, inventories)
+	}
+}
+
+// the liquidity the registered fillers have available for every order pair
+func (s *Server) liquidity() ([]model.Liquidity, error) {
+	fillers, inventories, err := s.availableInventories()
+	if err != nil {
+		return nil, err
+	}
+	liquidity := []model.Liquidity{}
+	for _, pair := range s.config.Network.OrderPairs() {
+		available, err := model.FillerInventories(pair, fillers, inventories)
+		if err != nil {
+			return nil, err
+		}
+		liquidity = append(liquidity, model.PairLiquidity(pair, available))
+	}
+	return liquidity, nil
+}
@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultLotteryWindow is how long fillers can register their intent to fill a
+// lottery order when it is not configured
+const DefaultLotteryWindow = 5 * time.Second
+
+type FillIntent struct {
+	SendAddress    string `json:"sendAddress" binding:"required"`
+	ReceiveAddress string `json:"receiveAddress" binding:"required"`
+}
+
+func (s *Server) lotteryWindow() time.Duration {
+	if s.config.LotteryWindow > 0 {
+		return time.Duration(s.config.LotteryWindow) * time.Second
+	}
+	return DefaultLotteryWindow
+}
+
+// registers the intent of a filler to fill a lottery order with the given addresses
+func (s *Server) postFillIntent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode id has to be a number: %v", err.Error())})
+			return
+		}
+		filler, exists := c.Get("userWallet")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		req := FillIntent{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		order, err := s.store.GetOrder(uint(orderID))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("failed to get order: %v", err.Error())})
+			return
+		}
+
+		// Check if the addresses is blacklisted
+		senderChain, receiverChain, _, _, err := model.ParseOrderPair(order.OrderPair)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		blacklisted, err := s.screener.IsBlacklisted(map[string]model.Chain{
+			filler.(string):    model.Ethereum,
+			req.ReceiveAddress: senderChain,
+			req.SendAddress:    receiverChain,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if blacklisted {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Address is blacklisted from database"})
+			return
+		}
+
+		if err := s.checkFillerStanding(strings.ToLower(filler.(string))); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := s.store.RegisterFillIntent(uint(orderID), strings.ToLower(filler.(string)), req.SendAddress, req.ReceiveAddress, s.config.Network); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to register fill intent: %v", err.Error())})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	}
+}
+
+// returns the fill intents of a lottery order, with their weights and scores to
+// audit the draw against the revealed seed of the order
+func (s *Server) getFillIntents() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode id has to be a number: %v", err.Error())})
+			return
+		}
+		order, err := s.store.GetOrder(uint(orderID))
+		if errors.Is(err, model.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("order %d not found", orderID)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get order %s", err.Error())})
+			return
+		}
+		// the intents on private orders are hidden like the orders themselves
+		if !order.VisibleTo(s.viewer(c)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("order %d not found", orderID)})
+			return
+		}
+		intents, err := s.store.GetFillIntents(uint(orderID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get fill intents: %v", err.Error())})
+			return
+		}
+		c.JSON(http.StatusOK, intents)
+	}
+}
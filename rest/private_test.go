@@ -0,0 +1,110 @@
+package rest_test
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/rest"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+// authToken signs a token for the user like the server does after a login
+func authToken(user string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, rest.Claims{UserWallet: user}).SignedString([]byte(mockSecret))
+	Expect(err).NotTo(HaveOccurred())
+	return token
+}
+
+var _ = Describe("private orders", func() {
+	filler := "0x2234567890123456789012345678901234567890"
+	privateOrder := model.Order{
+		Model:          gorm.Model{ID: 5},
+		Maker:          mockAddress,
+		Status:         model.Created,
+		AllowedFillers: model.StringArray{filler},
+	}
+
+	get := func(path, user string) int {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost:8080"+path, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if user != "" {
+			req.Header.Set("Authorization", authToken(user))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		return resp.StatusCode
+	}
+
+	It("should hide the history of a private order from other users", func() {
+		mockStore.EXPECT().GetOrderHistory(uint(5)).Return(model.OrderHistory{OrderID: 5, Subject: privateOrder}, nil).Times(3)
+
+		Expect(get("/orders/5/history", "")).To(Equal(http.StatusNotFound))
+		Expect(get("/orders/5/history", "0x3234567890123456789012345678901234567890")).To(Equal(http.StatusNotFound))
+		Expect(get("/orders/5/history", filler)).To(Equal(http.StatusOK))
+	})
+
+	It("should hide the fill intents on a private order from other users", func() {
+		mockStore.EXPECT().GetOrder(uint(5)).Return(&privateOrder, nil).Times(3)
+		mockStore.EXPECT().GetFillIntents(uint(5)).Return([]model.FillIntent{}, nil).Times(1)
+
+		Expect(get("/orders/5/intents", "")).To(Equal(http.StatusNotFound))
+		Expect(get("/orders/5/intents", "0x3234567890123456789012345678901234567890")).To(Equal(http.StatusNotFound))
+		Expect(get("/orders/5/intents", mockAddress)).To(Equal(http.StatusOK))
+	})
+
+	It("should not send a private order to a stranger subscribed to its id", func() {
+		mockStore.EXPECT().GetOrder(uint(5)).Return(&privateOrder, nil).Times(1)
+
+		client.Subscribe("subscribe::5")
+		listener := client.Listen()
+		update := (<-listener).(rest.UpdatedOrder)
+		Expect(update.Error).To(Equal("order 5 not found"))
+		Expect(update.Order.ID).To(BeZero())
+
+		Expect(pool.FilterAndBufferOrder(privateOrder)).To(Succeed())
+		Consistently(listener, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should send a private order to an allowed filler subscribed to its id", func() {
+		mockStore.EXPECT().GetOrder(uint(5)).Return(&privateOrder, nil).Times(1)
+
+		conn, _, err := websocket.DefaultDialer.Dial("ws://localhost:8080", http.Header{"Authorization": {authToken(filler)}})
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+		Expect(conn.WriteMessage(websocket.TextMessage, []byte("subscribe::5"))).To(Succeed())
+
+		var update struct {
+			Type string            `json:"type"`
+			Msg  rest.UpdatedOrder `json:"msg"`
+		}
+		Expect(conn.ReadJSON(&update)).To(Succeed())
+		Expect(update.Msg.Error).To(BeEmpty())
+		Expect(update.Msg.Order.ID).To(Equal(uint(5)))
+	})
+
+	It("should only buffer updates of a private order for subscribers who can see it", func() {
+		stranger := make(chan rest.UpdatedOrder, 1)
+		allowed := make(chan rest.UpdatedOrder, 1)
+		pool.AddOrderUpdatesChannel(5, "", stranger)
+		pool.AddOrderUpdatesChannel(5, filler, allowed)
+
+		Expect(pool.FilterAndBufferOrder(privateOrder)).To(Succeed())
+		Expect(allowed).To(Receive())
+		Expect(stranger).NotTo(Receive())
+
+		// an order created from a quote is hidden until its taker fills it
+		quoted := model.Order{Model: gorm.Model{ID: 5}, Maker: mockAddress, Taker: filler, Status: model.Created}
+		Expect(pool.FilterAndBufferOrder(quoted)).To(Succeed())
+		Expect(allowed).To(Receive())
+		Expect(stranger).NotTo(Receive())
+		quoted.Status = model.Filled
+		Expect(pool.FilterAndBufferOrder(quoted)).To(Succeed())
+		Expect(stranger).To(Receive())
+	})
+})
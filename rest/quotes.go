@@ -0,0 +1,268 @@
+package rest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DefaultQuoteWindow is how long fillers can quote a request for quote when it is
+// not configured
+const DefaultQuoteWindow = 10 * time.Second
+
+type RequestQuote struct {
+	OrderPair  string `json:"orderPair" binding:"required"`
+	SendAmount string `json:"sendAmount" binding:"required"`
+}
+
+type AcceptQuote struct {
+	QuoteID              uint   `json:"quoteId" binding:"required"`
+	SendAddress          string `json:"sendAddress" binding:"required"`
+	ReceiveAddress       string `json:"receiveAddress" binding:"required"`
+	SecretHash           string `json:"secretHash" binding:"required"`
+	UserWalletBTCAddress string `json:"userWalletBTCAddress" binding:"required"`
+}
+
+// SubmitQuote is the quote a filler sends over the websocket as quote::<json>,
+// signed as model.Quote.Message
+type SubmitQuote struct {
+	QuoteRequestID uint   `json:"quoteRequestId"`
+	ReceiveAmount  string `json:"receiveAmount"`
+	// unix timestamp until which the quote can be accepted
+	ValidUntil int64  `json:"validUntil"`
+	Signature  string `json:"signature"`
+}
+
+type QuoteRequests struct {
+	Requests []model.QuoteRequest `json:"requests"`
+	Error    string               `json:"error"`
+}
+
+type SubmittedQuote struct {
+	Quote model.Quote `json:"quote"`
+	Error string      `json:"error"`
+}
+
+// NewQuoteMessage returns the websocket message quoting the receive amount for a
+// request for quote, signed with the key of the filler
+func NewQuoteMessage(requestID uint, receiveAmount string, validUntil time.Time, key *ecdsa.PrivateKey) (string, error) {
+	quote := model.Quote{QuoteRequestID: requestID, ReceiveAmount: receiveAmount, ValidUntil: time.Unix(validUntil.Unix(), 0)}
+	signature, err := quote.Sign(key)
+	if err != nil {
+		return "", err
+	}
+	msg, err := json.Marshal(SubmitQuote{QuoteRequestID: requestID, ReceiveAmount: receiveAmount, ValidUntil: validUntil.Unix(), Signature: signature})
+	if err != nil {
+		return "", err
+	}
+	return "quote::" + string(msg), nil
+}
+
+func (s *Server) quoteWindow() time.Duration {
+	if s.config.QuoteWindow > 0 {
+		return time.Duration(s.config.QuoteWindow) * time.Second
+	}
+	return DefaultQuoteWindow
+}
+
+// reports whether the wallet is one of the configured fillers or registered
+// itself as a filler
+func (s *Server) isFiller(wallet string) (bool, error) {
+	for _, filler := range s.config.Fillers {
+		if strings.ToLower(filler) == wallet {
+			return true, nil
+		}
+	}
+	profile, err := s.store.GetFiller(wallet)
+	if errors.Is(err, model.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return profile.Registered, nil
+}
+
+// creates a request for quote and sends it to the fillers subscribed to its pair
+func (s *Server) postQuoteRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requester, exists := c.Get("userWallet")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		req := RequestQuote{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		sendAmount, ok := new(big.Int).SetString(req.SendAmount, 10)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid send amount: %s", req.SendAmount)})
+			return
+		}
+		request, err := s.store.CreateQuoteRequest(strings.ToLower(requester.(string)), req.OrderPair, sendAmount, s.quoteWindow(), s.config)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to request quotes: %v", err.Error())})
+			return
+		}
+		// fillers connected to other replicas see the request when they subscribe again
+		s.socketPool.BufferQuoteRequest(*request)
+		c.JSON(http.StatusCreated, request)
+	}
+}
+
+// returns a request for quote with its quotes to its requester
+func (s *Server) getQuoteRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode id has to be a number: %v", err.Error())})
+			return
+		}
+		requester, exists := c.Get("userWallet")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		request, err := s.store.GetQuoteRequest(uint(requestID))
+		if errors.Is(err, model.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("quote request %d not found", requestID)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get quote request: %v", err.Error())})
+			return
+		}
+		// quotes are only shown to the requester so that fillers cannot undercut them
+		if request.Requester != strings.ToLower(requester.(string)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "quote request can only be read by its requester"})
+			return
+		}
+		c.JSON(http.StatusOK, request)
+	}
+}
+
+// accepts a quote, which creates an order only the filler who quoted can fill
+func (s *Server) acceptQuote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode id has to be a number: %v", err.Error())})
+			return
+		}
+		requester, exists := c.Get("userWallet")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+			return
+		}
+		req := AcceptQuote{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		request, err := s.store.GetQuoteRequest(uint(requestID))
+		if errors.Is(err, model.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("quote request %d not found", requestID)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get quote request: %v", err.Error())})
+			return
+		}
+
+		// Check if the addresses is blacklisted
+		senderChain, receiverChain, _, _, err := model.ParseOrderPair(request.OrderPair)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		blacklisted, err := s.screener.IsBlacklisted(map[string]model.Chain{
+			requester.(string): model.Ethereum,
+			req.ReceiveAddress: receiverChain,
+			req.SendAddress:    senderChain,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if blacklisted {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Address is blacklisted from database"})
+			return
+		}
+
+		oid, err := s.store.AcceptQuote(strings.ToLower(requester.(string)), uint(requestID), req.QuoteID, req.SendAddress, req.ReceiveAddress, req.SecretHash, req.UserWalletBTCAddress, s.config)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to accept quote: %v", err.Error())})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"orderId": oid})
+	}
+}
+
+// records a quote sent over the websocket by a registered filler
+func (s *Server) submitQuote(msg []byte) SubmittedQuote {
+	req := SubmitQuote{}
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return SubmittedQuote{Error: fmt.Sprintf("invalid quote: %v", err)}
+	}
+	quote := model.Quote{
+		QuoteRequestID: req.QuoteRequestID,
+		ReceiveAmount:  req.ReceiveAmount,
+		ValidUntil:     time.Unix(req.ValidUntil, 0).UTC(),
+		Signature:      req.Signature,
+	}
+	filler, err := quote.Signer()
+	if err != nil {
+		return SubmittedQuote{Error: err.Error()}
+	}
+	isFiller, err := s.isFiller(filler)
+	if err != nil {
+		return SubmittedQuote{Error: fmt.Sprintf("failed to get filler %s: %v", filler, err)}
+	}
+	if !isFiller {
+		return SubmittedQuote{Error: fmt.Sprintf("%s is not a registered filler", filler)}
+	}
+	if err := s.checkFillerStanding(filler); err != nil {
+		return SubmittedQuote{Error: err.Error()}
+	}
+	quote.Filler = filler
+	if err := s.store.CreateQuote(&quote); err != nil {
+		return SubmittedQuote{Error: fmt.Sprintf("failed to submit quote: %v", err)}
+	}
+	return SubmittedQuote{Quote: quote}
+}
+
+// streams the requests for quotes of a pair, starting with the open requests
+func (s *Server) subscribeToQuoteRequests(orderPair string, ctx context.Context) <-chan QuoteRequests {
+	responses := make(chan QuoteRequests)
+	go func() {
+		defer func() {
+			s.socketPool.RemoveQuoteRequestsChannel(orderPair, responses)
+			close(responses)
+		}()
+
+		s.socketPool.AddQuoteRequestsChannel(orderPair, responses)
+		requests, err := s.store.OpenQuoteRequests(orderPair)
+		if err != nil {
+			responses <- QuoteRequests{Error: fmt.Sprintf("failed to get quote requests for %s: %v", orderPair, err)}
+			s.logger.Error("failed to get quote requests", zap.Error(err))
+			return
+		}
+		responses <- QuoteRequests{Requests: requests}
+
+		<-ctx.Done()
+	}()
+	return responses
+}
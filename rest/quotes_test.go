@@ -0,0 +1,27 @@
+package rest_test
+
+import (
+	"net/http"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("quote requests", func() {
+	DescribeTable("should only answer 404 for quote requests which do not exist",
+		func(storeErr error, status int) {
+			mockStore.EXPECT().GetQuoteRequest(uint(7)).Return(nil, storeErr).Times(1)
+
+			req, err := http.NewRequest(http.MethodGet, "http://localhost:8080/quotes/7", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", authToken(mockAddress))
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+			Expect(resp.StatusCode).To(Equal(status))
+		},
+		Entry("unknown request", model.ErrNotFound, http.StatusNotFound),
+		Entry("store failure", errMock, http.StatusInternalServerError),
+	)
+})
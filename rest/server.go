@@ -2,6 +2,7 @@ package rest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
@@ -54,25 +55,83 @@ type AfterHook func() error
 
 type Store interface {
 	// get value locked in the given chain for the given user
-	ValueLockedByChain(chain model.Chain, config model.Network) (*big.Int, error)
+	ValueLockedByChain(chain model.Chain, config model.Network) (model.Decimal, error)
 	// create order
-	CreateOrder(creator, sendAddress, receiveAddress, orderPair, secretHash, userWalletBTCAddress string, sendAmount, receiveAmount, feeInBtc, feeInSeed *big.Int, IsDiscounted bool, config model.Config, feePayment ...AfterHook) (uint, error)
+	CreateOrder(creator, sendAddress, receiveAddress, orderPair, secretHash, userWalletBTCAddress string, sendAmount, receiveAmount, feeInBtc, feeInSeed *big.Int, IsDiscounted bool, terms model.OrderTerms, config model.Config, feePayment ...AfterHook) (uint, error)
 	// fill order
 	FillOrder(orderID uint, filler, sendAddress, receiveAddress string, config model.Network) error
+	// fill a part of a partially fillable order, returns the id of the child order filling it
+	FillOrderPart(orderID uint, filler, sendAddress, receiveAddress string, amount *big.Int, config model.Network) (uint, error)
+	// create a request for quote which fillers can quote within the window
+	CreateQuoteRequest(requester, orderPair string, sendAmount *big.Int, window time.Duration, config model.Config) (*model.QuoteRequest, error)
+	// record the signed quote of a filler
+	CreateQuote(quote *model.Quote) error
+	// get a request for quote with its quotes
+	GetQuoteRequest(id uint) (*model.QuoteRequest, error)
+	// get the requests for quotes of a pair which can still be quoted
+	OpenQuoteRequests(orderPair string) ([]model.QuoteRequest, error)
+	// accept a quote, returns the id of the order created for it
+	AcceptQuote(requester string, requestID, quoteID uint, sendAddress, receiveAddress, secretHash, userBtcWalletAddress string, config model.Config) (uint, error)
+	// register the intent of a filler to fill a lottery order
+	RegisterFillIntent(orderID uint, filler, sendAddress, receiveAddress string, config model.Network) error
+	// get the fill intents of a lottery order
+	GetFillIntents(orderID uint) ([]model.FillIntent, error)
+	// register or update the profile of a filler
+	RegisterFiller(address, name, contact, bondAddress string, pairs []string) error
+	// get the fillers with their reputation
+	GetFillers() ([]model.Filler, error)
+	// get the filler with the given address
+	GetFiller(address string) (*model.Filler, error)
+	// get the bond of a filler and what it owes from slashed penalties
+	GetBond(filler string) (*model.Bond, error)
+	// get the penalties of a filler or of all fillers, with a status if one is given
+	GetPenalties(filler string, status model.PenaltyStatus) ([]model.Penalty, error)
+	// settle a pending penalty by slashing the bond of its filler or waiving it
+	SettlePenalty(id uint, status model.PenaltyStatus, amount, settledBy, note string) (*model.Penalty, error)
+	// publish the inventory of a filler for a chain and asset
+	PublishInventory(inventory *model.Inventory, config model.Network) error
+	// get the inventories of a filler or of all fillers, published since the given time if one is given
+	GetInventories(filler string, since time.Time) ([]model.Inventory, error)
 	// get order by id
 	GetOrder(orderID uint) (*model.Order, error)
 	// get order by atomic swap id
 	GetOrderBySwapID(swapID uint) (*model.Order, error)
+	// get the state transition history of an order and its atomic swaps
+	GetOrderHistory(orderID uint) (model.OrderHistory, error)
 	// get orders by address
 	GetOrdersByAddress(address string) ([]model.Order, error)
 
 	GetPendingOrdersForAddress(address string) ([]model.Order, error)
 	// cancel order by id
 	CancelOrder(creator string, orderID uint) error
-	// get all orders for the given user
-	FilterOrders(maker, taker, orderPair, secretHash string, status model.Status, minPrice, maxPrice float64, minAmount, maxAmount float64, page, perPage int, verbose bool) ([]model.Order, error)
+	// get the rolled up statistics of the orders created in [from, to)
+	OrderStats(granularity model.Granularity, from, to time.Time, orderPair string) ([]model.OrderStats, error)
+	// get the rolled up statistics of the executed orders of a wallet created in [from, to)
+	UserStats(wallet string, granularity model.Granularity, from, to time.Time) ([]model.UserStats, error)
+	// get the value locked on a chain, or on every chain, at the snapshots taken in [from, to)
+	TVLSeries(chain model.Chain, from, to time.Time) ([]model.TVLPoint, error)
+	// get the most recent trades of a pair, or of all pairs, executed in [from, to)
+	Trades(orderPair string, from, to time.Time, limit int) ([]model.Trade, error)
+	// get the candles of the trades of a pair executed in [from, to)
+	Candles(orderPair string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error)
+	// get the orders matching the filter and the cursor of the next page
+	FilterOrders(filter model.OrderFilter) ([]model.Order, string, error)
 
 	GetSecrets(lastUpdated time.Time) ([]model.SecretRevealed, error)
+
+	// grant a wallet specific limit override
+	GrantUserLimit(limit *model.UserLimit) error
+	// get the limit overrides of a wallet, or of all wallets if it is empty
+	GetUserLimits(wallet string, history bool) ([]model.UserLimit, error)
+	// revoke a limit override
+	RevokeUserLimit(id uint, revokedBy string) error
+
+	// reserve an idempotency key of a wallet, or get the request it is already reserved for
+	ReserveIdempotencyKey(wallet, key, fingerprint string, ttl, lockTimeout time.Duration) (*model.IdempotentRequest, bool, error)
+	// store the response to a request with a reserved idempotency key
+	CompleteIdempotencyKey(wallet, key string, status int, response []byte) error
+	// release a reserved idempotency key without a response
+	ReleaseIdempotencyKey(wallet, key string) error
 }
 
 func NewServer(store Store, config model.Config, logger *zap.Logger, secret string, socketPool SocketPool, screener screener.Screener, fhClient *feehub.FeehubClient, pc price.PriceFetcher) *Server {
@@ -95,8 +154,8 @@ func (s *Server) Run(ctx context.Context, addr string) error {
 	s.router.Use(cors.New(cors.Config{
 		AllowAllOrigins:  true,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", IdempotencyKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", IdempotentReplayedHeader},
 		AllowCredentials: true,
 	}))
 
@@ -108,16 +167,43 @@ func (s *Server) Run(ctx context.Context, addr string) error {
 
 	s.router.GET("/health", s.health())
 	s.router.GET("/orders/:id", s.getOrder())
+	s.router.GET("/orders/:id/history", s.getOrderHistory())
+	s.router.GET("/orders/:id/intents", s.getFillIntents())
 	s.router.GET("/orders", s.getOrders())
+	s.router.GET("/orderbook", s.getBestOrders())
 	s.router.GET("/nonce", s.nonce())
 	s.router.GET("/assets", s.supportedAssets())
 	s.router.GET("/chains/:chain/value", s.getValueByChain())
+	s.router.GET("/stats/orders", s.getOrderStats())
+	s.router.GET("/stats/users/:address", s.getUserStats())
+	s.router.GET("/stats/tvl", s.getTVL())
+	s.router.GET("/trades", s.getTrades())
+	s.router.GET("/candles", s.getCandles())
+	s.router.GET("/fillers", s.getFillers())
+	s.router.GET("/fillers/:address/bond", s.getBond())
+	s.router.GET("/fillers/:address/inventory", s.getInventory())
 	s.router.GET("/secrets", s.secrets())
 	s.router.POST("/verify", s.verify())
 	{
-		authRoutes.POST("/orders", s.postOrders())
-		authRoutes.PUT("/orders/:id", s.fillOrder())
+		authRoutes.POST("/orders", s.idempotent, s.postOrders())
+		authRoutes.PUT("/orders/:id", s.idempotent, s.fillOrder())
 		authRoutes.DELETE("/orders/:id", s.cancelOrder())
+		authRoutes.POST("/orders/:id/intents", s.idempotent, s.postFillIntent())
+		authRoutes.POST("/fillers", s.idempotent, s.postFiller())
+		authRoutes.POST("/fillers/inventory", s.idempotent, s.postInventory())
+		authRoutes.POST("/quotes", s.idempotent, s.postQuoteRequest())
+		authRoutes.GET("/quotes/:id", s.getQuoteRequest())
+		authRoutes.POST("/quotes/:id/accept", s.idempotent, s.acceptQuote())
+	}
+
+	adminRoutes := authRoutes.Group("/admin")
+	adminRoutes.Use(s.authorizeAdmin)
+	{
+		adminRoutes.POST("/limits", s.grantUserLimit())
+		adminRoutes.GET("/limits", s.getUserLimits())
+		adminRoutes.DELETE("/limits/:id", s.revokeUserLimit())
+		adminRoutes.GET("/penalties", s.getPenalties())
+		adminRoutes.POST("/penalties/:id/settle", s.settlePenalty())
 	}
 
 	server := &http.Server{
@@ -147,6 +233,20 @@ type CreateOrder struct {
 	FeePayment           feehub.ConditionalPayment `json:"feePayment"`
 	Filler               string                    `json:"filler"`
 	IsDiscounted         bool                      `json:"isDiscounted"`
+	// market, limit or auction, limit orders expire at the unix timestamp ExpiresAt
+	// and auctions decay from the receive amount to AuctionEndAmount over
+	// AuctionDuration seconds
+	Type             string `json:"type"`
+	ExpiresAt        int64  `json:"expiresAt"`
+	AuctionEndAmount string `json:"auctionEndAmount"`
+	AuctionDuration  int64  `json:"auctionDuration"`
+	// orders with part secret hashes can be filled in parts, one for each hash
+	PartSecretHashes []string `json:"partSecretHashes"`
+	// private orders can only be filled by one of the allowed fillers
+	AllowedFillers []string `json:"allowedFillers"`
+	// lottery orders are assigned to one of the fillers who register their intent
+	// to fill them shortly after creation
+	Lottery bool `json:"lottery"`
 }
 
 type Auth interface {
@@ -162,39 +262,55 @@ func (s *Server) authenticate(ctx *gin.Context) {
 		return
 	}
 
+	userWallet, err := s.parseToken(tokenString)
+	if err != nil {
+		s.logger.Debug("authorization failure", zap.Error(err))
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		ctx.Abort()
+		return
+	}
+	ctx.Set("userWallet", userWallet)
+	ctx.Set("token", tokenString)
+
+	ctx.Next()
+}
+
+// parseToken returns the wallet of a valid jwt issued by the server
+func (s *Server) parseToken(tokenString string) (string, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			s.logger.Debug("authorization failure", zap.Error(fmt.Errorf("invalid signing method")))
 			return nil, fmt.Errorf("invalid signing method")
 		}
 
 		return []byte(s.secret), nil
 	})
-
 	if err != nil {
-		s.logger.Debug("authorization failure", zap.Error(err))
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		ctx.Abort()
-		return
+		return "", err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if userWallet, exists := claims["userWallet"]; exists {
-			ctx.Set("userWallet", strings.ToLower(userWallet.(string)))
-			ctx.Set("token", tokenString)
-		} else {
-			s.logger.Debug("authorization failure", zap.Error(fmt.Errorf("invalid token claims")))
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
-			ctx.Abort()
-			return
-		}
-	} else {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token claims"})
-		ctx.Abort()
-		return
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return "", fmt.Errorf("invalid token claims")
+	}
+	userWallet, ok := claims["userWallet"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid token claims")
 	}
+	return strings.ToLower(userWallet), nil
+}
 
-	ctx.Next()
+// viewer returns the wallet of the caller of a public route, which is empty when
+// the caller is not authenticated
+func (s *Server) viewer(c *gin.Context) string {
+	tokenString := c.GetHeader("Authorization")
+	if tokenString == "" {
+		return ""
+	}
+	userWallet, err := s.parseToken(tokenString)
+	if err != nil {
+		return ""
+	}
+	return userWallet
 }
 
 func (s *Server) health() gin.HandlerFunc {
@@ -215,10 +331,10 @@ func (s *Server) getValueByChain() gin.HandlerFunc {
 
 		valueLocked, err := s.store.ValueLockedByChain(chain, s.config.Network)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		c.JSON(http.StatusCreated, gin.H{
+		c.JSON(http.StatusOK, gin.H{
 			"value": valueLocked,
 		})
 	}
@@ -233,7 +349,16 @@ func (s *Server) supportedAssets() gin.HandlerFunc {
 		}
 	}
 	return func(c *gin.Context) {
-		c.JSON(http.StatusCreated, assets)
+		if c.Query("liquidity") != "true" {
+			c.JSON(http.StatusCreated, assets)
+			return
+		}
+		liquidity, err := s.liquidity()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get liquidity: %v", err)})
+			return
+		}
+		c.JSON(http.StatusCreated, AssetsWithLiquidity{Assets: assets, Liquidity: liquidity})
 	}
 }
 
@@ -295,6 +420,46 @@ func (s *Server) postOrders() gin.HandlerFunc {
 			return
 		}
 
+		orderType, err := model.ParseOrderType(req.Type)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		terms := model.OrderTerms{Type: orderType}
+		if req.ExpiresAt != 0 {
+			terms.ExpiresAt = time.Unix(req.ExpiresAt, 0).UTC()
+		}
+		if req.AuctionEndAmount != "" {
+			auctionEndAmount, ok := new(big.Int).SetString(req.AuctionEndAmount, 10)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid auction end amount: %s", req.AuctionEndAmount)})
+				return
+			}
+			terms.AuctionEndAmount = auctionEndAmount
+		}
+		terms.AuctionDuration = time.Duration(req.AuctionDuration) * time.Second
+		terms.PartSecretHashes = req.PartSecretHashes
+		terms.AllowedFillers = req.AllowedFillers
+		if req.Lottery {
+			terms.LotteryWindow = s.lotteryWindow()
+		}
+
+		warning := ""
+		if s.config.InventoryCheck != "" {
+			covered, err := s.isCovered(req.OrderPair, receiveAmount, terms)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to check inventories: %v", err)})
+				return
+			}
+			if !covered {
+				warning = fmt.Sprintf("no registered filler can cover the receive amount %s of %s", receiveAmount, req.OrderPair)
+				if s.config.InventoryCheck == model.InventoryReject {
+					c.JSON(http.StatusBadRequest, gin.H{"error": warning})
+					return
+				}
+			}
+		}
+
 		var payfeehook AfterHook = nil
 
 		feeInBtc := big.NewInt(0)
@@ -346,6 +511,7 @@ func (s *Server) postOrders() gin.HandlerFunc {
 			feeInBtc,
 			req.FeePayment.HTLC.RecvAmount.Int,
 			req.IsDiscounted,
+			terms,
 			s.config,
 			payfeehook)
 		if err != nil {
@@ -357,6 +523,13 @@ func (s *Server) postOrders() gin.HandlerFunc {
 			return
 		}
 
+		if warning != "" {
+			c.JSON(http.StatusCreated, gin.H{
+				"orderId": oid,
+				"warning": warning,
+			})
+			return
+		}
 		c.JSON(http.StatusCreated, gin.H{
 			"orderId": oid,
 		})
@@ -366,6 +539,8 @@ func (s *Server) postOrders() gin.HandlerFunc {
 type FillOrder struct {
 	SendAddress    string `json:"sendAddress" binding:"required"`
 	ReceiveAddress string `json:"receiveAddress" binding:"required"`
+	// the part of the send amount of a partially fillable order to fill
+	Amount string `json:"amount"`
 }
 
 func (s *Server) fillOrder() gin.HandlerFunc {
@@ -421,6 +596,28 @@ func (s *Server) fillOrder() gin.HandlerFunc {
 			return
 		}
 
+		if err := s.checkFillerStanding(strings.ToLower(filler.(string))); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.Amount != "" {
+			amount, ok := new(big.Int).SetString(req.Amount, 10)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid fill amount: %s", req.Amount)})
+				return
+			}
+			childID, err := s.store.FillOrderPart(uint(orderID), strings.ToLower(filler.(string)), req.SendAddress, req.ReceiveAddress, amount, s.config.Network)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": fmt.Sprintf("failed to fill the Order %v", err.Error()),
+				})
+				return
+			}
+			c.JSON(http.StatusAccepted, gin.H{"orderId": childID})
+			return
+		}
+
 		if err := s.store.FillOrder(uint(orderID), strings.ToLower(filler.(string)), req.SendAddress, req.ReceiveAddress, s.config.Network); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("failed to fill the Order %v", err.Error()),
@@ -443,11 +640,44 @@ func (s *Server) getOrder() gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("failed to get order %s", err.Error()),
 			})
+			return
+		}
+		// private orders are hidden as if they did not exist
+		if !order.VisibleTo(s.viewer(c)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("order %d not found", orderID)})
+			return
 		}
 		c.JSON(http.StatusOK, order)
 	}
 }
 
+func (s *Server) getOrderHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode id has to be a number: %v", err.Error())})
+			return
+		}
+		history, err := s.store.GetOrderHistory(uint(orderID))
+		if errors.Is(err, model.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("order %d not found", orderID)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("failed to get order history %s", err.Error()),
+			})
+			return
+		}
+		// the history of private orders is hidden like the orders themselves
+		if !history.VisibleTo(s.viewer(c)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("order %d not found", orderID)})
+			return
+		}
+		c.JSON(http.StatusOK, history)
+	}
+}
+
 func (s *Server) cancelOrder() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// TODO: extract from auth token
@@ -472,6 +702,19 @@ func (s *Server) cancelOrder() gin.HandlerFunc {
 	}
 }
 
+// number of orders in a page of orders paginated by a cursor
+const (
+	DefaultOrdersLimit = 100
+	MaxOrdersLimit     = 1000
+)
+
+// OrdersPage is a page of orders, the next page is requested with its cursor
+// which is empty on the last page
+type OrdersPage struct {
+	Orders     []model.Order `json:"orders"`
+	NextCursor string        `json:"next_cursor"`
+}
+
 func (s *Server) getOrders() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		maker := c.DefaultQuery("maker", "")
@@ -527,7 +770,78 @@ func (s *Server) getOrders() gin.HandlerFunc {
 			return
 		}
 
-		orders, err := s.store.FilterOrders(maker, taker, orderPair, secretHash, model.Status(status), minPrice, maxPrice, minAmount, maxAmount, page, perPage, verbose)
+		filter := model.OrderFilter{
+			Maker:      maker,
+			Taker:      taker,
+			OrderPair:  orderPair,
+			SecretHash: secretHash,
+			Status:     model.Status(status),
+			MinPrice:   minPrice,
+			MaxPrice:   maxPrice,
+			MinAmount:  minAmount,
+			MaxAmount:  maxAmount,
+			Chain:      model.Chain(c.DefaultQuery("chain", "")),
+			Asset:      model.Asset(c.DefaultQuery("asset", "")),
+			TxHash:     c.DefaultQuery("tx_hash", ""),
+			Sort:       c.DefaultQuery("sort", ""),
+			Cursor:     c.DefaultQuery("cursor", ""),
+			Page:       page,
+			PerPage:    perPage,
+			Verbose:    verbose,
+			// private orders are only listed for the parties to them
+			HidePrivate: true,
+			Viewer:      s.viewer(c),
+		}
+		if swapStatus := c.DefaultQuery("swap_status", ""); swapStatus != "" {
+			value, err := strconv.Atoi(swapStatus)
+			if err != nil || value < int(model.NotStarted) || value > int(model.Refunded) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode swap_status has to be a number between %d and %d", model.NotStarted, model.Refunded)})
+				return
+			}
+			status := model.SwapStatus(value)
+			filter.SwapStatus = &status
+		}
+		for param, createdAt := range map[string]*time.Time{"created_after": &filter.CreatedAfter, "created_before": &filter.CreatedBefore} {
+			value := c.DefaultQuery(param, "")
+			if value == "" {
+				continue
+			}
+			unix, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode %s has to be a unix timestamp: %v", param, err.Error())})
+				return
+			}
+			*createdAt = time.Unix(unix, 0).UTC()
+		}
+		if sort := strings.TrimPrefix(filter.Sort, "-"); sort != "" && sort != model.SortByID && sort != model.SortByCreatedAt && sort != model.SortByPrice {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode sort has to be one of %s, %s or %s, prefixed with a - for descending order", model.SortByID, model.SortByCreatedAt, model.SortByPrice)})
+			return
+		}
+		if orderType := c.DefaultQuery("type", ""); orderType != "" {
+			if filter.Type, err = model.ParseOrderType(orderType); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		// orders are paginated by a cursor when a limit or a cursor is given,
+		// other requests get all orders, or a page of them, as before
+		_, hasLimit := c.GetQuery("limit")
+		paginated := hasLimit || filter.Cursor != ""
+		if paginated {
+			limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(DefaultOrdersLimit)))
+			if err != nil || limit <= 0 || limit > MaxOrdersLimit {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode limit has to be a number between 1 and %d", MaxOrdersLimit)})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		orders, nextCursor, err := s.store.FilterOrders(filter)
+		if errors.Is(err, model.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("failed to get orders %s", err.Error()),
@@ -535,6 +849,44 @@ func (s *Server) getOrders() gin.HandlerFunc {
 			return
 		}
 
+		if paginated {
+			c.JSON(http.StatusOK, OrdersPage{Orders: orders, NextCursor: nextCursor})
+			return
+		}
+		c.JSON(http.StatusOK, orders)
+	}
+}
+
+// getBestOrders returns the open limit orders of a pair which have not expired
+// and anyone can fill, best priced for a filler first. The price of an order is
+// the amount it sends per unit of the amount it receives, so fillers are best off
+// with the highest.
+func (s *Server) getBestOrders() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orderPair := c.Query("order_pair")
+		if orderPair == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order_pair is required"})
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(DefaultOrdersLimit)))
+		if err != nil || limit <= 0 || limit > MaxOrdersLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode limit has to be a number between 1 and %d", MaxOrdersLimit)})
+			return
+		}
+		orders, _, err := s.store.FilterOrders(model.OrderFilter{
+			OrderPair:    orderPair,
+			Status:       model.Created,
+			Type:         model.LimitOrder,
+			ExpiresAfter: time.Now().UTC(),
+			Unassigned:   true,
+			Sort:         "-" + model.SortByPrice,
+			Limit:        limit,
+			Verbose:      true,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get orders %s", err.Error())})
+			return
+		}
 		c.JSON(http.StatusOK, orders)
 	}
 }
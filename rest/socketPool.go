@@ -10,17 +10,31 @@ type socketPool struct {
 	mu                *sync.RWMutex
 	updatedOrdersPool map[string][]chan UpdatedOrders
 	OpenOrdersPool    map[string][]chan OpenOrders
-	orderUpdatesPool  map[uint][]chan UpdatedOrder
+	orderUpdatesPool  map[uint][]orderUpdatesChannel
+	tradesPool        map[string][]chan model.Order
+	quoteRequestsPool map[string][]chan QuoteRequests
+}
+
+// a subscriber to the updates of an order, private orders are only sent to
+// subscribers who can see them
+type orderUpdatesChannel struct {
+	viewer  string
+	channel chan UpdatedOrder
 }
 
 type SocketPool interface {
 	FilterAndBufferOrder(order model.Order) error
 	AddUpdatedOrdersChannel(creator string, channel chan UpdatedOrders)
 	AddOpenOrdersChannel(orderPair string, channel chan OpenOrders)
-	AddOrderUpdatesChannel(id uint, channel chan UpdatedOrder)
+	AddOrderUpdatesChannel(id uint, viewer string, channel chan UpdatedOrder)
 	RemoveUpdatedOrdersChannel(creator string, channel chan UpdatedOrders)
 	RemoveOpenOrdersChannel(orderPair string, channel chan OpenOrders)
 	RemoveOrderUpdatesChannel(id uint, channel chan UpdatedOrder)
+	AddTradesChannel(orderPair string, channel chan model.Order)
+	RemoveTradesChannel(orderPair string, channel chan model.Order)
+	BufferQuoteRequest(request model.QuoteRequest)
+	AddQuoteRequestsChannel(orderPair string, channel chan QuoteRequests)
+	RemoveQuoteRequestsChannel(orderPair string, channel chan QuoteRequests)
 }
 
 func NewSocketPool() SocketPool {
@@ -28,7 +42,9 @@ func NewSocketPool() SocketPool {
 		mu:                new(sync.RWMutex),
 		updatedOrdersPool: make(map[string][]chan UpdatedOrders),
 		OpenOrdersPool:    make(map[string][]chan OpenOrders),
-		orderUpdatesPool:  make(map[uint][]chan UpdatedOrder),
+		orderUpdatesPool:  make(map[uint][]orderUpdatesChannel),
+		tradesPool:        make(map[string][]chan model.Order),
+		quoteRequestsPool: make(map[string][]chan QuoteRequests),
 	}
 }
 
@@ -37,11 +53,17 @@ func (s *socketPool) FilterAndBufferOrder(order model.Order) error {
 	users := []string{order.Maker}
 	if order.Taker != "" {
 		users = append(users, order.Taker)
+	} else if len(order.AllowedFillers) > 0 {
+		// private orders are only sent to the fillers allowed to fill them
+		users = append(users, order.AllowedFillers...)
 	} else {
 		s.bufferOpenOrders(order.OrderPair, []model.Order{order})
 	}
 	s.bufferUpdatedOrders(users, []model.Order{order})
 	s.bufferOrderUpdates(order.ID, order)
+	if order.Status == model.Executed {
+		s.bufferTrades(order.OrderPair, order)
+	}
 	return nil
 }
 func (s *socketPool) bufferUpdatedOrders(users []string, orders []model.Order) {
@@ -59,8 +81,11 @@ func (s *socketPool) bufferOrderUpdates(orderId uint, order model.Order) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, chann := range (s.orderUpdatesPool)[orderId] {
-		chann <- UpdatedOrder{
+	for _, subscriber := range (s.orderUpdatesPool)[orderId] {
+		if !order.VisibleTo(subscriber.viewer) {
+			continue
+		}
+		subscriber.channel <- UpdatedOrder{
 			Order: order,
 		}
 
@@ -77,6 +102,14 @@ func (s *socketPool) bufferOpenOrders(orderPair string, orders []model.Order) {
 
 	}
 }
+func (s *socketPool) bufferTrades(orderPair string, order model.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, chann := range (s.tradesPool)[orderPair] {
+		chann <- order
+	}
+}
 func (s *socketPool) AddUpdatedOrdersChannel(creator string, channel chan UpdatedOrders) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -88,10 +121,10 @@ func (s *socketPool) AddOpenOrdersChannel(orderPair string, channel chan OpenOrd
 	(s.OpenOrdersPool)[orderPair] = append((s.OpenOrdersPool)[orderPair], channel)
 }
 
-func (s *socketPool) AddOrderUpdatesChannel(id uint, channel chan UpdatedOrder) {
+func (s *socketPool) AddOrderUpdatesChannel(id uint, viewer string, channel chan UpdatedOrder) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	(s.orderUpdatesPool)[id] = append((s.orderUpdatesPool)[id], channel)
+	(s.orderUpdatesPool)[id] = append((s.orderUpdatesPool)[id], orderUpdatesChannel{viewer: viewer, channel: channel})
 }
 
 func (s *socketPool) RemoveUpdatedOrdersChannel(creator string, channel chan UpdatedOrders) {
@@ -130,9 +163,58 @@ func (s *socketPool) RemoveOrderUpdatesChannel(id uint, channel chan UpdatedOrde
 		return
 	}
 	for m, n := range (s.orderUpdatesPool)[id] {
-		if n == channel {
+		if n.channel == channel {
 			(s.orderUpdatesPool)[id] = append((s.orderUpdatesPool)[id][0:m], (s.orderUpdatesPool)[id][m+1:len((s.orderUpdatesPool)[id])]...)
 			return
 		}
 	}
 }
+
+func (s *socketPool) AddTradesChannel(orderPair string, channel chan model.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	(s.tradesPool)[orderPair] = append((s.tradesPool)[orderPair], channel)
+}
+func (s *socketPool) RemoveTradesChannel(orderPair string, channel chan model.Order) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for m, n := range (s.tradesPool)[orderPair] {
+		if n == channel {
+			(s.tradesPool)[orderPair] = append((s.tradesPool)[orderPair][0:m], (s.tradesPool)[orderPair][m+1:]...)
+			break
+		}
+	}
+	if len((s.tradesPool)[orderPair]) == 0 {
+		delete(s.tradesPool, orderPair)
+	}
+}
+
+// sends a new request for quote to the fillers subscribed to its order pair
+func (s *socketPool) BufferQuoteRequest(request model.QuoteRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, chann := range (s.quoteRequestsPool)[request.OrderPair] {
+		chann <- QuoteRequests{
+			Requests: []model.QuoteRequest{request},
+		}
+	}
+}
+func (s *socketPool) AddQuoteRequestsChannel(orderPair string, channel chan QuoteRequests) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	(s.quoteRequestsPool)[orderPair] = append((s.quoteRequestsPool)[orderPair], channel)
+}
+func (s *socketPool) RemoveQuoteRequestsChannel(orderPair string, channel chan QuoteRequests) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for m, n := range (s.quoteRequestsPool)[orderPair] {
+		if n == channel {
+			(s.quoteRequestsPool)[orderPair] = append((s.quoteRequestsPool)[orderPair][0:m], (s.quoteRequestsPool)[orderPair][m+1:]...)
+			break
+		}
+	}
+	if len((s.quoteRequestsPool)[orderPair]) == 0 {
+		delete(s.quoteRequestsPool, orderPair)
+	}
+}
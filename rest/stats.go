@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/gin-gonic/gin"
+)
+
+// number of buckets of order statistics returned when no range is given, and at most
+const (
+	DefaultStatsBuckets = 30
+	MaxStatsBuckets     = 1000
+)
+
+// parses the interval, from and to query parameters of the analytics api, from
+// and to are unix timestamps and default to the last DefaultStatsBuckets buckets
+func parseStatsRange(c *gin.Context) (model.Granularity, time.Time, time.Time, error) {
+	granularity, err := model.ParseGranularity(c.DefaultQuery("interval", string(model.Daily)))
+	if err != nil {
+		return "", time.Time{}, time.Time{}, err
+	}
+	from, to, err := parseTimeRange(c, func(to time.Time) time.Time {
+		return granularity.Bucket(to).Add(-(DefaultStatsBuckets - 1) * granularity.Duration())
+	})
+	if err != nil {
+		return "", time.Time{}, time.Time{}, err
+	}
+	if to.Sub(granularity.Bucket(from)) > MaxStatsBuckets*granularity.Duration() {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("range exceeds %d buckets of a %s", MaxStatsBuckets, granularity)
+	}
+	return granularity, from, to, nil
+}
+
+// parses the from and to unix timestamps, to defaults to now and from to the
+// given start of the default range ending at to
+func parseTimeRange(c *gin.Context, defaultFrom func(to time.Time) time.Time) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if c.Query("to") != "" {
+		unix, err := strconv.ParseInt(c.Query("to"), 10, 64)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("failed to decode to has to be a unix timestamp: %v", err)
+		}
+		to = time.Unix(unix, 0).UTC()
+	}
+	from := defaultFrom(to)
+	if c.Query("from") != "" {
+		unix, err := strconv.ParseInt(c.Query("from"), 10, 64)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("failed to decode from has to be a unix timestamp: %v", err)
+		}
+		from = time.Unix(unix, 0).UTC()
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from has to be before to")
+	}
+	return from, to, nil
+}
+
+func (s *Server) getOrderStats() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granularity, from, to, err := parseStatsRange(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		stats, err := s.store.OrderStats(granularity, from, to, c.Query("order_pair"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get order stats: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
+func (s *Server) getUserStats() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granularity, from, to, err := parseStatsRange(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		stats, err := s.store.UserStats(strings.ToLower(c.Param("address")), granularity, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get user stats: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+	}
+}
+
+// DefaultTVLRange is the range of the value locked returned when no from is given
+const DefaultTVLRange = 24 * time.Hour
+
+func (s *Server) getTVL() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var chain model.Chain
+		if c.Query("chain") != "" {
+			parsed, err := model.ParseChain(c.Query("chain"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			chain = parsed
+		}
+		from, to, err := parseTimeRange(c, func(to time.Time) time.Time { return to.Add(-DefaultTVLRange) })
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		points, err := s.store.TVLSeries(chain, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get value locked: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, points)
+	}
+}
@@ -0,0 +1,220 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// number of trades returned when no limit is given, and at most
+const (
+	DefaultTradesLimit = 100
+	MaxTradesLimit     = 1000
+)
+
+// parses the interval, from and to query parameters of the candles, from and to
+// are unix timestamps and default to the last DefaultStatsBuckets candles
+func parseCandleRange(c *gin.Context) (model.CandleInterval, time.Time, time.Time, error) {
+	interval, err := model.ParseCandleInterval(c.DefaultQuery("interval", string(model.Hour1)))
+	if err != nil {
+		return "", time.Time{}, time.Time{}, err
+	}
+	from, to, err := parseTimeRange(c, func(to time.Time) time.Time {
+		return interval.Bucket(to).Add(-(DefaultStatsBuckets - 1) * interval.Duration())
+	})
+	if err != nil {
+		return "", time.Time{}, time.Time{}, err
+	}
+	if to.Sub(interval.Bucket(from)) > MaxStatsBuckets*interval.Duration() {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("range exceeds %d candles of %s", MaxStatsBuckets, interval)
+	}
+	return interval, from, to, nil
+}
+
+func (s *Server) getTrades() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(DefaultTradesLimit)))
+		if err != nil || limit <= 0 || limit > MaxTradesLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to decode limit has to be a number between 1 and %d", MaxTradesLimit)})
+			return
+		}
+		from, to, err := parseTimeRange(c, func(time.Time) time.Time { return time.Unix(0, 0).UTC() })
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		trades, err := s.store.Trades(c.Query("order_pair"), from, to, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get trades: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, trades)
+	}
+}
+
+func (s *Server) getCandles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Query("order_pair") == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "order_pair is required"})
+			return
+		}
+		interval, from, to, err := parseCandleRange(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		candles, err := s.store.Candles(c.Query("order_pair"), interval, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get candles: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, candles)
+	}
+}
+
+// streams the trades of a pair, starting with the most recent ones
+func (s *Server) subscribeToTrades(orderPair string, ctx context.Context) <-chan Trades {
+	responses := make(chan Trades)
+	go func() {
+		executed := make(chan model.Order)
+		defer func() {
+			s.removeTradesChannel(orderPair, executed)
+			close(responses)
+		}()
+
+		s.socketPool.AddTradesChannel(orderPair, executed)
+		trades, err := s.store.Trades(orderPair, time.Unix(0, 0).UTC(), time.Now().UTC(), DefaultTradesLimit)
+		if err != nil {
+			responses <- Trades{Error: fmt.Sprintf("failed to get trades for %s: %v", orderPair, err)}
+			s.logger.Error("failed to get trades", zap.Error(err))
+			return
+		}
+		responses <- Trades{Trades: trades}
+
+		sent := map[uint]bool{}
+		for _, trade := range trades {
+			sent[trade.OrderID] = true
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case order := <-executed:
+				trade, ok := s.newTrade(order, sent)
+				if !ok {
+					continue
+				}
+				responses <- Trades{Trades: []model.Trade{trade}}
+			}
+		}
+	}()
+	return responses
+}
+
+// streams the candles of a pair, starting with the last DefaultStatsBuckets
+// candles and followed by the current candle whenever a trade is executed
+func (s *Server) subscribeToCandles(orderPair string, interval model.CandleInterval, ctx context.Context) <-chan Candles {
+	responses := make(chan Candles)
+	go func() {
+		executed := make(chan model.Order)
+		defer func() {
+			s.removeTradesChannel(orderPair, executed)
+			close(responses)
+		}()
+
+		s.socketPool.AddTradesChannel(orderPair, executed)
+		to := time.Now().UTC()
+		from := interval.Bucket(to).Add(-(DefaultStatsBuckets - 1) * interval.Duration())
+		candles, err := s.store.Candles(orderPair, interval, from, to)
+		if err != nil {
+			responses <- Candles{Interval: interval, Error: fmt.Sprintf("failed to get candles for %s: %v", orderPair, err)}
+			s.logger.Error("failed to get candles", zap.Error(err))
+			return
+		}
+		responses <- Candles{Interval: interval, Candles: candles}
+
+		current := model.Candle{}
+		if len(candles) > 0 {
+			current = candles[len(candles)-1]
+		}
+		// the trades already counted in the current candle are not applied again
+		sent := map[uint]bool{}
+		if current.Trades > 0 && current.Start.Equal(interval.Bucket(to)) {
+			trades, err := s.store.Trades(orderPair, current.Start, to, int(current.Trades))
+			if err != nil {
+				responses <- Candles{Interval: interval, Error: fmt.Sprintf("failed to get trades for %s: %v", orderPair, err)}
+				s.logger.Error("failed to get trades", zap.Error(err))
+				return
+			}
+			for _, trade := range trades {
+				sent[trade.OrderID] = true
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case order := <-executed:
+				trade, ok := s.newTrade(order, sent)
+				if !ok {
+					continue
+				}
+				if start := interval.Bucket(trade.ExecutedAt); !current.Start.Equal(start) {
+					current = model.Candle{Start: start}
+				}
+				current.Apply(trade)
+				responses <- Candles{Interval: interval, Candles: []model.Candle{current}}
+			}
+		}
+	}()
+	return responses
+}
+
+// the pool sends to the channel while holding its lock, so the channel is drained
+// until it is removed
+func (s *Server) removeTradesChannel(orderPair string, executed chan model.Order) {
+	removed := make(chan struct{})
+	go func() {
+		s.socketPool.RemoveTradesChannel(orderPair, executed)
+		close(removed)
+	}()
+	for {
+		select {
+		case <-executed:
+		case <-removed:
+			return
+		}
+	}
+}
+
+// returns the trade of an executed order unless it was already sent, orders are
+// broadcast again whenever they or their swaps are updated
+func (s *Server) newTrade(order model.Order, sent map[uint]bool) (model.Trade, bool) {
+	if sent[order.ID] {
+		return model.Trade{}, false
+	}
+	trade, err := model.NewTrade(order, s.config.Network)
+	if err != nil {
+		s.logger.Error("failed to get trade", zap.Uint("order id", order.ID), zap.Error(err))
+		return model.Trade{}, false
+	}
+	sent[order.ID] = true
+	return trade, true
+}
+
+type Trades struct {
+	Trades []model.Trade `json:"trades"`
+	Error  string        `json:"error"`
+}
+
+type Candles struct {
+	Interval model.CandleInterval `json:"interval"`
+	Candles  []model.Candle       `json:"candles"`
+	Error    string               `json:"error"`
+}
@@ -26,6 +26,8 @@ func (s *Server) socket() gin.HandlerFunc {
 			cancel()
 			return
 		}
+		// private orders are only sent to the wallet the connection is authenticated as
+		viewer := s.viewer(c)
 		pinger := time.NewTicker(time.Second * 60)
 		defer func() {
 			cancel()
@@ -56,7 +58,7 @@ func (s *Server) socket() gin.HandlerFunc {
 				cancel()
 				return
 			}
-			subscription := s.subscribe(message, ctx)
+			subscription := s.subscribe(message, viewer, ctx)
 
 			go func() {
 				for resp := range subscription {
@@ -78,7 +80,7 @@ func (s *Server) socket() gin.HandlerFunc {
 	}
 }
 
-func (s *Server) subscribe(msg []byte, ctx context.Context) <-chan interface{} {
+func (s *Server) subscribe(msg []byte, viewer string, ctx context.Context) <-chan interface{} {
 	responses := make(chan interface{})
 	fmt.Println("subscribing to ", string(msg))
 
@@ -87,6 +89,12 @@ func (s *Server) subscribe(msg []byte, ctx context.Context) <-chan interface{} {
 			close(responses)
 		}()
 
+		// fillers quote requests for quotes on the same connection
+		if quote, ok := strings.CutPrefix(string(msg), "quote::"); ok {
+			responses <- s.submitQuote([]byte(quote))
+			return
+		}
+
 		values := strings.Split(string(msg), "::")
 		if len(values) != 2 || strings.ToLower(values[0]) != "subscribe" {
 			responses <- WebsocketError{Code: 1, Error: fmt.Sprintf("invalid message %s", msg)}
@@ -121,12 +129,39 @@ func (s *Server) subscribe(msg []byte, ctx context.Context) <-chan interface{} {
 				responses <- WebsocketError{Code: 2, Error: fmt.Sprintf("failed to parse order id %s: %v", values[1], err)}
 				return
 			}
-			for order := range s.subscribeToOrderUpdates(uint(orderID), ctx) {
+			for order := range s.subscribeToOrderUpdates(uint(orderID), viewer, ctx) {
 				responses <- order
 			}
 			return
 		}
 
+		if orderPair, ok := strings.CutPrefix(values[1], "trades:"); ok {
+			for response := range s.subscribeToTrades(orderPair, ctx) {
+				responses <- response
+			}
+			return
+		}
+
+		if orderPair, ok := strings.CutPrefix(values[1], "quoteRequests:"); ok {
+			for response := range s.subscribeToQuoteRequests(orderPair, ctx) {
+				responses <- response
+			}
+			return
+		}
+
+		if topic, ok := strings.CutPrefix(values[1], "candles:"); ok {
+			interval, orderPair, _ := strings.Cut(topic, ":")
+			candleInterval, err := model.ParseCandleInterval(interval)
+			if err != nil || orderPair == "" {
+				responses <- WebsocketError{Code: 3, Error: fmt.Sprintf("invalid subscribe message %s, expected candles:<interval>:<order pair>", values[1])}
+				return
+			}
+			for response := range s.subscribeToCandles(orderPair, candleInterval, ctx) {
+				responses <- response
+			}
+			return
+		}
+
 		isOrderPair, err := regexp.Match("^[a-zA-Z_]+(:0x[0-9a-fA-F]{40})?-[a-zA-Z_]+(:0x[0-9a-fA-F]{40})?", []byte(values[1]))
 		if err == nil && isOrderPair {
 			for response := range s.subscribeToOpenOrders(values[1], ctx) {
@@ -144,7 +179,7 @@ type WebsocketError struct {
 	Error string `json:"error"`
 }
 
-func (s *Server) subscribeToOrderUpdates(id uint, ctx context.Context) <-chan UpdatedOrder {
+func (s *Server) subscribeToOrderUpdates(id uint, viewer string, ctx context.Context) <-chan UpdatedOrder {
 	responses := make(chan UpdatedOrder)
 	go func() {
 		defer func() {
@@ -152,13 +187,17 @@ func (s *Server) subscribeToOrderUpdates(id uint, ctx context.Context) <-chan Up
 			close(responses)
 		}()
 
-		s.socketPool.AddOrderUpdatesChannel(id, responses)
+		s.socketPool.AddOrderUpdatesChannel(id, viewer, responses)
 		order, err := s.store.GetOrder(id)
 		if err != nil {
-			responses <- UpdatedOrder{Error: fmt.Sprintf("failed to get orders for %s: %v", id, err)}
+			responses <- UpdatedOrder{Error: fmt.Sprintf("failed to get orders for %d: %v", id, err)}
 			s.logger.Error("failed to get order", zap.Error(err))
 			return
 		}
+		if !order.VisibleTo(viewer) {
+			responses <- UpdatedOrder{Error: fmt.Sprintf("order %d not found", id)}
+			return
+		}
 
 		currentState := UpdatedOrder{
 			Order: *order,
@@ -234,7 +273,7 @@ func (s *Server) subscribeToOpenOrders(orderPair string, ctx context.Context) <-
 		}()
 
 		s.socketPool.AddOpenOrdersChannel(orderPair, responses)
-		orders, err := s.store.FilterOrders("", "", orderPair, "", model.Created, 0.0, 0.0, 0.0, 0.0, 0, 0, true)
+		orders, _, err := s.store.FilterOrders(model.OrderFilter{OrderPair: orderPair, Status: model.Created, Unassigned: true, Verbose: true})
 		if err != nil {
 			responses <- OpenOrders{Error: fmt.Sprintf("failed to get orders for %s: %v", orderPair, err)}
 			s.logger.Error("failed to get open orders", zap.Error(err))
@@ -109,6 +109,18 @@ func typeCast(t string, from interface{}) (interface{}, error) {
 	case "rest.UpdatedOrder":
 		obj := UpdatedOrder{}
 		return obj, json.Unmarshal(data, &obj)
+	case "rest.Trades":
+		obj := Trades{}
+		return obj, json.Unmarshal(data, &obj)
+	case "rest.Candles":
+		obj := Candles{}
+		return obj, json.Unmarshal(data, &obj)
+	case "rest.QuoteRequests":
+		obj := QuoteRequests{}
+		return obj, json.Unmarshal(data, &obj)
+	case "rest.SubmittedQuote":
+		obj := SubmittedQuote{}
+		return obj, json.Unmarshal(data, &obj)
 	case "rest.WebsocketError":
 		obj := WebsocketError{}
 		return obj, json.Unmarshal(data, &obj)
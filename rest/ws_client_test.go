@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
+	"github.com/catalogfi/orderbook/feehub"
 	"github.com/catalogfi/orderbook/mocks"
 	"github.com/catalogfi/orderbook/model"
 	"github.com/catalogfi/orderbook/rest"
@@ -19,6 +21,7 @@ var (
 	mockCtrl      *gomock.Controller
 	mockStore     *mocks.MockServerStore
 	mockScreener  *mocks.MockScreener
+	pool          rest.SocketPool
 	mockSecret    = "MOCK SECRET"
 	errMock       = errors.New("mock error")
 	mockOrderPair = "bitcoin-ethereum"
@@ -28,6 +31,7 @@ var (
 
 	ctx    context.Context
 	cancel context.CancelFunc
+	done   chan struct{}
 	client rest.WSClient
 )
 
@@ -36,12 +40,27 @@ var _ = BeforeEach(func() {
 	mockStore = mocks.NewMockServerStore(mockCtrl)
 	mockScreener = mocks.NewMockScreener(mockCtrl)
 	ctx, cancel = context.WithCancel(context.Background())
-	go rest.NewServer(mockStore, config, zap.NewNop(), mockSecret, nil, mockScreener, nil, nil).Run(ctx, ":8080")
+	pool = rest.NewSocketPool()
+	done = make(chan struct{})
+	server := rest.NewServer(mockStore, config, zap.NewNop(), mockSecret, pool, mockScreener, feehub.NewFeehubClient(""), nil)
+	go func() {
+		defer close(done)
+		server.Run(ctx, ":8080")
+	}()
+	// the client dials once, so wait for the server to listen first
+	Eventually(func() error {
+		resp, err := http.Get("http://localhost:8080/health")
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}).Should(Succeed())
 	client = rest.NewWSClient("ws://localhost:8080", zap.NewNop())
 })
 
 var _ = AfterEach(func() {
 	cancel()
+	Eventually(done).Should(BeClosed())
 	mockCtrl.Finish()
 })
 
@@ -73,64 +92,44 @@ var _ = Describe("subscribe to order status updates", func() {
 		Expect(err.(rest.WebsocketError).Error).ToNot(BeEmpty())
 	})
 
-	It("should return order and error if the getorder returns an order and an error", func() {
-		// Expect the function to be called 3 times
-		callCount := 0
-		mockStore.EXPECT().GetOrder(uint(5)).DoAndReturn(func(id uint) (*model.Order, error) {
-			callCount++
-			switch callCount {
-			case 1:
-				return &model.Order{
-					Status:              model.Created,
-					InitiatorAtomicSwap: &model.AtomicSwap{},
-					FollowerAtomicSwap:  &model.AtomicSwap{},
-				}, nil
-			default:
-				return nil, errMock
-			}
-		}).Times(2)
+	It("should not send the updates of other orders", func() {
+		mockStore.EXPECT().GetOrder(uint(5)).Return(&model.Order{
+			Model:               gorm.Model{ID: 5},
+			Status:              model.Created,
+			InitiatorAtomicSwap: &model.AtomicSwap{},
+			FollowerAtomicSwap:  &model.AtomicSwap{},
+		}, nil).Times(1)
 
 		client.Subscribe("subscribe::5")
 		listener := client.Listen()
 		order1 := <-listener
 		Expect(order1.(rest.UpdatedOrder).Order.Status).To(Equal(model.Created))
+
+		Expect(pool.FilterAndBufferOrder(model.Order{Model: gorm.Model{ID: 6}, Status: model.Filled})).To(Succeed())
+		Expect(pool.FilterAndBufferOrder(model.Order{Model: gorm.Model{ID: 5}, Status: model.Executed})).To(Succeed())
 		order2 := <-listener
-		Expect(order2.(rest.UpdatedOrder).Error).ToNot(BeEmpty())
+		Expect(order2.(rest.UpdatedOrder).Order.ID).To(Equal(uint(5)))
+		Expect(order2.(rest.UpdatedOrder).Order.Status).To(Equal(model.Executed))
 	})
 
 	It("should send us order updates as they happen in the database", func() {
-		// Expect the function to be called 4 times
-		callCount := 0
-		mockStore.EXPECT().GetOrder(uint(5)).DoAndReturn(func(id uint) (*model.Order, error) {
-			callCount++
-			switch callCount {
-			case 1:
-				return &model.Order{
-					Status:              model.Unknown,
-					InitiatorAtomicSwap: &model.AtomicSwap{},
-					FollowerAtomicSwap:  &model.AtomicSwap{},
-				}, nil
-			case 2, 3:
-				return &model.Order{
-					Status:              model.Filled,
-					InitiatorAtomicSwap: &model.AtomicSwap{},
-					FollowerAtomicSwap:  &model.AtomicSwap{},
-				}, nil
-			default:
-				return &model.Order{
-					Status:              model.Executed,
-					InitiatorAtomicSwap: &model.AtomicSwap{},
-					FollowerAtomicSwap:  &model.AtomicSwap{},
-				}, nil
-			}
-		}).Times(4)
+		mockStore.EXPECT().GetOrder(uint(5)).Return(&model.Order{
+			Model:               gorm.Model{ID: 5},
+			Status:              model.Unknown,
+			InitiatorAtomicSwap: &model.AtomicSwap{},
+			FollowerAtomicSwap:  &model.AtomicSwap{},
+		}, nil).Times(1)
 
 		client.Subscribe("subscribe::5")
 		listener := client.Listen()
 		order1 := <-listener
 		Expect(order1.(rest.UpdatedOrder).Order.Status).To(Equal(model.Unknown))
+
+		Expect(pool.FilterAndBufferOrder(model.Order{Model: gorm.Model{ID: 5}, Status: model.Filled})).To(Succeed())
 		order2 := <-listener
 		Expect(order2.(rest.UpdatedOrder).Order.Status).To(Equal(model.Filled))
+
+		Expect(pool.FilterAndBufferOrder(model.Order{Model: gorm.Model{ID: 5}, Status: model.Executed})).To(Succeed())
 		order3 := <-listener
 		Expect(order3.(rest.UpdatedOrder).Order.Status).To(Equal(model.Executed))
 	})
@@ -139,8 +138,8 @@ var _ = Describe("subscribe to order status updates", func() {
 var _ = Describe("subscribe to all open orders", func() {
 	It("should return the order if the status is already executed", func() {
 		mockStore.EXPECT().FilterOrders(
-			"", "", "", mockOrderPair, "", model.Created, 0.0, 0.0, 0.0, 0.0, 0, 0, true,
-		).Return(nil, errMock).Times(1)
+			model.OrderFilter{OrderPair: mockOrderPair, Status: model.Created, Unassigned: true, Verbose: true},
+		).Return(nil, "", errMock).Times(1)
 
 		client.Subscribe(fmt.Sprintf("subscribe::%s", mockOrderPair))
 		listener := client.Listen()
@@ -148,31 +147,25 @@ var _ = Describe("subscribe to all open orders", func() {
 		Expect(order.(rest.OpenOrders).Error).ToNot(BeEmpty())
 	})
 
-	It("should return order and error if the getorder returns an order and an error", func() {
-		callCount := 0
+	It("should send us new open orders as they are created", func() {
 		mockStore.EXPECT().FilterOrders(
-			"", "", "", mockOrderPair, "", model.Created, 0.0, 0.0, 0.0, 0.0, 0, 0, true,
-		).DoAndReturn(func(maker, taker, orderPair, secretHash, sort string, status model.Status, minPrice, maxPrice float64, minAmount, maxAmount float64, page, perPage int, verbose bool) ([]model.Order, error) {
-			callCount++
-			switch callCount {
-			case 1, 2:
-				return []model.Order{{
-					Model:               gorm.Model{ID: 1},
-					Status:              model.Created,
-					InitiatorAtomicSwap: &model.AtomicSwap{},
-					FollowerAtomicSwap:  &model.AtomicSwap{},
-				}}, nil
-			default:
-				return nil, errMock
-			}
-		}).Times(3)
+			model.OrderFilter{OrderPair: mockOrderPair, Status: model.Created, Unassigned: true, Verbose: true},
+		).Return([]model.Order{{
+			Model:               gorm.Model{ID: 1},
+			Status:              model.Created,
+			InitiatorAtomicSwap: &model.AtomicSwap{},
+			FollowerAtomicSwap:  &model.AtomicSwap{},
+		}}, "", nil).Times(1)
 
 		client.Subscribe(fmt.Sprintf("subscribe::%s", mockOrderPair))
 		listener := client.Listen()
 		order := <-listener
 		Expect(order.(rest.OpenOrders).Orders[0].Status).To(Equal(model.Created))
+
+		Expect(pool.FilterAndBufferOrder(model.Order{Model: gorm.Model{ID: 2}, OrderPair: mockOrderPair, Status: model.Created})).To(Succeed())
 		order2 := <-listener
-		Expect(order2.(rest.OpenOrders).Error).ToNot(BeEmpty())
+		Expect(order2.(rest.OpenOrders).Error).To(BeEmpty())
+		Expect(order2.(rest.OpenOrders).Orders[0].ID).To(Equal(uint(2)))
 	})
 })
 
@@ -186,29 +179,14 @@ var _ = Describe("subscribe to orders on an address", func() {
 		Expect(order.(rest.UpdatedOrders).Error).ToNot(BeEmpty())
 	})
 
-	It("should return order and error if the getorder returns an order and an error", func() {
-		callCount := 0
-		mockStore.EXPECT().GetOrdersByAddress(mockAddress).DoAndReturn(func(address string) ([]model.Order, error) {
-			callCount++
-			switch callCount {
-			case 1, 2:
-				return []model.Order{{
-					Model:               gorm.Model{ID: 1},
-					Status:              model.Created,
-					InitiatorAtomicSwap: &model.AtomicSwap{},
-					FollowerAtomicSwap:  &model.AtomicSwap{},
-				}}, nil
-			case 3:
-				return []model.Order{{
-					Model:               gorm.Model{ID: 1},
-					Status:              model.Filled,
-					InitiatorAtomicSwap: &model.AtomicSwap{},
-					FollowerAtomicSwap:  &model.AtomicSwap{},
-				}}, nil
-			default:
-				return nil, errMock
-			}
-		}).Times(4)
+	It("should send us the updates of the orders on the address", func() {
+		mockStore.EXPECT().GetOrdersByAddress(mockAddress).Return([]model.Order{{
+			Model:               gorm.Model{ID: 1},
+			Maker:               mockAddress,
+			Status:              model.Created,
+			InitiatorAtomicSwap: &model.AtomicSwap{},
+			FollowerAtomicSwap:  &model.AtomicSwap{},
+		}}, nil).Times(1)
 
 		client.Subscribe(fmt.Sprintf("subscribe::%s", mockAddress))
 		listener := client.Listen()
@@ -216,11 +194,11 @@ var _ = Describe("subscribe to orders on an address", func() {
 		Expect(order.(rest.UpdatedOrders).Error).To(BeEmpty())
 		Expect(len(order.(rest.UpdatedOrders).Orders)).To(Equal(1))
 		Expect(order.(rest.UpdatedOrders).Orders[0].Status).To(Equal(model.Created))
+
+		Expect(pool.FilterAndBufferOrder(model.Order{Model: gorm.Model{ID: 1}, Maker: mockAddress, Status: model.Filled})).To(Succeed())
 		order2 := <-listener
 		Expect(len(order2.(rest.UpdatedOrders).Orders)).To(Equal(1))
 		Expect(order2.(rest.UpdatedOrders).Orders[0].Status).To(Equal(model.Filled))
-		order3 := <-listener
-		Expect(order3.(rest.UpdatedOrders).Error).ToNot(BeEmpty())
 	})
 })
 
@@ -0,0 +1,28 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/catalogfi/orderbook/internal/job"
+	"github.com/catalogfi/orderbook/model"
+	"go.uber.org/zap"
+)
+
+// DefaultScoreInterval is the time between scorings of the fillers when it is not
+// configured
+const DefaultScoreInterval = 10 * time.Minute
+
+type ScoreStore interface {
+	// score the reputation of the fillers from the orders they filled
+	ScoreFillers() error
+}
+
+// NewScorer returns a job periodically scoring the reputation of the fillers
+// served by the filler registry
+func NewScorer(store ScoreStore, config model.Config, logger *zap.Logger) job.Job {
+	logger = logger.With(zap.String("service", "score"))
+	return job.NewPeriodic(job.Interval(config.ScoreInterval, DefaultScoreInterval), func(ctx context.Context) error {
+		return store.ScoreFillers()
+	}, logger)
+}
@@ -0,0 +1,27 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/catalogfi/orderbook/internal/job"
+	"github.com/catalogfi/orderbook/model"
+	"go.uber.org/zap"
+)
+
+// DefaultRollupInterval is the time between rollups when it is not configured
+const DefaultRollupInterval = time.Minute
+
+type Store interface {
+	// roll up the statistics of the orders updated since the last rollup
+	RollupOrders(config model.Network) error
+}
+
+// NewRoller returns a job periodically rolling up the order statistics served by
+// the analytics api, so queries read the rollups instead of scanning the orders
+func NewRoller(store Store, config model.Config, logger *zap.Logger) job.Job {
+	logger = logger.With(zap.String("service", "rollup"))
+	return job.NewPeriodic(job.Interval(config.RollupInterval, DefaultRollupInterval), func(ctx context.Context) error {
+		return store.RollupOrders(config.Network)
+	}, logger)
+}
@@ -0,0 +1,28 @@
+package stats
+
+import (
+	"context"
+	"time"
+
+	"github.com/catalogfi/orderbook/internal/job"
+	"github.com/catalogfi/orderbook/model"
+	"go.uber.org/zap"
+)
+
+// DefaultSnapshotInterval is the time between snapshots of the value locked when
+// it is not configured
+const DefaultSnapshotInterval = 5 * time.Minute
+
+type SnapshotStore interface {
+	// record the value locked per chain and asset and downsample old snapshots
+	SnapshotTVL(config model.Config) error
+}
+
+// NewSnapshotter returns a job periodically recording the value locked served by
+// the analytics api
+func NewSnapshotter(store SnapshotStore, config model.Config, logger *zap.Logger) job.Job {
+	logger = logger.With(zap.String("service", "snapshot"))
+	return job.NewPeriodic(job.Interval(config.SnapshotInterval, DefaultSnapshotInterval), func(ctx context.Context) error {
+		return store.SnapshotTVL(config)
+	}, logger)
+}
@@ -0,0 +1,62 @@
+package store_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Auction orders", func() {
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	pair := testPair
+
+	It("should lock in the decayed receive amount when filled", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		terms := model.OrderTerms{Type: model.AuctionOrder, AuctionEndAmount: big.NewInt(90000000), AuctionDuration: time.Hour}
+		id, err := createOrder(store, testOrder{Terms: terms})
+		Expect(err).NotTo(HaveOccurred())
+
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Type).To(Equal(model.AuctionOrder))
+		Expect(order.Auction).NotTo(BeNil())
+		Expect(order.Auction.StartAmount).To(Equal("110000000"))
+		Expect(order.Auction.EndAmount).To(Equal("90000000"))
+		Expect(order.Auction.EndsAt).To(BeTemporally("~", order.Auction.StartsAt.Add(time.Hour), time.Second))
+		Expect(*order.ExpiresAt).To(BeTemporally("~", order.Auction.EndsAt, time.Second))
+
+		// half way through the auction
+		startsAt := time.Now().UTC().Add(-30 * time.Minute)
+		Expect(store.Gorm().Model(&model.Order{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"auction_starts_at": startsAt,
+			"auction_ends_at":   startsAt.Add(time.Hour),
+		}).Error).NotTo(HaveOccurred())
+		orders, _, err := store.FilterOrders(model.OrderFilter{OrderPair: pair, Status: model.Created})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(HaveLen(1))
+		current, _ := new(big.Int).SetString(orders[0].Auction.CurrentAmount, 10)
+		Expect(current.Int64()).To(BeNumerically("~", 100000000, 10000))
+
+		Expect(store.FillOrder(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(Succeed())
+		order, err = store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		locked, _ := new(big.Int).SetString(order.FollowerAtomicSwap.Amount, 10)
+		Expect(locked.Int64()).To(BeNumerically("~", 100000000, 10000))
+		Expect(locked.Int64()).To(BeNumerically("<=", current.Int64()))
+		Expect(order.Price.Float64()).To(BeNumerically("~", 1, 0.001))
+
+		// market orders have no auction
+		id, err = createOrder(store, testOrder{})
+		Expect(err).NotTo(HaveOccurred())
+		order, err = store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Auction).To(BeNil())
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
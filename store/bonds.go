@@ -0,0 +1,142 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// record the bond of a filler verified on chain
+func (s *store) UpdateBond(filler string, amount *big.Int, verifiedAt time.Time) error {
+	return s.db.Model(&model.Filler{}).Where("address = ?", filler).
+		Updates(map[string]interface{}{"bond": amount.String(), "bond_verified_at": verifiedAt.UTC()}).Error
+}
+
+// get the bond of a filler, what it owes from slashed penalties and the number of
+// its penalties pending review
+func (s *store) GetBond(filler string) (*model.Bond, error) {
+	profile, err := s.GetFiller(filler)
+	if err != nil {
+		return nil, err
+	}
+	bond := &model.Bond{
+		Filler:      profile.Address,
+		BondAddress: profile.BondAddress,
+		Amount:      "0",
+		VerifiedAt:  profile.BondVerifiedAt,
+	}
+	if bond.BondAddress == "" {
+		bond.BondAddress = profile.Address
+	}
+	amount := big.NewInt(0)
+	if profile.Bond != "" {
+		var ok bool
+		if amount, ok = new(big.Int).SetString(profile.Bond, 10); !ok {
+			return nil, fmt.Errorf("constraint violation: corrupted bond %s", profile.Bond)
+		}
+		bond.Amount = amount.String()
+	}
+
+	penalties := []model.Penalty{}
+	if err := s.db.Select("status", "amount").Where("filler = ? AND status IN ?", filler, []model.PenaltyStatus{model.PenaltyPending, model.PenaltySlashed}).
+		Find(&penalties).Error; err != nil {
+		return nil, err
+	}
+	slashed := big.NewInt(0)
+	for _, penalty := range penalties {
+		if penalty.Status == model.PenaltyPending {
+			bond.Pending++
+			continue
+		}
+		penaltyAmount, ok := new(big.Int).SetString(penalty.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("constraint violation: corrupted penalty amount %s", penalty.Amount)
+		}
+		slashed.Add(slashed, penaltyAmount)
+	}
+	bond.Slashed = slashed.String()
+	available := new(big.Int).Sub(amount, slashed)
+	if available.Sign() < 0 {
+		available.SetInt64(0)
+	}
+	bond.Available = available.String()
+	return bond, nil
+}
+
+// record a penalty against the filler of an order it failed, the penalty of an
+// order is only recorded once
+func (s *store) RecordPenalty(order *model.Order, reason string) error {
+	penalty := model.Penalty{
+		Filler:  order.Taker,
+		OrderID: order.ID,
+		Reason:  reason,
+		Status:  model.PenaltyPending,
+	}
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&penalty).Error
+}
+
+// get the penalties of a filler, or of all fillers if none is given, with the
+// given status if one is given, the latest first
+func (s *store) GetPenalties(filler string, status model.PenaltyStatus) ([]model.Penalty, error) {
+	query := s.db
+	if filler != "" {
+		query = query.Where("filler = ?", filler)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	penalties := []model.Penalty{}
+	if err := query.Order("id DESC").Find(&penalties).Error; err != nil {
+		return nil, err
+	}
+	return penalties, nil
+}
+
+// settle a pending penalty by slashing the given amount from the bond of the
+// filler or by waiving it
+func (s *store) SettlePenalty(id uint, status model.PenaltyStatus, amount, settledBy, note string) (*model.Penalty, error) {
+	switch status {
+	case model.PenaltySlashed:
+		slashed, ok := new(big.Int).SetString(amount, 10)
+		if !ok || slashed.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid slashed amount %s", amount)
+		}
+		amount = slashed.String()
+	case model.PenaltyWaived:
+		if amount != "" {
+			return nil, fmt.Errorf("waived penalties cannot slash an amount")
+		}
+	default:
+		return nil, fmt.Errorf("invalid settlement %s", status)
+	}
+
+	penalty := &model.Penalty{}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(penalty, id).Error; err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		// only one of concurrent settlements succeeds
+		res := tx.Model(&model.Penalty{}).Where("id = ? AND status = ?", id, model.PenaltyPending).
+			Updates(map[string]interface{}{"status": status, "amount": amount, "settled_by": settledBy, "settled_at": now, "note": note})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return fmt.Errorf("penalty %d is already %s", id, penalty.Status)
+		}
+		return tx.First(penalty, id).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("penalty %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return penalty, nil
+}
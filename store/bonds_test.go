@@ -0,0 +1,90 @@
+package store_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Bonds", func() {
+	maker := testMaker
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	bondAddress := "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0"
+	btcAddress := testBTCAddress
+
+	defaultedOrder := func(store Store) *model.Order {
+		id, err := createOrder(store, testOrder{SendAmount: "100000", ReceiveAmount: "110000"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.FillOrder(id, filler, filler, btcAddress, config.Network)).To(Succeed())
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		order.InitiatorAtomicSwap.Status = model.Refunded
+		order.Status = model.FailedSoft
+		Expect(order.FillerDefaulted()).To(BeTrue())
+		Expect(store.UpdateOrder(order)).To(Succeed())
+		return order
+	}
+
+	It("should verify bonds and settle the penalties of defaulted fills", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.RegisterFiller(filler, "Filler", "", "invalid", nil)).NotTo(Succeed())
+		Expect(store.RegisterFiller(filler, "Filler", "", bondAddress, nil)).To(Succeed())
+
+		bond, err := store.GetBond(filler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bond.BondAddress).To(Equal(bondAddress))
+		Expect(bond.Amount).To(Equal("0"))
+		Expect(bond.VerifiedAt).To(BeNil())
+
+		Expect(store.UpdateBond(filler, big.NewInt(100), time.Now())).To(Succeed())
+		order := defaultedOrder(store)
+		// the penalty of an order is only recorded once
+		Expect(store.RecordPenalty(order, model.PenaltyFillerDefaulted)).To(Succeed())
+		Expect(store.RecordPenalty(order, model.PenaltyFillerDefaulted)).To(Succeed())
+
+		penalties, err := store.GetPenalties(filler, model.PenaltyPending)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(penalties).To(HaveLen(1))
+		Expect(penalties[0].OrderID).To(Equal(order.ID))
+		bond, err = store.GetBond(filler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bond.Amount).To(Equal("100"))
+		Expect(bond.Pending).To(Equal(int64(1)))
+		Expect(bond.VerifiedAt).NotTo(BeNil())
+
+		_, err = store.SettlePenalty(penalties[0].ID, model.PenaltyWaived, "10", maker, "")
+		Expect(err).To(HaveOccurred())
+		_, err = store.SettlePenalty(penalties[0].ID, model.PenaltySlashed, "0", maker, "")
+		Expect(err).To(HaveOccurred())
+		_, err = store.SettlePenalty(penalties[0].ID+1, model.PenaltySlashed, "40", maker, "")
+		Expect(err).To(HaveOccurred())
+		penalty, err := store.SettlePenalty(penalties[0].ID, model.PenaltySlashed, "40", maker, "did not initiate")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(penalty.Status).To(Equal(model.PenaltySlashed))
+		Expect(penalty.SettledBy).To(Equal(maker))
+		Expect(penalty.SettledAt).NotTo(BeNil())
+		_, err = store.SettlePenalty(penalties[0].ID, model.PenaltyWaived, "", maker, "")
+		Expect(err).To(HaveOccurred())
+
+		bond, err = store.GetBond(filler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bond.Pending).To(Equal(int64(0)))
+		Expect(bond.Slashed).To(Equal("40"))
+		Expect(bond.Available).To(Equal("60"))
+
+		// the bond of a new bond address is verified again
+		Expect(store.RegisterFiller(filler, "Filler", "", "", nil)).To(Succeed())
+		bond, err = store.GetBond(filler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bond.BondAddress).To(Equal(filler))
+		Expect(bond.VerifiedAt).To(BeNil())
+		Expect(bond.Available).To(Equal("0"))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/watcher"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// get the last block processed by the evm watcher for the given htlc contract,
+// returns model.ErrNotFound if the contract has never been watched
+func (s *store) GetBlockCursor(chain model.Chain, contract string) (uint64, error) {
+	cursor := model.BlockCursor{}
+	if tx := s.db.Where("chain = ? AND contract = ?", chain, strings.ToLower(contract)).First(&cursor); tx.Error != nil {
+		return 0, notFound(tx.Error)
+	}
+	return cursor.BlockNumber, nil
+}
+
+// persist the last block processed by the evm watcher for the given htlc contract
+func (s *store) UpdateBlockCursor(chain model.Chain, contract string, blockNumber uint64) error {
+	cursor := model.BlockCursor{
+		Chain:       chain,
+		Contract:    strings.ToLower(contract),
+		BlockNumber: blockNumber,
+	}
+	if tx := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain"}, {Name: "contract"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block_number", "updated_at"}),
+	}).Create(&cursor); tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// run the given function against a store which shares a single db transaction,
+// the transaction is rolled back if fn returns an error
+func (s *store) Transaction(fn func(watcher.Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&store{mu: s.mu, db: tx, prices: s.prices})
+	})
+}
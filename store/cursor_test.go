@@ -0,0 +1,62 @@
+package store_test
+
+import (
+	"errors"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	"github.com/catalogfi/orderbook/watcher"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Block cursors", func() {
+	contract := "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"
+
+	It("should not find a cursor for a new contract", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = store.GetBlockCursor(model.EthereumSepolia, contract)
+		Expect(errors.Is(err, model.ErrNotFound)).To(BeTrue())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should advance the cursor of a contract", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.UpdateBlockCursor(model.EthereumSepolia, contract, 100)).To(Succeed())
+		Expect(store.UpdateBlockCursor(model.EthereumSepolia, contract, 250)).To(Succeed())
+		Expect(store.UpdateBlockCursor(model.EthereumArbitrum, contract, 10)).To(Succeed())
+
+		block, err := store.GetBlockCursor(model.EthereumSepolia, contract)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block).To(Equal(uint64(250)))
+
+		block, err = store.GetBlockCursor(model.EthereumArbitrum, contract)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block).To(Equal(uint64(10)))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should not advance the cursor when the transaction fails", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.UpdateBlockCursor(model.EthereumSepolia, contract, 100)).To(Succeed())
+
+		err = store.Transaction(func(tx watcher.Store) error {
+			if err := tx.UpdateBlockCursor(model.EthereumSepolia, contract, 200); err != nil {
+				return err
+			}
+			return errors.New("failed to handle logs")
+		})
+		Expect(err).To(HaveOccurred())
+
+		block, err := store.GetBlockCursor(model.EthereumSepolia, contract)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block).To(Equal(uint64(100)))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// the store supports postgres, mysql and sqlite, queries which are not portable
+// between them are built with the helpers below
+
+const (
+	dialectPostgres = "postgres"
+	dialectMySQL    = "mysql"
+	dialectSQLite   = "sqlite"
+)
+
+// case insensitive equality of the column and a single query parameter
+func equalFold(column string) string {
+	return fmt.Sprintf("LOWER(%s) = LOWER(?)", column)
+}
+
+// casts a text column holding an integer amount to an exact number, amounts can
+// exceed 64 bits on evm chains so sqlite, which has no wider integers, may overflow
+func castAmount(db *gorm.DB, column string) string {
+	switch db.Dialector.Name() {
+	case dialectMySQL:
+		return fmt.Sprintf("CAST(%s AS DECIMAL(65,0))", column)
+	case dialectSQLite:
+		return fmt.Sprintf("CAST(%s AS INTEGER)", column)
+	default:
+		return fmt.Sprintf("CAST(%s AS NUMERIC)", column)
+	}
+}
@@ -0,0 +1,187 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// how the orders filled by a filler ended
+type fillerOutcome struct {
+	Taker      string
+	Fills      uint64
+	Executed   uint64
+	FailedSoft uint64
+	FailedHard uint64
+	Cancelled  uint64
+}
+
+// register or update the profile of a filler and the address holding its bond,
+// its reputation is kept
+func (s *store) RegisterFiller(address, name, contact, bondAddress string, pairs []string) error {
+	if err := CheckAddress(model.Ethereum, address); err != nil {
+		return err
+	}
+	if bondAddress != "" {
+		if err := CheckAddress(model.Ethereum, bondAddress); err != nil {
+			return fmt.Errorf("invalid bond address: %v", err)
+		}
+	}
+	for _, pair := range pairs {
+		if _, _, _, _, err := model.ParseOrderPair(pair); err != nil {
+			return fmt.Errorf("invalid pair %s: %v", pair, err)
+		}
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		filler := model.Filler{}
+		err := tx.Where("address = ?", address).First(&filler).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			filler.Score = model.FillerScore(0, 0, 0)
+			filler.FillRate = model.FillRate(0, 0, 0)
+		}
+		filler.Address = address
+		filler.Name = name
+		filler.Contact = contact
+		filler.Pairs = pairs
+		if bondAddress != filler.BondAddress {
+			// the bond of another address is verified again
+			filler.Bond = ""
+			filler.BondVerifiedAt = nil
+		}
+		filler.BondAddress = bondAddress
+		filler.Registered = true
+		return tx.Save(&filler).Error
+	})
+}
+
+// get the fillers, the best scored first
+func (s *store) GetFillers() ([]model.Filler, error) {
+	fillers := []model.Filler{}
+	if err := s.db.Order("score DESC, fills DESC, address ASC").Find(&fillers).Error; err != nil {
+		return nil, err
+	}
+	return fillers, nil
+}
+
+// get the filler with the given address
+func (s *store) GetFiller(address string) (*model.Filler, error) {
+	filler := &model.Filler{}
+	if err := s.db.Where("address = ?", address).First(filler).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return filler, nil
+}
+
+// score the reputation of every wallet which filled an order from the outcomes
+// of its orders and how long it took to initiate after the maker
+func (s *store) ScoreFillers() error {
+	outcomes, err := fillerOutcomes(s.db, nil)
+	if err != nil {
+		return err
+	}
+	timesToInitiate, err := s.timesToInitiate()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, outcome := range outcomes {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			filler := model.Filler{}
+			err := tx.Where("address = ?", outcome.Taker).First(&filler).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			filler.Address = outcome.Taker
+			filler.Fills = outcome.Fills
+			filler.Executed = outcome.Executed
+			filler.FailedSoft = outcome.FailedSoft
+			filler.FailedHard = outcome.FailedHard
+			filler.Cancelled = outcome.Cancelled
+			filler.FillRate = model.FillRate(outcome.Executed, outcome.FailedSoft, outcome.FailedHard)
+			filler.MedianTimeToInitiate = median(timesToInitiate[outcome.Taker])
+			filler.Score = model.FillerScore(outcome.Executed, outcome.FailedSoft, outcome.FailedHard)
+			filler.ScoredAt = &now
+			return tx.Save(&filler).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to score filler %s: %v", outcome.Taker, err)
+		}
+	}
+	return nil
+}
+
+// count the outcomes of the orders filled by the given fillers, or by all
+// fillers if none are given. Orders assigned to a filler but not filled yet are
+// not counted.
+func fillerOutcomes(tx *gorm.DB, fillers []string) ([]fillerOutcome, error) {
+	query := tx.Model(&model.Order{}).
+		Select("taker, COUNT(*) AS fills, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS executed, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS failed_soft, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS failed_hard, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS cancelled",
+			model.Executed, model.FailedSoft, model.FailedHard, model.Cancelled).
+		Where("taker <> '' AND taker IS NOT NULL AND status <> ?", model.Created)
+	if fillers != nil {
+		query = query.Where("taker IN ?", fillers)
+	}
+	outcomes := []fillerOutcome{}
+	if err := query.Group("taker").Order("taker ASC").Scan(&outcomes).Error; err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+// the seconds between the initiation of the maker and of the filler of every
+// order both initiated, by filler. Orders are joined to the first initiated event
+// of each of their swaps so only the orders with both swaps initiated are loaded.
+func (s *store) timesToInitiate() (map[string][]int64, error) {
+	rows := []struct {
+		Taker            string
+		MakerInitiatedAt time.Time
+		TakerInitiatedAt time.Time
+	}{}
+	firstInitiated := func(events string) string {
+		return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM swap_events earlier WHERE earlier.swap_id = %[1]s.swap_id AND earlier.status = %[1]s.status AND earlier.id < %[1]s.id)", events)
+	}
+	if err := s.db.Table("orders").
+		Select("orders.taker, maker_events.created_at AS maker_initiated_at, taker_events.created_at AS taker_initiated_at").
+		Joins("JOIN swap_events maker_events ON maker_events.swap_id = orders.initiator_atomic_swap_id AND maker_events.status = ?", model.Initiated).
+		Joins("JOIN swap_events taker_events ON taker_events.swap_id = orders.follower_atomic_swap_id AND taker_events.status = ?", model.Initiated).
+		Where("orders.taker <> '' AND orders.taker IS NOT NULL AND orders.status <> ?", model.Created).
+		Where(firstInitiated("maker_events")).
+		Where(firstInitiated("taker_events")).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	times := map[string][]int64{}
+	for _, row := range rows {
+		seconds := int64(row.TakerInitiatedAt.Sub(row.MakerInitiatedAt) / time.Second)
+		if seconds < 0 {
+			seconds = 0
+		}
+		times[row.Taker] = append(times[row.Taker], seconds)
+	}
+	return times, nil
+}
+
+// the median of the values, rounded down, or 0 without values
+func median(values []int64) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64{}, values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
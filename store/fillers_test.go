@@ -0,0 +1,79 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Fillers", func() {
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	otherFiller := "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0"
+	pair := testPair
+	btcAddress := testBTCAddress
+
+	fillOrder := func(store Store, taker string, status model.Status) *model.Order {
+		id, err := createOrder(store, testOrder{SendAmount: "100000", ReceiveAmount: "110000"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.FillOrder(id, taker, taker, btcAddress, config.Network)).To(Succeed())
+		Expect(store.Gorm().Model(&model.Order{}).Where("id = ?", id).Update("status", status).Error).NotTo(HaveOccurred())
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		return order
+	}
+
+	It("should score fillers from the outcomes of their orders", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.RegisterFiller(filler, "Filler", "filler@example.com", "", []string{"invalid"})).NotTo(Succeed())
+		Expect(store.RegisterFiller(filler, "Filler", "filler@example.com", "", []string{pair})).To(Succeed())
+
+		for _, delay := range []time.Duration{30 * time.Second, 90 * time.Second} {
+			order := fillOrder(store, filler, model.Executed)
+			initiatedAt := time.Now().UTC().Add(-time.Hour)
+			Expect(store.Gorm().Create(&model.SwapEvent{SwapID: order.InitiatorAtomicSwapID, Status: model.Initiated, CreatedAt: initiatedAt}).Error).NotTo(HaveOccurred())
+			Expect(store.Gorm().Create(&model.SwapEvent{SwapID: order.FollowerAtomicSwapID, Status: model.Initiated, CreatedAt: initiatedAt.Add(delay)}).Error).NotTo(HaveOccurred())
+			// only the first initiated event of a swap is counted
+			Expect(store.Gorm().Create(&model.SwapEvent{SwapID: order.FollowerAtomicSwapID, Status: model.Initiated, CreatedAt: initiatedAt.Add(delay + time.Hour)}).Error).NotTo(HaveOccurred())
+		}
+		fillOrder(store, filler, model.FailedSoft)
+		fillOrder(store, filler, model.Cancelled)
+		fillOrder(store, otherFiller, model.FailedHard)
+		fillOrder(store, otherFiller, model.FailedSoft)
+
+		Expect(store.ScoreFillers()).To(Succeed())
+		profile, err := store.GetFiller(filler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profile.Registered).To(BeTrue())
+		Expect(profile.Name).To(Equal("Filler"))
+		Expect([]string(profile.Pairs)).To(Equal([]string{pair}))
+		Expect(profile.Fills).To(Equal(uint64(4)))
+		Expect(profile.Executed).To(Equal(uint64(2)))
+		Expect(profile.FailedSoft).To(Equal(uint64(1)))
+		Expect(profile.Cancelled).To(Equal(uint64(1)))
+		Expect(profile.FillRate).To(BeNumerically("~", 2.0/3.0, 0.001))
+		Expect(profile.MedianTimeToInitiate).To(Equal(int64(60)))
+		Expect(profile.Score).To(Equal(model.FillerScore(2, 1, 0)))
+		Expect(profile.ScoredAt).NotTo(BeNil())
+
+		// the profile is kept when the reputation is scored again
+		Expect(store.RegisterFiller(filler, "Renamed", "", "", nil)).To(Succeed())
+		Expect(store.ScoreFillers()).To(Succeed())
+
+		fillers, err := store.GetFillers()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fillers).To(HaveLen(2))
+		Expect(fillers[0].Address).To(Equal(filler))
+		Expect(fillers[0].Name).To(Equal("Renamed"))
+		Expect(fillers[0].Executed).To(Equal(uint64(2)))
+		Expect(fillers[1].Address).To(Equal(otherFiller))
+		Expect(fillers[1].Registered).To(BeFalse())
+		Expect(fillers[1].FillRate).To(Equal(0.0))
+		Expect(fillers[1].Score).To(Equal(model.FillerScore(0, 1, 1)))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// get the state transition history of the given order and its atomic swaps
+func (s *store) GetOrderHistory(orderID uint) (model.OrderHistory, error) {
+	order := model.Order{}
+	if tx := s.db.Unscoped().First(&order, orderID); tx.Error != nil {
+		return model.OrderHistory{}, notFound(tx.Error)
+	}
+	history := model.OrderHistory{
+		OrderID:             order.ID,
+		Subject:             order,
+		Order:               []model.OrderEvent{},
+		InitiatorAtomicSwap: []model.SwapEvent{},
+		FollowerAtomicSwap:  []model.SwapEvent{},
+	}
+	if tx := s.db.Where("order_id = ?", order.ID).Order("id ASC").Find(&history.Order); tx.Error != nil {
+		return model.OrderHistory{}, tx.Error
+	}
+	if tx := s.db.Where("swap_id = ?", order.InitiatorAtomicSwapID).Order("id ASC").Find(&history.InitiatorAtomicSwap); tx.Error != nil {
+		return model.OrderHistory{}, tx.Error
+	}
+	if tx := s.db.Where("swap_id = ?", order.FollowerAtomicSwapID).Order("id ASC").Find(&history.FollowerAtomicSwap); tx.Error != nil {
+		return model.OrderHistory{}, tx.Error
+	}
+	return history, nil
+}
+
+// save the given order and append an order event if its status or taker changed,
+// should be called within the transaction updating the order
+func saveOrder(tx *gorm.DB, order *model.Order, actor string) error {
+	prev := model.Order{}
+	if order.ID != 0 {
+		if err := tx.Unscoped().First(&prev, order.ID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	if err := tx.Omit("InitiatorAtomicSwap", "FollowerAtomicSwap", "Children").Save(order).Error; err != nil {
+		return err
+	}
+	if prev.ID != 0 && prev.Status == order.Status && prev.Taker == order.Taker {
+		return nil
+	}
+	return appendOrderEvent(tx, prev.Status, order, actor)
+}
+
+// save the given atomic swap and append a swap event if any of the tracked fields changed,
+// should be called within the transaction updating the swap
+func saveSwap(tx *gorm.DB, swap *model.AtomicSwap, actor string) error {
+	prev := model.AtomicSwap{}
+	if swap.ID != 0 {
+		if err := tx.First(&prev, swap.ID).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	if err := tx.Save(swap).Error; err != nil {
+		return err
+	}
+	if prev.ID != 0 && !swapChanged(prev, *swap) {
+		return nil
+	}
+	return appendSwapEvent(tx, prev.Status, swap, actor)
+}
+
+func appendOrderEvent(tx *gorm.DB, prevStatus model.Status, order *model.Order, actor string) error {
+	return tx.Create(&model.OrderEvent{
+		OrderID:    order.ID,
+		PrevStatus: prevStatus,
+		Status:     order.Status,
+		Taker:      order.Taker,
+		Actor:      actor,
+	}).Error
+}
+
+func appendSwapEvent(tx *gorm.DB, prevStatus model.SwapStatus, swap *model.AtomicSwap, actor string) error {
+	return tx.Create(&model.SwapEvent{
+		SwapID:               swap.ID,
+		PrevStatus:           prevStatus,
+		Status:               swap.Status,
+		CurrentConfirmations: swap.CurrentConfirmations,
+		FilledAmount:         swap.FilledAmount,
+		InitiateTxHash:       swap.InitiateTxHash,
+		RedeemTxHash:         swap.RedeemTxHash,
+		RefundTxHash:         swap.RefundTxHash,
+		Actor:                actor,
+	}).Error
+}
+
+func swapChanged(a, b model.AtomicSwap) bool {
+	return a.Status != b.Status ||
+		a.CurrentConfirmations != b.CurrentConfirmations ||
+		a.FilledAmount != b.FilledAmount ||
+		a.InitiateTxHash != b.InitiateTxHash ||
+		a.RedeemTxHash != b.RedeemTxHash ||
+		a.RefundTxHash != b.RefundTxHash
+}
@@ -0,0 +1,78 @@
+package store_test
+
+import (
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Order history", func() {
+	maker := testMaker
+
+	newOrder := func(store Store) model.Order {
+		return insertOrder(store, model.Order{Status: model.Created}, model.AtomicSwap{Chain: model.EthereumSepolia}, model.AtomicSwap{Chain: model.BitcoinTestnet})
+	}
+
+	It("should record every swap and order transition", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		order := newOrder(store)
+
+		swap := *order.InitiatorAtomicSwap
+		swap.Status = model.Detected
+		swap.InitiateTxHash = "0xinit"
+		Expect(store.UpdateSwap(&swap)).To(Succeed())
+		// unchanged swaps are not recorded
+		Expect(store.UpdateSwap(&swap)).To(Succeed())
+		swap.Status = model.Initiated
+		swap.CurrentConfirmations = 1
+		Expect(store.UpdateSwap(&swap)).To(Succeed())
+
+		order.InitiatorAtomicSwap = &swap
+		order.Status = model.Executed
+		Expect(store.UpdateOrder(&order)).To(Succeed())
+
+		history, err := store.GetOrderHistory(order.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history.OrderID).To(Equal(order.ID))
+		Expect(history.FollowerAtomicSwap).To(BeEmpty())
+		Expect(history.InitiatorAtomicSwap).To(HaveLen(2))
+		Expect(history.InitiatorAtomicSwap[0].PrevStatus).To(Equal(model.NotStarted))
+		Expect(history.InitiatorAtomicSwap[0].Status).To(Equal(model.Detected))
+		Expect(history.InitiatorAtomicSwap[0].InitiateTxHash).To(Equal("0xinit"))
+		Expect(history.InitiatorAtomicSwap[0].Actor).To(Equal("watcher:ethereum_sepolia"))
+		Expect(history.InitiatorAtomicSwap[1].PrevStatus).To(Equal(model.Detected))
+		Expect(history.InitiatorAtomicSwap[1].Status).To(Equal(model.Initiated))
+		Expect(history.InitiatorAtomicSwap[1].CurrentConfirmations).To(Equal(uint64(1)))
+		Expect(history.Order).To(HaveLen(1))
+		Expect(history.Order[0].PrevStatus).To(Equal(model.Created))
+		Expect(history.Order[0].Status).To(Equal(model.Executed))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should keep the history of cancelled orders", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		order := newOrder(store)
+
+		Expect(store.CancelOrder(maker, order.ID)).To(Succeed())
+
+		history, err := store.GetOrderHistory(order.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history.Order).To(HaveLen(1))
+		Expect(history.Order[0].Status).To(Equal(model.Cancelled))
+		Expect(history.Order[0].Actor).To(Equal("maker:" + maker))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should not find the history of an unknown order", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = store.GetOrderHistory(1)
+		Expect(err).To(MatchError(model.ErrNotFound))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
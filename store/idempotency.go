@@ -0,0 +1,72 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reserve an idempotency key for a request of the wallet, if the key is already
+// reserved the existing request is returned instead. Expired requests are replaced,
+// as are requests still in progress after the lock timeout, which are assumed to
+// have crashed or timed out.
+func (s *store) ReserveIdempotencyKey(wallet, key, fingerprint string, ttl, lockTimeout time.Duration) (*model.IdempotentRequest, bool, error) {
+	wallet = strings.ToLower(wallet)
+	if key == "" {
+		return nil, false, fmt.Errorf("empty idempotency key")
+	}
+
+	var existing model.IdempotentRequest
+	reserved := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		if err := tx.Where("wallet = ? AND idempotency_key = ? AND (expires_at <= ? OR (status = 0 AND reserved_at <= ?))", wallet, key, now, now.Add(-lockTimeout)).Delete(&model.IdempotentRequest{}).Error; err != nil {
+			return err
+		}
+
+		request := model.IdempotentRequest{
+			Wallet:      wallet,
+			Key:         key,
+			Fingerprint: fingerprint,
+			ReservedAt:  now,
+			ExpiresAt:   now.Add(ttl),
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&request)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			reserved = true
+			existing = request
+			return nil
+		}
+		return tx.Where("wallet = ? AND idempotency_key = ?", wallet, key).First(&existing).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, reserved, nil
+}
+
+// store the response to a reserved request, it is replayed until the request expires
+func (s *store) CompleteIdempotencyKey(wallet, key string, status int, response []byte) error {
+	res := s.db.Model(&model.IdempotentRequest{}).
+		Where("wallet = ? AND idempotency_key = ? AND status = 0", strings.ToLower(wallet), key).
+		Updates(map[string]interface{}{"status": status, "response": response})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("idempotency key %s is not reserved", key)
+	}
+	return nil
+}
+
+// release a reserved request without a response, so that it can be retried
+func (s *store) ReleaseIdempotencyKey(wallet, key string) error {
+	return s.db.Where("wallet = ? AND idempotency_key = ? AND status = 0", strings.ToLower(wallet), key).Delete(&model.IdempotentRequest{}).Error
+}
@@ -0,0 +1,94 @@
+package store_test
+
+import (
+	"time"
+
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Idempotency keys", func() {
+	wallet := "0xE103abfa0f867e53cef1ad2cb0dcbc193b385a93"
+	other := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+
+	It("should reserve a key once per wallet and replay its response", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, reserved, err := store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+		Expect(request.Status).To(BeZero())
+
+		// in progress
+		request, reserved, err = store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeFalse())
+		Expect(request.Status).To(BeZero())
+
+		// keys are scoped to the wallet
+		_, reserved, err = store.ReserveIdempotencyKey(other, "key", "fingerprint", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+
+		Expect(store.CompleteIdempotencyKey(wallet, "key", 201, []byte(`{"orderId":1}`))).To(Succeed())
+		Expect(store.CompleteIdempotencyKey(wallet, "key", 201, []byte(`{"orderId":2}`))).NotTo(Succeed())
+		request, reserved, err = store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeFalse())
+		Expect(request.Status).To(Equal(201))
+		Expect(string(request.Response)).To(Equal(`{"orderId":1}`))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should release keys and replace expired keys", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, reserved, err := store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+		Expect(store.ReleaseIdempotencyKey(wallet, "key")).To(Succeed())
+		_, reserved, err = store.ReserveIdempotencyKey(wallet, "key", "fingerprint", -time.Second, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+		Expect(store.CompleteIdempotencyKey(wallet, "key", 202, []byte(`{}`))).To(Succeed())
+
+		request, reserved, err := store.ReserveIdempotencyKey(wallet, "key", "another", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+		Expect(request.Fingerprint).To(Equal("another"))
+
+		_, _, err = store.ReserveIdempotencyKey(wallet, "", "fingerprint", time.Hour, time.Minute)
+		Expect(err).To(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should let a retry take over a request in progress after the lock timeout", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, reserved, err := store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+
+		// the first request crashed without releasing its reservation
+		_, reserved, err = store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeFalse())
+		request, reserved, err := store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, -time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeTrue())
+		Expect(request.Status).To(BeZero())
+
+		// completed requests are replayed however long ago they were reserved
+		Expect(store.CompleteIdempotencyKey(wallet, "key", 201, []byte(`{}`))).To(Succeed())
+		request, reserved, err = store.ReserveIdempotencyKey(wallet, "key", "fingerprint", time.Hour, -time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reserved).To(BeFalse())
+		Expect(request.Status).To(Equal(201))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// publish the inventory of a filler for a chain and asset, either an amount it
+// signed or an address whose balance is read on chain. Signed amounts have to be
+// published after the amount they replace.
+func (s *store) PublishInventory(inventory *model.Inventory, config model.Network) error {
+	if err := CheckAddress(model.Ethereum, inventory.Filler); err != nil {
+		return err
+	}
+	if _, ok := config[inventory.Chain].Assets[inventory.Asset]; !ok {
+		return fmt.Errorf("unsupported asset %s on %s", inventory.Asset, inventory.Chain)
+	}
+	switch inventory.Source {
+	case model.InventorySigned:
+		amount, ok := new(big.Int).SetString(inventory.Amount, 10)
+		if !ok || amount.Sign() < 0 {
+			return fmt.Errorf("invalid amount: %s", inventory.Amount)
+		}
+		if inventory.PublishedAt == nil || inventory.PublishedAt.After(time.Now().Add(model.MaxInventoryClockSkew)) {
+			return fmt.Errorf("inventory has to be published at most %s in the future", model.MaxInventoryClockSkew)
+		}
+		inventory.Address = ""
+	case model.InventoryOnChain:
+		if err := CheckAddress(inventory.Chain, inventory.Address); err != nil {
+			return fmt.Errorf("invalid address: %v", err)
+		}
+		// the balance of the address is read by the watcher
+		inventory.Amount = "0"
+		inventory.PublishedAt = nil
+		inventory.Signature = ""
+	default:
+		return fmt.Errorf("invalid inventory source %s", inventory.Source)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		existing := model.Inventory{}
+		err := tx.Where("filler = ? AND chain = ? AND asset = ?", inventory.Filler, inventory.Chain, inventory.Asset).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil {
+			if inventory.Source == model.InventorySigned && existing.Source == model.InventorySigned &&
+				existing.PublishedAt != nil && !inventory.PublishedAt.After(*existing.PublishedAt) {
+				return fmt.Errorf("inventory published at %d is not newer than the inventory published at %d", inventory.PublishedAt.Unix(), existing.PublishedAt.Unix())
+			}
+			inventory.ID = existing.ID
+			inventory.CreatedAt = existing.CreatedAt
+		}
+		return tx.Save(inventory).Error
+	})
+}
+
+// get the inventories of a filler, or of all fillers if none is given, published
+// since the given time if one is given
+func (s *store) GetInventories(filler string, since time.Time) ([]model.Inventory, error) {
+	query := s.db
+	if filler != "" {
+		query = query.Where("filler = ?", filler)
+	}
+	if !since.IsZero() {
+		query = query.Where("published_at >= ?", since.UTC())
+	}
+	inventories := []model.Inventory{}
+	if err := query.Order("filler ASC, chain ASC, asset ASC").Find(&inventories).Error; err != nil {
+		return nil, err
+	}
+	return inventories, nil
+}
+
+// record the balance of the address of an inventory read on chain, unless the
+// filler changed the inventory since
+func (s *store) UpdateInventoryBalance(inventory model.Inventory, amount *big.Int, readAt time.Time) error {
+	return s.db.Model(&model.Inventory{}).
+		Where("id = ? AND source = ? AND address = ?", inventory.ID, model.InventoryOnChain, inventory.Address).
+		Updates(map[string]interface{}{"amount": amount.String(), "published_at": readAt.UTC()}).Error
+}
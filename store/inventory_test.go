@@ -0,0 +1,60 @@
+package store_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Inventory", func() {
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	token := model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+	btcAddress := testBTCAddress
+
+	It("should publish signed inventories in order and read the others on chain", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		publishedAt := time.Unix(time.Now().Unix(), 0).UTC()
+		signed := func(amount string, at time.Time) *model.Inventory {
+			return &model.Inventory{Filler: filler, Chain: model.EthereumSepolia, Asset: token, Source: model.InventorySigned, Amount: amount, PublishedAt: &at}
+		}
+		Expect(store.PublishInventory(signed("100", publishedAt), config.Network)).To(Succeed())
+		// older and replayed amounts are rejected
+		Expect(store.PublishInventory(signed("200", publishedAt), config.Network)).NotTo(Succeed())
+		Expect(store.PublishInventory(signed("200", publishedAt.Add(-time.Second)), config.Network)).NotTo(Succeed())
+		Expect(store.PublishInventory(signed("200", publishedAt.Add(time.Hour)), config.Network)).NotTo(Succeed())
+		Expect(store.PublishInventory(signed("-1", publishedAt.Add(time.Second)), config.Network)).NotTo(Succeed())
+		Expect(store.PublishInventory(signed("200", publishedAt.Add(time.Second)), config.Network)).To(Succeed())
+		unsupported := signed("200", publishedAt.Add(2*time.Second))
+		unsupported.Chain = model.Ethereum
+		Expect(store.PublishInventory(unsupported, config.Network)).NotTo(Succeed())
+
+		onChain := &model.Inventory{Filler: filler, Chain: model.BitcoinTestnet, Asset: model.Primary, Source: model.InventoryOnChain, Address: "invalid"}
+		Expect(store.PublishInventory(onChain, config.Network)).NotTo(Succeed())
+		onChain.Address = btcAddress
+		Expect(store.PublishInventory(onChain, config.Network)).To(Succeed())
+
+		// inventories read on chain are only available once read
+		available, err := store.GetInventories("", time.Now().Add(-time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(available).To(HaveLen(1))
+		Expect(available[0].Amount).To(Equal("200"))
+
+		inventories, err := store.GetInventories(filler, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventories).To(HaveLen(2))
+		Expect(inventories[0].Chain).To(Equal(model.BitcoinTestnet))
+		Expect(store.UpdateInventoryBalance(inventories[0], big.NewInt(5000), time.Now())).To(Succeed())
+		available, err = store.GetInventories(filler, time.Now().Add(-time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(available).To(HaveLen(2))
+		Expect(available[0].Amount).To(Equal("5000"))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,94 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Limit orders", func() {
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	pair := testPair
+
+	It("should validate the expiry of limit orders", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000", Terms: model.OrderTerms{Type: model.LimitOrder}})
+		Expect(err).To(HaveOccurred())
+		_, err = createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: time.Now().Add(model.MaxLimitOrderExpiry + time.Hour)}})
+		Expect(err).To(HaveOccurred())
+		_, err = createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000", Terms: model.OrderTerms{ExpiresAt: time.Now().Add(time.Hour)}})
+		Expect(err).To(HaveOccurred())
+
+		id, err := createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: time.Now().Add(time.Hour)}})
+		Expect(err).NotTo(HaveOccurred())
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Type).To(Equal(model.LimitOrder))
+		Expect(order.ExpiresAt).NotTo(BeNil())
+
+		id, err = createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000"})
+		Expect(err).NotTo(HaveOccurred())
+		order, err = store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Type).To(Equal(model.MarketOrder))
+		Expect(order.ExpiresAt).To(BeNil())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should not fill expired limit orders", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: time.Now().Add(time.Hour)}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Gorm().Model(&model.Order{}).Where("id = ?", id).Update("expires_at", time.Now().Add(-time.Minute)).Error).NotTo(HaveOccurred())
+		Expect(store.FillOrder(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(MatchError(ContainSubstring("expired")))
+
+		id, err = createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: time.Now().Add(time.Hour)}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.FillOrder(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(Succeed())
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Filled))
+		Expect(order.FilledAt).NotTo(BeNil())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should list the best priced open limit orders first", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		expiresAt := time.Now().Add(time.Hour)
+		worst, err := createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "110000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: expiresAt}})
+		Expect(err).NotTo(HaveOccurred())
+		best, err := createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "90000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: expiresAt}})
+		Expect(err).NotTo(HaveOccurred())
+		middle, err := createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "100000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: expiresAt}})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "80000000"})
+		Expect(err).NotTo(HaveOccurred())
+		expired, err := createOrder(store, testOrder{SendAmount: "100000000", ReceiveAmount: "70000000", Terms: model.OrderTerms{Type: model.LimitOrder, ExpiresAt: expiresAt}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Gorm().Model(&model.Order{}).Where("id = ?", expired).Update("expires_at", time.Now().Add(-time.Minute)).Error).NotTo(HaveOccurred())
+
+		filter := model.OrderFilter{OrderPair: pair, Status: model.Created, Type: model.LimitOrder, ExpiresAfter: time.Now(), Sort: "-" + model.SortByPrice, Limit: 2}
+		orders, cursor, err := store.FilterOrders(filter)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(HaveLen(2))
+		Expect(orders[0].ID).To(Equal(best))
+		Expect(orders[1].ID).To(Equal(middle))
+		Expect(cursor).NotTo(BeEmpty())
+
+		filter.Cursor = cursor
+		orders, cursor, err = store.FilterOrders(filter)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(HaveLen(1))
+		Expect(orders[0].ID).To(Equal(worst))
+		Expect(cursor).To(BeEmpty())
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
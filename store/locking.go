@@ -0,0 +1,43 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// get the order with a row lock held until the end of the transaction, on dialects
+// without row locks (i.e. SQLite) the order is read without a lock and concurrent
+// writers are only serialized by the database, see transitionOrder
+func lockOrder(tx *gorm.DB, orderID uint) (*model.Order, error) {
+	order := &model.Order{}
+	query := tx
+	if tx.Dialector.Name() != "sqlite" {
+		query = tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.First(order, orderID).Error; err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// save the order if it still has the given status and append an order event. The
+// status is checked by a conditional update, so only one of concurrent transitions
+// succeeds even when the order was read without a row lock.
+func transitionOrder(tx *gorm.DB, order *model.Order, from model.Status, actor string) error {
+	res := tx.Model(&model.Order{}).
+		Where("id = ? AND status = ?", order.ID, from).
+		Updates(map[string]interface{}{"status": order.Status, "taker": order.Taker})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("order %d is no longer in status %v", order.ID, from)
+	}
+	if err := tx.Omit("InitiatorAtomicSwap", "FollowerAtomicSwap", "Children").Save(order).Error; err != nil {
+		return err
+	}
+	return appendOrderEvent(tx, from, order, actor)
+}
@@ -0,0 +1,121 @@
+package store_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Concurrent fills and cancels", func() {
+	maker := testMaker
+	network := model.Network{
+		model.BitcoinTestnet: model.NetworkConfig{
+			Assets: map[model.Asset]model.Token{model.Primary: {Decimals: 8}},
+			Expiry: 144,
+		},
+		model.EthereumSepolia: model.NetworkConfig{
+			Assets: map[model.Asset]model.Token{model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {Decimals: 8}},
+			Expiry: 7200,
+		},
+	}
+
+	newOrder := func(store Store) model.Order {
+		initiator := model.AtomicSwap{Chain: model.BitcoinTestnet, InitiatorAddress: testBTCAddress, Amount: "100000"}
+		follower := model.AtomicSwap{Chain: model.EthereumSepolia, RedeemerAddress: maker, Amount: "100000"}
+		return insertOrder(store, model.Order{Status: model.Created}, initiator, follower)
+	}
+
+	// two stores on the same database behave like two rest replicas, immediate
+	// transactions make sqlite writers wait for each other instead of failing
+	replicas := func() []Store {
+		stores := make([]Store, 2)
+		for i := range stores {
+			store, err := New(testDialector(), &gorm.Config{})
+			Expect(err).NotTo(HaveOccurred())
+			stores[i] = store
+		}
+		return stores
+	}
+
+	filler := func(i int) string {
+		return fmt.Sprintf("0x%040x", i+1)
+	}
+
+	It("should let exactly one of concurrent fills win", func() {
+		stores := replicas()
+		order := newOrder(stores[0])
+
+		wins := int64(0)
+		wg := new(sync.WaitGroup)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				err := stores[i%2].FillOrder(order.ID, filler(i), filler(i), "mxHKgg7dU4pt9abWXveMofqRvWr7f6xx7g", network)
+				if err == nil {
+					atomic.AddInt64(&wins, 1)
+					return
+				}
+				Expect(err).To(MatchError(ContainSubstring("order already filled")))
+			}(i)
+		}
+		wg.Wait()
+		Expect(wins).To(Equal(int64(1)))
+
+		filled, err := stores[0].GetOrder(order.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(filled.Status).To(Equal(model.Filled))
+		Expect(filled.FollowerAtomicSwap.InitiatorAddress).To(Equal(filled.Taker))
+
+		history, err := stores[1].GetOrderHistory(order.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history.Order).To(HaveLen(1))
+		Expect(history.Order[0].Taker).To(Equal(filled.Taker))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should let exactly one of a concurrent fill and cancel win", func() {
+		stores := replicas()
+		for round := 0; round < 5; round++ {
+			order := newOrder(stores[0])
+
+			wins := int64(0)
+			wg := new(sync.WaitGroup)
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					var err error
+					if i%2 == 0 {
+						err = stores[0].CancelOrder(maker, order.ID)
+					} else {
+						err = stores[1].FillOrder(order.ID, filler(i), filler(i), "mxHKgg7dU4pt9abWXveMofqRvWr7f6xx7g", network)
+					}
+					if err == nil {
+						atomic.AddInt64(&wins, 1)
+						return
+					}
+					// cancelled orders are deleted
+					Expect(err).To(MatchError(Or(ContainSubstring("order already filled"), ContainSubstring("only if it is not filled"), ContainSubstring("record not found"))))
+				}(i)
+			}
+			wg.Wait()
+			Expect(wins).To(Equal(int64(1)))
+
+			history, err := stores[0].GetOrderHistory(order.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history.Order).To(HaveLen(1))
+			Expect(history.Order[0].PrevStatus).To(Equal(model.Created))
+			Expect(history.Order[0].Status).To(BeElementOf(model.Filled, model.Cancelled))
+		}
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
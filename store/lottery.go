@@ -0,0 +1,191 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/watcher"
+	"gorm.io/gorm"
+)
+
+// generate the secret seed of a new lottery and the hash it is committed to by
+func newLotterySeed() (string, string, error) {
+	seed := [32]byte{}
+	if _, err := rand.Read(seed[:]); err != nil {
+		return "", "", fmt.Errorf("failed to generate lottery seed: %v", err)
+	}
+	seedHash, err := model.LotterySeedHash(hex.EncodeToString(seed[:]))
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(seed[:]), seedHash, nil
+}
+
+// register the intent of a filler to fill a lottery order before the lottery
+// ends, registering again updates the addresses of the filler
+func (s *store) RegisterFillIntent(orderID uint, filler, sendAddress, receiveAddress string, config model.Network) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != model.Created || !order.InLottery() {
+			return fmt.Errorf("order %d is not in a lottery", orderID)
+		}
+		if !time.Now().Before(*order.LotteryEndsAt) {
+			return fmt.Errorf("lottery of order %d ended at %s", orderID, order.LotteryEndsAt.UTC().Format(time.RFC3339))
+		}
+		if filler == order.Maker {
+			return fmt.Errorf("maker cannot fill its own order")
+		}
+		if !order.CanBeFilledBy(filler) {
+			return fmt.Errorf("order is private and cannot be filled by %s", filler)
+		}
+		fromChain, toChain, _, _, err := model.ParseOrderPair(order.OrderPair)
+		if err != nil {
+			return fmt.Errorf("constraint violation: corrupted order pair: %v", err)
+		}
+		if _, ok := config[fromChain]; !ok {
+			return fmt.Errorf("unsupported chain %s", fromChain)
+		}
+		if _, ok := config[toChain]; !ok {
+			return fmt.Errorf("unsupported chain %s", toChain)
+		}
+		if err := CheckAddress(fromChain, receiveAddress); err != nil {
+			return fmt.Errorf("invalid receive address: %v", err)
+		}
+		if err := CheckAddress(toChain, sendAddress); err != nil {
+			return fmt.Errorf("invalid send address: %v", err)
+		}
+
+		intent := model.FillIntent{}
+		err = tx.Where("order_id = ? AND filler = ?", orderID, filler).First(&intent).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		intent.OrderID = orderID
+		intent.Filler = filler
+		intent.SendAddress = sendAddress
+		intent.ReceiveAddress = receiveAddress
+		return tx.Save(&intent).Error
+	})
+}
+
+// get the fill intents registered for an order, with their weights and scores
+// once the lottery is drawn
+func (s *store) GetFillIntents(orderID uint) ([]model.FillIntent, error) {
+	intents := []model.FillIntent{}
+	if err := s.db.Where("order_id = ?", orderID).Order("id ASC").Find(&intents).Error; err != nil {
+		return nil, err
+	}
+	return intents, nil
+}
+
+// draw the lotteries which have ended and fill their orders with the winners
+func (s *store) DrawLotteries(config model.Network) error {
+	orderIDs := []uint{}
+	if err := s.db.Model(&model.Order{}).
+		Where("status = ? AND lottery_ends_at <= ? AND (lottery_seed = '' OR lottery_seed IS NULL)", model.Created, time.Now().UTC()).
+		Order("id ASC").Pluck("id", &orderIDs).Error; err != nil {
+		return err
+	}
+	errs := []error{}
+	for _, orderID := range orderIDs {
+		if err := s.drawLottery(orderID, config); err != nil {
+			errs = append(errs, fmt.Errorf("failed to draw lottery of order %d: %v", orderID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reveal the seed of the lottery of an order and fill it with the highest ranked
+// filler who can fill it. Orders no filler can fill are left to the first filler.
+func (s *store) drawLottery(orderID uint, config model.Network) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if order.Status != model.Created || !order.InLottery() || now.Before(*order.LotteryEndsAt) {
+			return nil
+		}
+		intents := []model.FillIntent{}
+		if err := tx.Where("order_id = ?", orderID).Find(&intents).Error; err != nil {
+			return err
+		}
+		fillers := make([]string, len(intents))
+		for i, intent := range intents {
+			fillers[i] = intent.Filler
+		}
+		weights, err := fillerWeights(tx, fillers)
+		if err != nil {
+			return err
+		}
+		for i := range intents {
+			intents[i].Weight = weights[intents[i].Filler]
+		}
+		ranked, err := model.DrawLottery(order.LotterySecret, intents)
+		if err != nil {
+			return err
+		}
+
+		order.LotterySeed = order.LotterySecret
+		if err := tx.Model(&model.Order{}).Where("id = ?", orderID).Update("lottery_seed", order.LotterySeed).Error; err != nil {
+			return err
+		}
+		for _, intent := range ranked {
+			if err := tx.Model(&model.FillIntent{}).Where("id = ?", intent.ID).
+				Updates(map[string]interface{}{"weight": intent.Weight, "score": intent.Score}).Error; err != nil {
+				return err
+			}
+		}
+		if order.IsExpired(now, watcher.OrderTimeout) {
+			return nil
+		}
+		for _, intent := range ranked {
+			// the fill of a winner who cannot fill the order, for example as it
+			// would lock more than the value limits allow, is rolled back
+			if err := tx.SavePoint("lottery").Error; err != nil {
+				return err
+			}
+			winner := *order
+			winner.RandomScore = intent.Score
+			winner.RandomMultiplier = intent.Weight
+			if err := s.fillOrder(tx, &winner, intent.Filler, intent.SendAddress, intent.ReceiveAddress, config, now); err != nil {
+				if err := tx.RollbackTo("lottery").Error; err != nil {
+					return err
+				}
+				continue
+			}
+			return tx.Model(&model.FillIntent{}).Where("id = ?", intent.ID).Update("won", true).Error
+		}
+		return nil
+	})
+}
+
+// the lottery weights of the fillers, their scores from the orders they filled
+func fillerWeights(tx *gorm.DB, fillers []string) (map[string]uint64, error) {
+	weights := make(map[string]uint64, len(fillers))
+	for _, filler := range fillers {
+		weights[filler] = model.FillerScore(0, 0, 0)
+	}
+	if len(fillers) == 0 {
+		return weights, nil
+	}
+	outcomes, err := fillerOutcomes(tx, fillers)
+	if err != nil {
+		return nil, err
+	}
+	for _, outcome := range outcomes {
+		weights[outcome.Taker] = model.FillerScore(outcome.Executed, outcome.FailedSoft, outcome.FailedHard)
+	}
+	return weights, nil
+}
@@ -0,0 +1,88 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Lotteries", func() {
+	maker := testMaker
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	otherFiller := "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0"
+	btcAddress := testBTCAddress
+
+	endLottery := func(store Store, id uint) {
+		Expect(store.Gorm().Model(&model.Order{}).Where("id = ?", id).Update("lottery_ends_at", time.Now().UTC().Add(-time.Second)).Error).NotTo(HaveOccurred())
+	}
+
+	It("should fill the order with the winner of the lottery", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := createOrder(store, testOrder{Terms: model.OrderTerms{LotteryWindow: time.Minute}})
+		Expect(err).NotTo(HaveOccurred())
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.LotteryEndsAt).NotTo(BeNil())
+		Expect(order.LotterySeedHash).NotTo(BeEmpty())
+		Expect(order.LotterySeed).To(BeEmpty())
+
+		Expect(store.FillOrder(id, filler, filler, btcAddress, config.Network)).To(MatchError(ContainSubstring("lottery")))
+		Expect(store.RegisterFillIntent(id, maker, maker, btcAddress, config.Network)).NotTo(Succeed())
+		Expect(store.RegisterFillIntent(id, filler, filler, "invalid", config.Network)).NotTo(Succeed())
+		Expect(store.RegisterFillIntent(id, filler, filler, btcAddress, config.Network)).To(Succeed())
+		Expect(store.RegisterFillIntent(id, otherFiller, otherFiller, btcAddress, config.Network)).To(Succeed())
+
+		// lotteries are only drawn once they end
+		Expect(store.DrawLotteries(config.Network)).To(Succeed())
+		order, err = store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Created))
+
+		endLottery(store, id)
+		Expect(store.RegisterFillIntent(id, filler, filler, btcAddress, config.Network)).NotTo(Succeed())
+		Expect(store.DrawLotteries(config.Network)).To(Succeed())
+
+		order, err = store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Filled))
+		seedHash, err := model.LotterySeedHash(order.LotterySeed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(seedHash).To(Equal(order.LotterySeedHash))
+
+		// the draw can be audited from the revealed seed and the published weights
+		intents, err := store.GetFillIntents(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(intents).To(HaveLen(2))
+		ranked, err := model.DrawLottery(order.LotterySeed, intents)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Taker).To(Equal(ranked[0].Filler))
+		Expect(order.RandomScore).To(Equal(ranked[0].Score))
+		// neither filler executed an order yet
+		Expect(order.RandomMultiplier).To(Equal(uint64(50)))
+		for _, intent := range intents {
+			Expect(intent.Won).To(Equal(intent.Filler == order.Taker))
+		}
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should leave the order to the first filler when no filler registered", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := createOrder(store, testOrder{Terms: model.OrderTerms{LotteryWindow: time.Minute}})
+		Expect(err).NotTo(HaveOccurred())
+		endLottery(store, id)
+		Expect(store.DrawLotteries(config.Network)).To(Succeed())
+
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Created))
+		Expect(order.LotterySeed).NotTo(BeEmpty())
+		Expect(store.FillOrder(id, filler, filler, btcAddress, config.Network)).To(Succeed())
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// converts the float64 price columns of existing databases to exact decimals,
+// existing rows are converted by the database when the column type is altered
+func migrateDecimalColumns(db *gorm.DB) error {
+	for _, column := range []struct {
+		model interface{}
+		name  string
+	}{
+		{&orderV1{}, "price"},
+		{&atomicSwapV1{}, "price_by_oracle"},
+	} {
+		if !db.Migrator().HasTable(column.model) {
+			continue
+		}
+		columnTypes, err := db.Migrator().ColumnTypes(column.model)
+		if err != nil {
+			return err
+		}
+		for _, columnType := range columnTypes {
+			if columnType.Name() != column.name || !isFloatType(columnType.DatabaseTypeName()) {
+				continue
+			}
+			if err := db.Migrator().AlterColumn(column.model, column.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isFloatType(databaseType string) bool {
+	switch strings.ToLower(databaseType) {
+	case "float", "float4", "float8", "double", "double precision", "real":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package store_test
+
+import (
+	"strings"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+type legacyOrder struct {
+	gorm.Model
+	SecretHash string
+	Price      float64
+}
+
+func (legacyOrder) TableName() string { return "orders" }
+
+type legacyAtomicSwap struct {
+	gorm.Model
+	Amount        string
+	PriceByOracle float64
+}
+
+func (legacyAtomicSwap) TableName() string { return "atomic_swaps" }
+
+var _ = Describe("Decimal migration", func() {
+	It("should convert float prices of existing rows to decimals", func() {
+		db, err := gorm.Open(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(db.AutoMigrate(&legacyOrder{}, &legacyAtomicSwap{})).To(Succeed())
+		Expect(db.Create(&legacyOrder{SecretHash: "secretHash", Price: 0.1}).Error).NotTo(HaveOccurred())
+		Expect(db.Create(&legacyAtomicSwap{Amount: "100000000", PriceByOracle: 42000.12}).Error).NotTo(HaveOccurred())
+
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		order := model.Order{}
+		Expect(store.Gorm().First(&order).Error).NotTo(HaveOccurred())
+		Expect(order.Price.String()).To(Equal("0.1"))
+		swap := model.AtomicSwap{}
+		Expect(store.Gorm().First(&swap).Error).NotTo(HaveOccurred())
+		Expect(swap.PriceByOracle.String()).To(Equal("42000.12"))
+
+		columnTypes, err := store.Gorm().Migrator().ColumnTypes(&model.Order{})
+		Expect(err).NotTo(HaveOccurred())
+		for _, columnType := range columnTypes {
+			if columnType.Name() == "price" {
+				Expect(strings.ToLower(columnType.DatabaseTypeName())).To(BeElementOf("numeric", "decimal"))
+			}
+		}
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
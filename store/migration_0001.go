@@ -0,0 +1,175 @@
+package store
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// the schema of the tables created by AutoMigrate before versioned migrations,
+// frozen here so that changes to the models need migrations of their own
+
+type orderV1 struct {
+	gorm.Model
+
+	Maker     string
+	Taker     string
+	OrderPair string
+
+	InitiatorAtomicSwapID uint
+	FollowerAtomicSwapID  uint
+	InitiatorAtomicSwap   *atomicSwapV1 `gorm:"foreignKey:InitiatorAtomicSwapID"`
+	FollowerAtomicSwap    *atomicSwapV1 `gorm:"foreignKey:FollowerAtomicSwapID"`
+
+	SecretHash           string `gorm:"unique;not null"`
+	Secret               string
+	Price                model.Decimal
+	Status               model.Status
+	SecretNonce          uint64
+	UserBtcWalletAddress string
+	FeeInSeed            string
+	IsDiscounted         bool
+	SecretUpdatedAt      time.Time
+	RandomMultiplier     uint64
+	RandomScore          uint64
+
+	Fee uint
+}
+
+func (orderV1) TableName() string { return "orders" }
+
+type atomicSwapV1 struct {
+	gorm.Model
+
+	Status               model.SwapStatus
+	SecretHash           string
+	Secret               string
+	OnChainIdentifier    string
+	InitiatorAddress     string
+	RedeemerAddress      string
+	Timelock             string
+	Chain                model.Chain
+	Asset                model.Asset
+	Amount               string
+	FilledAmount         string
+	InitiateTxHash       string
+	RedeemTxHash         string
+	RefundTxHash         string
+	PriceByOracle        model.Decimal
+	MinimumConfirmations uint64
+	CurrentConfirmations uint64
+	InitiateBlockNumber  uint64
+	RedeemBlockNumber    uint64
+	RefundBlockNumber    uint64
+	IsInstantWallet      bool
+}
+
+func (atomicSwapV1) TableName() string { return "atomic_swaps" }
+
+type blacklistV1 struct {
+	gorm.Model
+	Address string `gorm:"unique"`
+}
+
+func (blacklistV1) TableName() string { return "blacklists" }
+
+type blockCursorV1 struct {
+	Chain       model.Chain `gorm:"primaryKey"`
+	Contract    string      `gorm:"primaryKey"`
+	BlockNumber uint64
+	UpdatedAt   time.Time
+}
+
+func (blockCursorV1) TableName() string { return "block_cursors" }
+
+type processedBlockV1 struct {
+	Chain       model.Chain `gorm:"primaryKey"`
+	Contract    string      `gorm:"primaryKey"`
+	BlockNumber uint64      `gorm:"primaryKey;autoIncrement:false"`
+	BlockHash   string
+	CreatedAt   time.Time
+}
+
+func (processedBlockV1) TableName() string { return "processed_blocks" }
+
+type orderEventV1 struct {
+	ID         uint `gorm:"primaryKey"`
+	OrderID    uint `gorm:"index"`
+	PrevStatus model.Status
+	Status     model.Status
+	Taker      string
+	Actor      string
+	CreatedAt  time.Time
+}
+
+func (orderEventV1) TableName() string { return "order_events" }
+
+type swapEventV1 struct {
+	ID                   uint `gorm:"primaryKey"`
+	SwapID               uint `gorm:"index"`
+	PrevStatus           model.SwapStatus
+	Status               model.SwapStatus
+	CurrentConfirmations uint64
+	FilledAmount         string
+	InitiateTxHash       string
+	RedeemTxHash         string
+	RefundTxHash         string
+	Actor                string
+	CreatedAt            time.Time
+}
+
+func (swapEventV1) TableName() string { return "swap_events" }
+
+type userLimitV1 struct {
+	gorm.Model
+
+	Wallet     string `gorm:"index"`
+	OrderPair  string
+	DailyLimit string
+	MinTxLimit string
+	MaxTxLimit string
+	Expiry     time.Time
+	GrantedBy  string
+	Reason     string
+	RevokedBy  string
+}
+
+func (userLimitV1) TableName() string { return "user_limits" }
+
+type idempotentRequestV1 struct {
+	ID          uint   `gorm:"primarykey"`
+	Wallet      string `gorm:"size:64;uniqueIndex:idx_idempotent_wallet_key"`
+	Key         string `gorm:"column:idempotency_key;size:255;uniqueIndex:idx_idempotent_wallet_key"`
+	Fingerprint string
+	Status      int
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+func (idempotentRequestV1) TableName() string { return "idempotent_requests" }
+
+// tables referenced by others come first
+func initialSchema() []interface{} {
+	return []interface{}{&atomicSwapV1{}, &orderV1{}, &blacklistV1{}, &blockCursorV1{}, &processedBlockV1{}, &orderEventV1{}, &swapEventV1{}, &userLimitV1{}, &idempotentRequestV1{}}
+}
+
+// creates the tables, databases created by AutoMigrate before versioned migrations
+// already have them and only get their float prices converted to decimals
+func upInitialSchema(tx *gorm.DB) error {
+	if err := migrateDecimalColumns(tx); err != nil {
+		return err
+	}
+	return tx.AutoMigrate(initialSchema()...)
+}
+
+func downInitialSchema(tx *gorm.DB) error {
+	tables := initialSchema()
+	for i := len(tables) - 1; i >= 0; i-- {
+		if err := tx.Migrator().DropTable(tables[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
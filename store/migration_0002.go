@@ -0,0 +1,36 @@
+package store
+
+import (
+	"embed"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationSQL embed.FS
+
+// executes an embedded sql migration
+func execMigrationSQL(tx *gorm.DB, name string) error {
+	sql, err := migrationSQL.ReadFile("migrations/" + name)
+	if err != nil {
+		return fmt.Errorf("error reading migration %s: %v", name, err)
+	}
+	return tx.Exec(string(sql)).Error
+}
+
+// the triggers notifying listeners of updates and protecting the history are
+// written in plpgsql, other databases run without them
+func upNotifyTriggers(tx *gorm.DB) error {
+	if tx.Dialector.Name() != dialectPostgres {
+		return nil
+	}
+	return execMigrationSQL(tx, "0002_notify_triggers.up.sql")
+}
+
+func downNotifyTriggers(tx *gorm.DB) error {
+	if tx.Dialector.Name() != dialectPostgres {
+		return nil
+	}
+	return execMigrationSQL(tx, "0002_notify_triggers.down.sql")
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// order statistics rolled up into buckets of time by the stats job
+
+type orderRollupV3 struct {
+	Granularity string    `gorm:"primaryKey;size:8"`
+	BucketStart time.Time `gorm:"primaryKey;autoIncrement:false"`
+	OrderPair   string    `gorm:"primaryKey;size:255"`
+	Status      uint      `gorm:"primaryKey;autoIncrement:false"`
+	Trades      int64
+	Volume      model.Decimal
+}
+
+func (orderRollupV3) TableName() string { return "order_rollups" }
+
+type userRollupV3 struct {
+	Granularity string    `gorm:"primaryKey;size:8"`
+	BucketStart time.Time `gorm:"primaryKey;autoIncrement:false"`
+	Wallet      string    `gorm:"primaryKey;size:64"`
+	Trades      int64
+	Volume      model.Decimal
+}
+
+func (userRollupV3) TableName() string { return "user_rollups" }
+
+type rollupCursorV3 struct {
+	Name      string `gorm:"primaryKey;size:64"`
+	Until     time.Time
+	UpdatedAt time.Time
+}
+
+func (rollupCursorV3) TableName() string { return "rollup_cursors" }
+
+func upRollups(tx *gorm.DB) error {
+	return tx.AutoMigrate(&orderRollupV3{}, &userRollupV3{}, &rollupCursorV3{})
+}
+
+func downRollups(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&rollupCursorV3{}, &userRollupV3{}, &orderRollupV3{})
+}
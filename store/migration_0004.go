@@ -0,0 +1,31 @@
+package store
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// periodic snapshots of the value locked per chain and asset
+
+type tvlSnapshotV4 struct {
+	ID         uint      `gorm:"primaryKey"`
+	TakenAt    time.Time `gorm:"index"`
+	Chain      string    `gorm:"size:64;index"`
+	Asset      string    `gorm:"size:255"`
+	Amount     model.Decimal
+	Price      model.Decimal
+	Value      model.Decimal
+	Resolution string `gorm:"size:8"`
+}
+
+func (tvlSnapshotV4) TableName() string { return "tvl_snapshots" }
+
+func upTVLSnapshots(tx *gorm.DB) error {
+	return tx.AutoMigrate(&tvlSnapshotV4{})
+}
+
+func downTVLSnapshots(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&tvlSnapshotV4{})
+}
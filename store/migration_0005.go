@@ -0,0 +1,29 @@
+package store
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// the trade tape of executed orders
+
+type tradeV5 struct {
+	OrderID       uint   `gorm:"primaryKey;autoIncrement:false"`
+	OrderPair     string `gorm:"size:255;index:idx_trades_pair_executed_at,priority:1"`
+	SendAmount    model.Decimal
+	ReceiveAmount model.Decimal
+	Price         model.Decimal
+	ExecutedAt    time.Time `gorm:"index:idx_trades_pair_executed_at,priority:2"`
+}
+
+func (tradeV5) TableName() string { return "trades" }
+
+func upTrades(tx *gorm.DB) error {
+	return tx.AutoMigrate(&tradeV5{})
+}
+
+func downTrades(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&tradeV5{})
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// the type and expiry of orders, existing orders are market orders
+
+type orderV6 struct {
+	ID        uint
+	Status    model.Status  `gorm:"index:idx_orders_status_type_price,priority:1"`
+	Type      string        `gorm:"size:16;default:market;index:idx_orders_status_type_price,priority:2"`
+	Price     model.Decimal `gorm:"index:idx_orders_status_type_price,priority:3"`
+	ExpiresAt *time.Time
+	FilledAt  *time.Time
+}
+
+func (orderV6) TableName() string { return "orders" }
+
+func upOrderTypes(tx *gorm.DB) error {
+	for _, column := range []string{"Type", "ExpiresAt", "FilledAt"} {
+		if err := tx.Migrator().AddColumn(&orderV6{}, column); err != nil {
+			return err
+		}
+	}
+	return tx.Migrator().CreateIndex(&orderV6{}, "idx_orders_status_type_price")
+}
+
+func downOrderTypes(tx *gorm.DB) error {
+	if err := tx.Migrator().DropIndex(&orderV6{}, "idx_orders_status_type_price"); err != nil {
+		return err
+	}
+	for _, column := range []string{"Type", "ExpiresAt", "FilledAt"} {
+		if err := tx.Migrator().DropColumn(&orderV6{}, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
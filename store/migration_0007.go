@@ -0,0 +1,32 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// the price curve of Dutch auction orders
+
+type orderV7 struct {
+	ID                 uint
+	AuctionStartAmount string `gorm:"size:78"`
+	AuctionEndAmount   string `gorm:"size:78"`
+	AuctionStartsAt    *time.Time
+	AuctionEndsAt      *time.Time
+}
+
+func (orderV7) TableName() string { return "orders" }
+
+func upAuctions(tx *gorm.DB) error {
+	for _, column := range []string{"AuctionStartAmount", "AuctionEndAmount", "AuctionStartsAt", "AuctionEndsAt"} {
+		if err := tx.Migrator().AddColumn(&orderV7{}, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downAuctions(tx *gorm.DB) error {
+	return dropColumns(tx, "orders", "auction_start_amount", "auction_end_amount", "auction_starts_at", "auction_ends_at")
+}
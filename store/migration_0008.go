@@ -0,0 +1,34 @@
+package store
+
+import (
+	"gorm.io/gorm"
+)
+
+// partially fillable orders and the child orders filling them
+
+type orderV8 struct {
+	ID               uint
+	Partial          bool
+	ParentOrderID    *uint  `gorm:"index:idx_orders_parent_order_id"`
+	FilledAmount     string `gorm:"size:78"`
+	RemainingAmount  string `gorm:"size:78"`
+	PartSecretHashes string `gorm:"type:text"`
+}
+
+func (orderV8) TableName() string { return "orders" }
+
+func upPartialFills(tx *gorm.DB) error {
+	for _, column := range []string{"Partial", "ParentOrderID", "FilledAmount", "RemainingAmount", "PartSecretHashes"} {
+		if err := tx.Migrator().AddColumn(&orderV8{}, column); err != nil {
+			return err
+		}
+	}
+	return tx.Migrator().CreateIndex(&orderV8{}, "idx_orders_parent_order_id")
+}
+
+func downPartialFills(tx *gorm.DB) error {
+	if err := tx.Migrator().DropIndex(&orderV8{}, "idx_orders_parent_order_id"); err != nil {
+		return err
+	}
+	return dropColumns(tx, "orders", "partial", "parent_order_id", "filled_amount", "remaining_amount", "part_secret_hashes")
+}
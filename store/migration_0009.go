@@ -0,0 +1,55 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// requests for quotes, the quotes of fillers and the orders created from them
+
+type quoteRequestV9 struct {
+	gorm.Model
+	Requester       string `gorm:"size:255;index"`
+	OrderPair       string `gorm:"size:255;index"`
+	SendAmount      string `gorm:"size:78"`
+	ExpiresAt       time.Time
+	Status          string `gorm:"size:16"`
+	AcceptedQuoteID *uint
+	OrderID         *uint
+}
+
+func (quoteRequestV9) TableName() string { return "quote_requests" }
+
+type quoteV9 struct {
+	gorm.Model
+	QuoteRequestID uint   `gorm:"index"`
+	Filler         string `gorm:"size:255;index"`
+	ReceiveAmount  string `gorm:"size:78"`
+	ValidUntil     time.Time
+	Signature      string
+	Accepted       bool
+}
+
+func (quoteV9) TableName() string { return "quotes" }
+
+type orderV9 struct {
+	ID      uint
+	QuoteID *uint
+}
+
+func (orderV9) TableName() string { return "orders" }
+
+func upQuotes(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&quoteRequestV9{}, &quoteV9{}); err != nil {
+		return err
+	}
+	return tx.Migrator().AddColumn(&orderV9{}, "QuoteID")
+}
+
+func downQuotes(tx *gorm.DB) error {
+	if err := dropColumns(tx, "orders", "quote_id"); err != nil {
+		return err
+	}
+	return tx.Migrator().DropTable(&quoteV9{}, &quoteRequestV9{})
+}
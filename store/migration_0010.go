@@ -0,0 +1,22 @@
+package store
+
+import (
+	"gorm.io/gorm"
+)
+
+// private orders restricted to some fillers
+
+type orderV10 struct {
+	ID             uint
+	AllowedFillers string `gorm:"type:text"`
+}
+
+func (orderV10) TableName() string { return "orders" }
+
+func upPrivateOrders(tx *gorm.DB) error {
+	return tx.Migrator().AddColumn(&orderV10{}, "AllowedFillers")
+}
+
+func downPrivateOrders(tx *gorm.DB) error {
+	return dropColumns(tx, "orders", "allowed_fillers")
+}
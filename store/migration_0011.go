@@ -0,0 +1,51 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fill intents of fillers and the lotteries assigning orders to one of them
+
+type fillIntentV11 struct {
+	gorm.Model
+	OrderID        uint   `gorm:"uniqueIndex:idx_fill_intents_order_filler"`
+	Filler         string `gorm:"size:255;uniqueIndex:idx_fill_intents_order_filler"`
+	SendAddress    string
+	ReceiveAddress string
+	Weight         uint64
+	Score          uint64
+	Won            bool
+}
+
+func (fillIntentV11) TableName() string { return "fill_intents" }
+
+type orderV11 struct {
+	ID              uint
+	LotteryEndsAt   *time.Time
+	LotterySeedHash string `gorm:"size:64"`
+	LotterySeed     string `gorm:"size:64"`
+	LotterySecret   string `gorm:"size:64"`
+}
+
+func (orderV11) TableName() string { return "orders" }
+
+func upFillLottery(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&fillIntentV11{}); err != nil {
+		return err
+	}
+	for _, column := range []string{"LotteryEndsAt", "LotterySeedHash", "LotterySeed", "LotterySecret"} {
+		if err := tx.Migrator().AddColumn(&orderV11{}, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func downFillLottery(tx *gorm.DB) error {
+	if err := dropColumns(tx, "orders", "lottery_ends_at", "lottery_seed_hash", "lottery_seed", "lottery_secret"); err != nil {
+		return err
+	}
+	return tx.Migrator().DropTable(&fillIntentV11{})
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// profiles and reputation of fillers
+
+type fillerV12 struct {
+	gorm.Model
+	Address              string `gorm:"size:255;uniqueIndex"`
+	Name                 string
+	Pairs                string `gorm:"type:text"`
+	Contact              string
+	Registered           bool
+	Fills                uint64
+	Executed             uint64
+	FailedSoft           uint64
+	FailedHard           uint64
+	Cancelled            uint64
+	FillRate             float64
+	MedianTimeToInitiate int64
+	Score                uint64
+	ScoredAt             *time.Time
+}
+
+func (fillerV12) TableName() string { return "fillers" }
+
+func upFillers(tx *gorm.DB) error {
+	return tx.AutoMigrate(&fillerV12{})
+}
+
+func downFillers(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&fillerV12{})
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// bonds of fillers and penalties for failing the orders they filled
+
+type fillerV13 struct {
+	ID             uint
+	BondAddress    string
+	Bond           string `gorm:"size:78"`
+	BondVerifiedAt *time.Time
+}
+
+func (fillerV13) TableName() string { return "fillers" }
+
+type penaltyV13 struct {
+	gorm.Model
+	Filler    string `gorm:"size:255;index"`
+	OrderID   uint   `gorm:"uniqueIndex"`
+	Reason    string
+	Status    string `gorm:"size:16;index"`
+	Amount    string `gorm:"size:78"`
+	SettledBy string
+	SettledAt *time.Time
+	Note      string
+}
+
+func (penaltyV13) TableName() string { return "penalties" }
+
+func upFillerBonds(tx *gorm.DB) error {
+	for _, column := range []string{"BondAddress", "Bond", "BondVerifiedAt"} {
+		if err := tx.Migrator().AddColumn(&fillerV13{}, column); err != nil {
+			return err
+		}
+	}
+	return tx.AutoMigrate(&penaltyV13{})
+}
+
+func downFillerBonds(tx *gorm.DB) error {
+	if err := tx.Migrator().DropTable(&penaltyV13{}); err != nil {
+		return err
+	}
+	return dropColumns(tx, "fillers", "bond_address", "bond", "bond_verified_at")
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// inventories fillers have available to fill orders
+
+type inventoryV14 struct {
+	gorm.Model
+	Filler      string `gorm:"size:255;uniqueIndex:idx_inventories_filler_chain_asset"`
+	Chain       string `gorm:"size:64;uniqueIndex:idx_inventories_filler_chain_asset"`
+	Asset       string `gorm:"size:255;uniqueIndex:idx_inventories_filler_chain_asset"`
+	Source      string `gorm:"size:16"`
+	Address     string
+	Amount      string     `gorm:"size:78"`
+	PublishedAt *time.Time `gorm:"index"`
+	Signature   string
+}
+
+func (inventoryV14) TableName() string { return "inventories" }
+
+func upInventories(tx *gorm.DB) error {
+	return tx.AutoMigrate(&inventoryV14{})
+}
+
+func downInventories(tx *gorm.DB) error {
+	return tx.Migrator().DropTable(&inventoryV14{})
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"gorm.io/gorm"
+)
+
+// user limits override the usd limits instead of a daily limit summing raw amounts
+
+type userLimitV15 struct {
+	ID              uint
+	DailyLimit      string
+	DailyUSDLimit   string
+	WeeklyUSDLimit  string
+	MonthlyUSDLimit string
+}
+
+func (userLimitV15) TableName() string { return "user_limits" }
+
+func upUserUSDLimits(tx *gorm.DB) error {
+	for _, column := range []string{"DailyUSDLimit", "WeeklyUSDLimit", "MonthlyUSDLimit"} {
+		if err := tx.Migrator().AddColumn(&userLimitV15{}, column); err != nil {
+			return err
+		}
+	}
+	return dropColumns(tx, "user_limits", "daily_limit")
+}
+
+func downUserUSDLimits(tx *gorm.DB) error {
+	if err := tx.Migrator().AddColumn(&userLimitV15{}, "DailyLimit"); err != nil {
+		return err
+	}
+	return dropColumns(tx, "user_limits", "daily_usd_limit", "weekly_usd_limit", "monthly_usd_limit")
+}
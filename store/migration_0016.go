@@ -0,0 +1,16 @@
+package store
+
+import (
+	"gorm.io/gorm"
+)
+
+// pair volumes counted both swaps of a trade, the order rollups are recomputed
+// from all orders on the next run of the stats job
+
+func upRecountRollups(tx *gorm.DB) error {
+	return tx.Where("name = ?", "orders").Delete(&rollupCursorV3{}).Error
+}
+
+func downRecountRollups(tx *gorm.DB) error {
+	return nil
+}
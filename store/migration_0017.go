@@ -0,0 +1,28 @@
+package store
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// requests in progress are reserved for a lock timeout, after which a retry can
+// take over the reservation of a request that crashed or timed out
+
+type idempotentRequestV17 struct {
+	ID         uint
+	ReservedAt time.Time
+}
+
+func (idempotentRequestV17) TableName() string { return "idempotent_requests" }
+
+func upIdempotencyLease(tx *gorm.DB) error {
+	if err := tx.Migrator().AddColumn(&idempotentRequestV17{}, "ReservedAt"); err != nil {
+		return err
+	}
+	return tx.Exec("UPDATE idempotent_requests SET reserved_at = created_at").Error
+}
+
+func downIdempotencyLease(tx *gorm.DB) error {
+	return dropColumns(tx, "idempotent_requests", "reserved_at")
+}
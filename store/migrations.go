@@ -0,0 +1,205 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Migration is a numbered change of the database schema. Migrations are applied
+// in the order of their versions and reverted in the reverse order, each one in
+// its own transaction together with its record in the schema_migrations table.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// MigrationStatus is a migration and the time it was applied, if it was
+type MigrationStatus struct {
+	Version   uint       `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"appliedAt"`
+}
+
+// schemaMigration is the record of an applied migration
+type schemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// migrations are never edited once released, schema changes are new migrations
+var migrations = []Migration{
+	{Version: 1, Name: "initial_schema", Up: upInitialSchema, Down: downInitialSchema},
+	{Version: 2, Name: "notify_triggers", Up: upNotifyTriggers, Down: downNotifyTriggers},
+	{Version: 3, Name: "rollups", Up: upRollups, Down: downRollups},
+	{Version: 4, Name: "tvl_snapshots", Up: upTVLSnapshots, Down: downTVLSnapshots},
+	{Version: 5, Name: "trades", Up: upTrades, Down: downTrades},
+	{Version: 6, Name: "order_types", Up: upOrderTypes, Down: downOrderTypes},
+	{Version: 7, Name: "auctions", Up: upAuctions, Down: downAuctions},
+	{Version: 8, Name: "partial_fills", Up: upPartialFills, Down: downPartialFills},
+	{Version: 9, Name: "quotes", Up: upQuotes, Down: downQuotes},
+	{Version: 10, Name: "private_orders", Up: upPrivateOrders, Down: downPrivateOrders},
+	{Version: 11, Name: "fill_lottery", Up: upFillLottery, Down: downFillLottery},
+	{Version: 12, Name: "fillers", Up: upFillers, Down: downFillers},
+	{Version: 13, Name: "filler_bonds", Up: upFillerBonds, Down: downFillerBonds},
+	{Version: 14, Name: "inventories", Up: upInventories, Down: downInventories},
+	{Version: 15, Name: "user_usd_limits", Up: upUserUSDLimits, Down: downUserUSDLimits},
+	{Version: 16, Name: "recount_rollups", Up: upRecountRollups, Down: downRecountRollups},
+	{Version: 17, Name: "idempotency_lease", Up: upIdempotencyLease, Down: downIdempotencyLease},
+}
+
+// keys of the advisory locks serializing migrations of concurrent boots
+const (
+	migrationLockID   = 7315400127
+	migrationLockName = "orderbook_schema_migrations"
+	// seconds to wait for the mysql lock
+	migrationLockTimeout = 300
+)
+
+// MigrateUp applies the given number of pending migrations, or all of them if
+// steps is not positive
+func MigrateUp(db *gorm.DB, steps int) error {
+	return withMigrationLock(db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range sortedMigrations() {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+			}); err != nil {
+				return fmt.Errorf("failed to apply migration %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+			// all pending migrations are applied when steps is not positive
+			if steps--; steps == 0 {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// MigrateDown reverts the given number of applied migrations, latest first
+func MigrateDown(db *gorm.DB, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("invalid number of migrations to revert: %d", steps)
+	}
+	return withMigrationLock(db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		all := sortedMigrations()
+		for i := len(all) - 1; i >= 0 && steps > 0; i-- {
+			migration := all[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, migration.Version).Error
+			}); err != nil {
+				return fmt.Errorf("failed to revert migration %04d_%s: %v", migration.Version, migration.Name, err)
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// MigrationStatuses returns all migrations known to this build and when they
+// were applied to the database
+func MigrationStatuses(db *gorm.DB) ([]MigrationStatus, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	statuses := []MigrationStatus{}
+	for _, migration := range sortedMigrations() {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func sortedMigrations() []Migration {
+	sorted := append([]Migration{}, migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return sorted
+}
+
+func appliedMigrations(db *gorm.DB) (map[uint]schemaMigration, error) {
+	records := []schemaMigration{}
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := map[uint]schemaMigration{}
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// runs fn on a single connection holding an advisory lock, so concurrent boots
+// apply each migration once. SQLite has no advisory locks, its databases are not
+// shared between processes.
+func withMigrationLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		// statements on the connection must not share their models
+		conn = conn.Session(&gorm.Session{NewDB: true})
+		switch conn.Dialector.Name() {
+		case dialectPostgres:
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+				return err
+			}
+			defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+		case dialectMySQL:
+			locked := 0
+			if err := conn.Raw("SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).Scan(&locked).Error; err != nil {
+				return err
+			}
+			if locked != 1 {
+				return fmt.Errorf("timed out waiting for the migration lock")
+			}
+			defer conn.Exec("SELECT RELEASE_LOCK(?)", migrationLockName)
+		}
+		if err := conn.AutoMigrate(&schemaMigration{}); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// drops columns in place, the sqlite migrator recreates the table to drop a column
+// which loses its indexes and triggers
+func dropColumns(tx *gorm.DB, table string, columns ...string) error {
+	for _, column := range columns {
+		if err := tx.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{Name: table}, clause.Column{Name: column}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+-- #TRIGGERS
+DROP TRIGGER IF EXISTS swap_events_append_only_trigger ON swap_events;
+DROP TRIGGER IF EXISTS order_events_append_only_trigger ON order_events;
+DROP TRIGGER IF EXISTS notify_atomic_swap_trigger ON atomic_swaps;
+DROP TRIGGER IF EXISTS notify_orders_update_trigger ON orders;
+DROP TRIGGER IF EXISTS notify_orders_insert_trigger ON orders;
+
+-- #FUNCTIONS
+DROP FUNCTION IF EXISTS reject_event_changes();
+DROP FUNCTION IF EXISTS notify_creation_with_order_id();
+DROP FUNCTION IF EXISTS notify_changes_with_swap_id();
+DROP FUNCTION IF EXISTS notify_changes_with_order_id();
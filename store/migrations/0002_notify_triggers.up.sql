@@ -32,6 +32,16 @@ BEGIN
 END;
 $order_created_event$ LANGUAGE plpgsql;
 
+-- #4
+-- Function to reject changes to the event history
+-- called when a row is updated or deleted on order_events and swap_events tables
+CREATE OR REPLACE FUNCTION reject_event_changes()
+RETURNS TRIGGER AS $append_only_event$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$append_only_event$ LANGUAGE plpgsql;
+
 -- #TRIGGERS
 -- #1
 -- trigger for insert events on orders
@@ -58,4 +68,18 @@ CREATE OR REPLACE TRIGGER notify_atomic_swap_trigger
 AFTER UPDATE ON atomic_swaps
 FOR EACH ROW
 WHEN (OLD.status IS DISTINCT FROM NEW.status OR OLD.current_confirmations IS DISTINCT FROM NEW.current_confirmations OR OLD.filled_amount IS DISTINCT FROM NEW.filled_amount)
-EXECUTE FUNCTION notify_changes_with_swap_id();
\ No newline at end of file
+EXECUTE FUNCTION notify_changes_with_swap_id();
+
+-- #4
+-- trigger keeping order_events append-only
+CREATE OR REPLACE TRIGGER order_events_append_only_trigger
+BEFORE UPDATE OR DELETE ON order_events
+FOR EACH ROW
+EXECUTE FUNCTION reject_event_changes();
+
+-- #5
+-- trigger keeping swap_events append-only
+CREATE OR REPLACE TRIGGER swap_events_append_only_trigger
+BEFORE UPDATE OR DELETE ON swap_events
+FOR EACH ROW
+EXECUTE FUNCTION reject_event_changes();
\ No newline at end of file
@@ -0,0 +1,83 @@
+package store_test
+
+import (
+	"sync"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Schema migrations", func() {
+	applied := func(db *gorm.DB) []uint {
+		statuses, err := MigrationStatuses(db)
+		Expect(err).NotTo(HaveOccurred())
+		versions := []uint{}
+		for _, status := range statuses {
+			if status.AppliedAt != nil {
+				versions = append(versions, status.Version)
+			}
+		}
+		return versions
+	}
+
+	It("should apply all migrations when the store is created", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		statuses, err := MigrationStatuses(store.Gorm())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).NotTo(BeEmpty())
+		Expect(statuses[0].Version).To(Equal(uint(1)))
+		for _, status := range statuses {
+			Expect(status.AppliedAt).NotTo(BeNil())
+		}
+
+		// migrating again is a no-op
+		Expect(MigrateUp(store.Gorm(), 0)).To(Succeed())
+		Expect(applied(store.Gorm())).To(HaveLen(len(statuses)))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should revert and reapply migrations step by step", func() {
+		db, err := gorm.Open(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(MigrateUp(db, 1)).To(Succeed())
+		Expect(applied(db)).To(Equal([]uint{1}))
+		Expect(db.Migrator().HasTable(&model.Order{})).To(BeTrue())
+
+		Expect(MigrateUp(db, 0)).To(Succeed())
+		all := applied(db)
+		Expect(len(all)).To(BeNumerically(">", 1))
+
+		Expect(MigrateDown(db, len(all)-1)).To(Succeed())
+		Expect(applied(db)).To(Equal([]uint{1}))
+		Expect(MigrateDown(db, 1)).To(Succeed())
+		Expect(applied(db)).To(BeEmpty())
+		Expect(db.Migrator().HasTable(&model.Order{})).To(BeFalse())
+		Expect(MigrateDown(db, 0)).NotTo(Succeed())
+
+		Expect(MigrateUp(db, 0)).To(Succeed())
+		Expect(applied(db)).To(Equal(all))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should apply each migration once when stores boot concurrently", func() {
+		if isSQLite() {
+			Skip("sqlite has no advisory locks")
+		}
+		wg := new(sync.WaitGroup)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := New(testDialector(), &gorm.Config{})
+				Expect(err).NotTo(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
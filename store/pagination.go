@@ -0,0 +1,94 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+)
+
+// the sort order of filtered orders, orders with equal sort keys are sorted by id
+type orderSort struct {
+	column     string
+	descending bool
+}
+
+func parseOrderSort(sort string) (orderSort, error) {
+	descending := strings.HasPrefix(sort, "-")
+	switch column := strings.TrimPrefix(sort, "-"); column {
+	case "", model.SortByID:
+		return orderSort{column: model.SortByID, descending: descending}, nil
+	case model.SortByCreatedAt, model.SortByPrice:
+		return orderSort{column: column, descending: descending}, nil
+	default:
+		return orderSort{}, fmt.Errorf("invalid sort %s, orders can be sorted by %s, %s or %s", sort, model.SortByID, model.SortByCreatedAt, model.SortByPrice)
+	}
+}
+
+func (sort orderSort) String() string {
+	if sort.descending {
+		return "-" + sort.column
+	}
+	return sort.column
+}
+
+func (sort orderSort) direction() (string, string) {
+	if sort.descending {
+		return "DESC", "<"
+	}
+	return "ASC", ">"
+}
+
+func (sort orderSort) orderBy() string {
+	direction, _ := sort.direction()
+	if sort.column == model.SortByID {
+		return "orders.id " + direction
+	}
+	return fmt.Sprintf("orders.%s %s, orders.id %s", sort.column, direction, direction)
+}
+
+// the condition selecting the orders after the cursor
+func (sort orderSort) after(cursor orderCursor) (string, []interface{}) {
+	_, operator := sort.direction()
+	if sort.column == model.SortByID {
+		return "orders.id " + operator + " ?", []interface{}{cursor.ID}
+	}
+	var value interface{} = cursor.CreatedAt
+	if sort.column == model.SortByPrice {
+		value = cursor.Price
+	}
+	return fmt.Sprintf("orders.%s %s ? OR (orders.%s = ? AND orders.id %s ?)", sort.column, operator, sort.column, operator),
+		[]interface{}{value, value, cursor.ID}
+}
+
+// orderCursor is the position of the last order of a page, cursors are opaque to
+// clients and only valid for the sort order they were created with
+type orderCursor struct {
+	Sort      string        `json:"s"`
+	ID        uint          `json:"i"`
+	CreatedAt time.Time     `json:"c"`
+	Price     model.Decimal `json:"p"`
+}
+
+func encodeOrderCursor(sort orderSort, order model.Order) string {
+	data, _ := json.Marshal(orderCursor{Sort: sort.String(), ID: order.ID, CreatedAt: order.CreatedAt, Price: order.Price})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeOrderCursor(cursor string, sort orderSort) (orderCursor, error) {
+	decoded := orderCursor{}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return decoded, fmt.Errorf("%w %s", model.ErrInvalidCursor, cursor)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return decoded, fmt.Errorf("%w %s", model.ErrInvalidCursor, cursor)
+	}
+	if decoded.Sort != sort.String() {
+		return decoded, fmt.Errorf("%w %s, not a cursor of orders sorted by %s", model.ErrInvalidCursor, cursor, sort)
+	}
+	return decoded, nil
+}
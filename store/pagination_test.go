@@ -0,0 +1,129 @@
+package store_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Filtering orders", func() {
+	maker := testMaker
+	token := model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+
+	orderAt := func(store Store, createdAt time.Time, initiateTxHash string, followerStatus model.SwapStatus) model.Order {
+		initiator := model.AtomicSwap{Chain: model.BitcoinTestnet, Asset: model.Primary, Amount: "100000", InitiateTxHash: initiateTxHash}
+		follower := model.AtomicSwap{Chain: model.EthereumSepolia, Asset: token, Amount: "100000", Status: followerStatus}
+		return insertOrder(store, model.Order{Model: gorm.Model{CreatedAt: createdAt}, Status: model.Created}, initiator, follower)
+	}
+
+	ids := func(orders []model.Order) []uint {
+		ids := make([]uint, len(orders))
+		for i, order := range orders {
+			ids[i] = order.ID
+		}
+		return ids
+	}
+
+	It("should paginate by cursor without skipping or repeating orders inserted meanwhile", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		now := time.Now().UTC().Truncate(time.Second)
+		for i := 0; i < 5; i++ {
+			orderAt(store, now.Add(time.Duration(i)*time.Minute), "", model.NotStarted)
+		}
+
+		seen := []uint{}
+		filter := model.OrderFilter{Maker: maker, Limit: 2}
+		for pages := 0; ; pages++ {
+			Expect(pages).To(BeNumerically("<", 10))
+			orders, next, err := store.FilterOrders(filter)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(orders)).To(BeNumerically("<=", 2))
+			seen = append(seen, ids(orders)...)
+			if pages == 0 {
+				// orders created while paginating are on a later page
+				orderAt(store, now.Add(time.Hour), "", model.NotStarted)
+			}
+			if next == "" {
+				break
+			}
+			filter.Cursor = next
+		}
+		Expect(seen).To(HaveLen(6))
+		for i := 1; i < len(seen); i++ {
+			Expect(seen[i]).To(BeNumerically(">", seen[i-1]))
+		}
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should paginate orders sorted by creation time in descending order", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		now := time.Now().UTC().Truncate(time.Second)
+		older := orderAt(store, now.Add(-time.Hour), "", model.NotStarted)
+		newer := orderAt(store, now, "", model.NotStarted)
+		same := orderAt(store, now, "", model.NotStarted)
+
+		orders, next, err := store.FilterOrders(model.OrderFilter{Sort: "-" + model.SortByCreatedAt, Limit: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(orders)).To(Equal([]uint{same.ID, newer.ID}))
+		Expect(next).NotTo(BeEmpty())
+
+		orders, next, err = store.FilterOrders(model.OrderFilter{Sort: "-" + model.SortByCreatedAt, Limit: 2, Cursor: next})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(orders)).To(Equal([]uint{older.ID}))
+		Expect(next).To(BeEmpty())
+
+		// cursors only belong to the sort order they were returned with
+		_, _, err = store.FilterOrders(model.OrderFilter{Sort: model.SortByID, Limit: 2, Cursor: next + "x"})
+		Expect(errors.Is(err, model.ErrInvalidCursor)).To(BeTrue())
+		_, first, err := store.FilterOrders(model.OrderFilter{Limit: 1})
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = store.FilterOrders(model.OrderFilter{Sort: model.SortByCreatedAt, Limit: 1, Cursor: first})
+		Expect(errors.Is(err, model.ErrInvalidCursor)).To(BeTrue())
+		_, _, err = store.FilterOrders(model.OrderFilter{Sort: "maker"})
+		Expect(err).To(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should filter orders by their swaps and creation time", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		now := time.Now().UTC().Truncate(time.Second)
+		old := orderAt(store, now.Add(-48*time.Hour), "", model.NotStarted)
+		initiated := orderAt(store, now, "0xABCDEF", model.Initiated)
+
+		orders, _, err := store.FilterOrders(model.OrderFilter{CreatedAfter: now.Add(-time.Hour)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(orders)).To(Equal([]uint{initiated.ID}))
+		orders, _, err = store.FilterOrders(model.OrderFilter{CreatedBefore: now.Add(-time.Hour)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(orders)).To(Equal([]uint{old.ID}))
+
+		orders, _, err = store.FilterOrders(model.OrderFilter{TxHash: "0xabcdef", Verbose: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(orders)).To(Equal([]uint{initiated.ID}))
+		Expect(orders[0].InitiatorAtomicSwap.InitiateTxHash).To(Equal("0xABCDEF"))
+
+		initiatedStatus, notStartedStatus := model.Initiated, model.NotStarted
+		orders, _, err = store.FilterOrders(model.OrderFilter{SwapStatus: &initiatedStatus})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(orders)).To(Equal([]uint{initiated.ID}))
+		orders, _, err = store.FilterOrders(model.OrderFilter{SwapStatus: &notStartedStatus})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(orders)).To(Equal([]uint{old.ID, initiated.ID}))
+
+		orders, _, err = store.FilterOrders(model.OrderFilter{Chain: model.EthereumSepolia, Asset: model.NewSecondary("0x130ff59b75a415d0bccc2e996acaf27ce70fd5ef")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(HaveLen(2))
+		orders, _, err = store.FilterOrders(model.OrderFilter{Chain: model.Ethereum})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(BeEmpty())
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
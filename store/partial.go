@@ -0,0 +1,199 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// fill a part of a partially fillable order with a child order and return the id
+// of the child order
+func (s *store) FillOrderPart(orderID uint, filler, sendAddress, receiveAddress string, amount *big.Int, config model.Network) (uint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	childID := uint(0)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if err := checkFillable(order, filler, now); err != nil {
+			return err
+		}
+		if !order.Partial {
+			return fmt.Errorf("order %d cannot be filled partially", orderID)
+		}
+		childID, err = s.fillPart(tx, order, filler, sendAddress, receiveAddress, amount, config, now)
+		return err
+	})
+	return childID, err
+}
+
+// create a child order filling the given amount of the locked order with the next
+// secret hash of the order, the last part has to fill the remaining amount
+func (s *store) fillPart(tx *gorm.DB, order *model.Order, filler, sendAddress, receiveAddress string, amount *big.Int, config model.Network, now time.Time) (uint, error) {
+	remaining, ok := new(big.Int).SetString(order.RemainingAmount, 10)
+	if !ok {
+		return 0, fmt.Errorf("constraint violation: corrupted remaining amount: %v", order.RemainingAmount)
+	}
+	filled, ok := new(big.Int).SetString(order.FilledAmount, 10)
+	if !ok {
+		return 0, fmt.Errorf("constraint violation: corrupted filled amount: %v", order.FilledAmount)
+	}
+	if amount == nil || amount.Sign() <= 0 || amount.Cmp(remaining) > 0 {
+		return 0, fmt.Errorf("invalid fill amount %v, the order has %s remaining", amount, remaining)
+	}
+	if len(order.PartSecretHashes) == 0 {
+		return 0, fmt.Errorf("constraint violation: no secret hash left for order %d", order.ID)
+	}
+	if len(order.PartSecretHashes) == 1 && amount.Cmp(remaining) != 0 {
+		return 0, fmt.Errorf("the last part of the order has to fill the remaining amount %s", remaining)
+	}
+	secretHash := order.PartSecretHashes[0]
+
+	orderInitiatorSwap := model.AtomicSwap{}
+	if err := tx.First(&orderInitiatorSwap, order.InitiatorAtomicSwapID).Error; err != nil {
+		return 0, err
+	}
+	orderFollowerSwap := model.AtomicSwap{}
+	if err := tx.First(&orderFollowerSwap, order.FollowerAtomicSwapID).Error; err != nil {
+		return 0, err
+	}
+	sendAmount, ok := new(big.Int).SetString(orderInitiatorSwap.Amount, 10)
+	if !ok {
+		return 0, fmt.Errorf("constraint violation: corrupted send amount: %v", orderInitiatorSwap.Amount)
+	}
+	receiveAmount, ok := new(big.Int).SetString(orderFollowerSwap.Amount, 10)
+	if !ok {
+		return 0, fmt.Errorf("constraint violation: corrupted receive amount: %v", orderFollowerSwap.Amount)
+	}
+	partReceiveAmount, err := model.PartReceiveAmount(sendAmount, receiveAmount, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	initiatorAtomicSwap := model.AtomicSwap{
+		InitiatorAddress: orderInitiatorSwap.InitiatorAddress,
+		Chain:            orderInitiatorSwap.Chain,
+		Asset:            orderInitiatorSwap.Asset,
+		Amount:           amount.String(),
+		PriceByOracle:    orderInitiatorSwap.PriceByOracle,
+	}
+	followerAtomicSwap := model.AtomicSwap{
+		RedeemerAddress: orderFollowerSwap.RedeemerAddress,
+		Chain:           orderFollowerSwap.Chain,
+		Asset:           orderFollowerSwap.Asset,
+		Amount:          partReceiveAmount.String(),
+		PriceByOracle:   orderFollowerSwap.PriceByOracle,
+	}
+	if err := s.fillSwaps(order.OrderPair, secretHash, sendAddress, receiveAddress, &initiatorAtomicSwap, &followerAtomicSwap, config); err != nil {
+		return 0, err
+	}
+	for _, swap := range []*model.AtomicSwap{&initiatorAtomicSwap, &followerAtomicSwap} {
+		if err := tx.Create(swap).Error; err != nil {
+			return 0, err
+		}
+		if err := appendSwapEvent(tx, model.NotStarted, swap, "filler:"+filler); err != nil {
+			return 0, err
+		}
+	}
+
+	child := model.Order{
+		Maker:                 order.Maker,
+		Taker:                 filler,
+		OrderPair:             order.OrderPair,
+		InitiatorAtomicSwapID: initiatorAtomicSwap.ID,
+		FollowerAtomicSwapID:  followerAtomicSwap.ID,
+		Price:                 order.Price,
+		SecretHash:            secretHash,
+		Status:                model.Filled,
+		UserBtcWalletAddress:  order.UserBtcWalletAddress,
+		Type:                  order.Type,
+		ParentOrderID:         &order.ID,
+		FilledAt:              &now,
+	}
+	if err := tx.Create(&child).Error; err != nil {
+		return 0, err
+	}
+	if err := appendOrderEvent(tx, model.Unknown, &child, "filler:"+filler); err != nil {
+		return 0, err
+	}
+
+	// the amounts are checked by a conditional update, so only one of concurrent
+	// fills of the same remaining amount succeeds
+	prevRemaining := order.RemainingAmount
+	remaining.Sub(remaining, amount)
+	order.FilledAmount = filled.Add(filled, amount).String()
+	order.RemainingAmount = remaining.String()
+	order.PartSecretHashes = order.PartSecretHashes[1:]
+	if remaining.Sign() == 0 {
+		order.Status = model.Filled
+		order.FilledAt = &now
+	}
+	res := tx.Model(&model.Order{}).
+		Where("id = ? AND status = ? AND remaining_amount = ?", order.ID, model.Created, prevRemaining).
+		Updates(map[string]interface{}{
+			"status":             order.Status,
+			"filled_amount":      order.FilledAmount,
+			"remaining_amount":   order.RemainingAmount,
+			"part_secret_hashes": order.PartSecretHashes,
+			"filled_at":          order.FilledAt,
+		})
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return 0, fmt.Errorf("order %d was filled concurrently", order.ID)
+	}
+	if order.Status != model.Created {
+		if err := appendOrderEvent(tx, model.Created, order, "filler:"+filler); err != nil {
+			return 0, err
+		}
+	}
+	return child.ID, nil
+}
+
+// validate the secret hashes of the parts of a new order, which have to be
+// distinct from each other and from the secret hashes of all orders
+func (s *store) checkPartSecretHashes(secretHash string, partSecretHashes []string) ([]string, error) {
+	if len(partSecretHashes) == 0 {
+		return nil, nil
+	}
+	seen := map[string]bool{secretHash: true}
+	hashes := make([]string, len(partSecretHashes))
+	for i, hash := range partSecretHashes {
+		hash, err := CheckHash(hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid part secret hash: %v", err)
+		}
+		if seen[hash] {
+			return nil, fmt.Errorf("secret hash %s is used more than once", hash)
+		}
+		seen[hash] = true
+		hashes[i] = hash
+	}
+	var existing int64
+	if tx := s.db.Unscoped().Model(&model.Order{}).Where("secret_hash IN ?", hashes).Count(&existing); tx.Error != nil {
+		return nil, tx.Error
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("an order with one of the part secret hashes already exists")
+	}
+	return hashes, nil
+}
+
+// fills the child orders, with their atomic swaps, of a partially fillable order
+func (s *store) fillChildren(order *model.Order) error {
+	if !order.Partial {
+		return nil
+	}
+	order.Children = []model.Order{}
+	return s.db.Where("parent_order_id = ?", order.ID).
+		Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap").
+		Order("id ASC").Find(&order.Children).Error
+}
@@ -0,0 +1,107 @@
+package store_test
+
+import (
+	"math/big"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Partial fills", func() {
+	maker := testMaker
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	otherFiller := "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0"
+
+	// terms of an order which can be filled in the given number of parts
+	partTerms := func(parts int) model.OrderTerms {
+		terms := model.OrderTerms{}
+		for i := 0; i < parts; i++ {
+			terms.PartSecretHashes = append(terms.PartSecretHashes, newSecretHash())
+		}
+		return terms
+	}
+
+	It("should fill an order in parts by child orders", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := createOrder(store, testOrder{Terms: partTerms(2)})
+		Expect(err).NotTo(HaveOccurred())
+
+		childID, err := store.FillOrderPart(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", big.NewInt(30000000), config.Network)
+		Expect(err).NotTo(HaveOccurred())
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Created))
+		Expect(order.FilledAmount).To(Equal("30000000"))
+		Expect(order.RemainingAmount).To(Equal("70000000"))
+		Expect(order.Children).To(HaveLen(1))
+		child := order.Children[0]
+		Expect(child.ID).To(Equal(childID))
+		Expect(*child.ParentOrderID).To(Equal(id))
+		Expect(child.Status).To(Equal(model.Filled))
+		Expect(child.Taker).To(Equal(filler))
+		Expect(child.SecretHash).NotTo(Equal(order.SecretHash))
+		Expect(child.InitiatorAtomicSwap.Amount).To(Equal("30000000"))
+		Expect(child.FollowerAtomicSwap.Amount).To(Equal("33000000"))
+		Expect(child.InitiatorAtomicSwap.OnChainIdentifier).NotTo(BeEmpty())
+
+		// the last part has to fill the remaining amount
+		_, err = store.FillOrderPart(id, otherFiller, otherFiller, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", big.NewInt(30000000), config.Network)
+		Expect(err).To(MatchError(ContainSubstring("remaining amount")))
+		_, err = store.FillOrderPart(id, otherFiller, otherFiller, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", big.NewInt(80000000), config.Network)
+		Expect(err).To(HaveOccurred())
+		Expect(store.FillOrder(id, otherFiller, otherFiller, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(Succeed())
+
+		order, err = store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Filled))
+		Expect(order.FilledAmount).To(Equal("100000000"))
+		Expect(order.RemainingAmount).To(Equal("0"))
+		Expect(order.Children).To(HaveLen(2))
+		Expect(order.Children[1].Taker).To(Equal(otherFiller))
+		Expect(order.Children[1].FollowerAtomicSwap.Amount).To(Equal("77000000"))
+
+		active, err := store.GetActiveOrders()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(active).To(HaveLen(3))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should close a partially filled order when it is cancelled", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := createOrder(store, testOrder{Terms: partTerms(3)})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = store.FillOrderPart(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", big.NewInt(50000000), config.Network)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.CancelOrder(maker, id)).To(Succeed())
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Filled))
+		Expect(order.RemainingAmount).To(Equal("50000000"))
+		_, err = store.FillOrderPart(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", big.NewInt(50000000), config.Network)
+		Expect(err).To(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should validate the parts of an order", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = createOrder(store, testOrder{Terms: partTerms(model.MaxOrderParts + 1)})
+		Expect(err).To(HaveOccurred())
+
+		hash := newSecretHash()
+		_, err = createOrder(store, testOrder{Terms: model.OrderTerms{PartSecretHashes: []string{hash, hash}}})
+		Expect(err).To(MatchError(ContainSubstring("more than once")))
+
+		id, err := createOrder(store, testOrder{Terms: partTerms(0)})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = store.FillOrderPart(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", big.NewInt(50000000), config.Network)
+		Expect(err).To(MatchError(ContainSubstring("cannot be filled partially")))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,84 @@
+package store_test
+
+import (
+	"strings"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Private orders", func() {
+	maker := testMaker
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	otherFiller := "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0"
+	pair := testPair
+
+	It("should only be filled by an allowed filler", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := createOrder(store, testOrder{Terms: model.OrderTerms{AllowedFillers: []string{"0x" + strings.ToUpper(filler[2:]), filler}}})
+		Expect(err).NotTo(HaveOccurred())
+		publicID, err := createOrder(store, testOrder{})
+		Expect(err).NotTo(HaveOccurred())
+
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.AllowedFillers).To(Equal(model.StringArray{filler}))
+		Expect(order.IsPrivate()).To(BeTrue())
+
+		orders, _, err := store.FilterOrders(model.OrderFilter{OrderPair: pair, Status: model.Created, Unassigned: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(HaveLen(1))
+		Expect(orders[0].ID).To(Equal(publicID))
+
+		orders, err = store.GetOrdersByAddress(filler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(HaveLen(1))
+		orders, err = store.GetOrdersByAddress(otherFiller)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(BeEmpty())
+
+		Expect(store.FillOrder(id, otherFiller, otherFiller, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(MatchError(ContainSubstring("private")))
+		Expect(store.FillOrder(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(Succeed())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should hide private orders from the public listing", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := createOrder(store, testOrder{Terms: model.OrderTerms{AllowedFillers: []string{filler}}})
+		Expect(err).NotTo(HaveOccurred())
+		publicID, err := createOrder(store, testOrder{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.FillOrder(publicID, otherFiller, otherFiller, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(Succeed())
+
+		for viewer, ids := range map[string][]uint{"": {publicID}, otherFiller: {publicID}, filler: {id, publicID}, maker: {id, publicID}} {
+			orders, _, err := store.FilterOrders(model.OrderFilter{OrderPair: pair, HidePrivate: true, Viewer: viewer, Sort: model.SortByID})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orders).To(HaveLen(len(ids)), viewer)
+			for i, order := range orders {
+				Expect(order.ID).To(Equal(ids[i]))
+				Expect(order.VisibleTo(viewer)).To(BeTrue())
+			}
+		}
+
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.VisibleTo("")).To(BeFalse())
+		Expect(order.VisibleTo(otherFiller)).To(BeFalse())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should validate the allowed fillers", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = createOrder(store, testOrder{Terms: model.OrderTerms{AllowedFillers: []string{"0x1234"}}})
+		Expect(err).To(HaveOccurred())
+		_, err = createOrder(store, testOrder{Terms: model.OrderTerms{AllowedFillers: []string{maker}}})
+		Expect(err).To(MatchError(ContainSubstring("maker")))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// create a request for fillers to quote the send amount on the order pair until
+// the window closes
+func (s *store) CreateQuoteRequest(requester, orderPair string, sendAmount *big.Int, window time.Duration, config model.Config) (*model.QuoteRequest, error) {
+	if err := CheckAddress(model.Ethereum, requester); err != nil {
+		return nil, err
+	}
+	sendChain, receiveChain, sendAsset, receiveAsset, err := model.ParseOrderPair(orderPair)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := config.Network[sendChain].Assets[sendAsset]; !ok {
+		return nil, fmt.Errorf("unsupported asset %s on %s", sendAsset, sendChain)
+	}
+	if _, ok := config.Network[receiveChain].Assets[receiveAsset]; !ok {
+		return nil, fmt.Errorf("unsupported asset %s on %s", receiveAsset, receiveChain)
+	}
+	if sendAmount == nil || sendAmount.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid send amount")
+	}
+	request := &model.QuoteRequest{
+		Requester:  requester,
+		OrderPair:  orderPair,
+		SendAmount: sendAmount.String(),
+		ExpiresAt:  time.Now().UTC().Add(window),
+		Status:     model.QuoteRequestOpen,
+	}
+	if err := s.db.Create(request).Error; err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// record the signed quote of a filler for an open request for quote, a filler can
+// improve its quote by quoting again until the request expires
+func (s *store) CreateQuote(quote *model.Quote) error {
+	request := model.QuoteRequest{}
+	if err := s.db.First(&request, quote.QuoteRequestID).Error; err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if request.Status != model.QuoteRequestOpen || !now.Before(request.ExpiresAt) {
+		return fmt.Errorf("quote request %d is closed", request.ID)
+	}
+	receiveAmount, ok := new(big.Int).SetString(quote.ReceiveAmount, 10)
+	if !ok || receiveAmount.Sign() <= 0 {
+		return fmt.Errorf("invalid receive amount: %s", quote.ReceiveAmount)
+	}
+	if !quote.ValidUntil.After(now) || quote.ValidUntil.Sub(now) > model.MaxQuoteValidity {
+		return fmt.Errorf("quote has to be valid for at most %s", model.MaxQuoteValidity)
+	}
+	quote.ID = 0
+	quote.Accepted = false
+	return s.db.Create(quote).Error
+}
+
+// get the request for quote with its quotes, the highest receive amount first
+func (s *store) GetQuoteRequest(id uint) (*model.QuoteRequest, error) {
+	request := &model.QuoteRequest{}
+	if err := s.db.First(request, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	if err := s.db.Where("quote_request_id = ?", id).
+		Order(castAmount(s.db, "receive_amount") + " DESC, id ASC").
+		Find(&request.Quotes).Error; err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// get the requests for quotes on the order pair which can still be quoted
+func (s *store) OpenQuoteRequests(orderPair string) ([]model.QuoteRequest, error) {
+	requests := []model.QuoteRequest{}
+	if err := s.db.Where("order_pair = ? AND status = ? AND expires_at > ?", orderPair, model.QuoteRequestOpen, time.Now().UTC()).
+		Order("id ASC").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// accept a quote of a request for quote of the requester, which creates an order
+// for the quoted amounts which only the filler who quoted can fill
+func (s *store) AcceptQuote(requester string, requestID, quoteID uint, sendAddress, receiveAddress, secretHash, userBtcWalletAddress string, config model.Config) (uint, error) {
+	quote := model.Quote{}
+	if err := s.db.First(&quote, quoteID).Error; err != nil {
+		return 0, err
+	}
+	if quote.QuoteRequestID != requestID {
+		return 0, fmt.Errorf("quote %d does not quote request %d", quoteID, requestID)
+	}
+	request := model.QuoteRequest{}
+	if err := s.db.First(&request, requestID).Error; err != nil {
+		return 0, err
+	}
+	if request.Requester != requester {
+		return 0, fmt.Errorf("quote request %d can only be accepted by its requester", requestID)
+	}
+	if request.Status != model.QuoteRequestOpen {
+		return 0, fmt.Errorf("quote request %d is already %s", requestID, request.Status)
+	}
+	if !time.Now().Before(quote.ValidUntil) {
+		return 0, fmt.Errorf("quote expired at %s", quote.ValidUntil.UTC().Format(time.RFC3339))
+	}
+	sendAmount, ok := new(big.Int).SetString(request.SendAmount, 10)
+	if !ok {
+		return 0, fmt.Errorf("constraint violation: corrupted send amount: %v", request.SendAmount)
+	}
+	receiveAmount, ok := new(big.Int).SetString(quote.ReceiveAmount, 10)
+	if !ok {
+		return 0, fmt.Errorf("constraint violation: corrupted receive amount: %v", quote.ReceiveAmount)
+	}
+	return s.CreateOrder(requester, sendAddress, receiveAddress, request.OrderPair, secretHash, userBtcWalletAddress, sendAmount, receiveAmount, big.NewInt(0), big.NewInt(0), false, model.OrderTerms{Quote: &quote}, config)
+}
+
+// mark the quote and its request as accepted by the order, should be called within
+// the transaction creating the order. The status of the request is checked by a
+// conditional update, so only one of concurrent acceptances succeeds.
+func acceptQuote(tx *gorm.DB, quote *model.Quote, orderID uint) error {
+	res := tx.Model(&model.QuoteRequest{}).
+		Where("id = ? AND status = ?", quote.QuoteRequestID, model.QuoteRequestOpen).
+		Updates(map[string]interface{}{
+			"status":            model.QuoteRequestAccepted,
+			"accepted_quote_id": quote.ID,
+			"order_id":          orderID,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("quote request %d is no longer open", quote.QuoteRequestID)
+	}
+	return tx.Model(&model.Quote{}).Where("id = ?", quote.ID).Update("accepted", true).Error
+}
@@ -0,0 +1,83 @@
+package store_test
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Quotes", func() {
+	requester := "0x17100301bb2ff58ae6b5ca5b8f9ec6f872e0f2da"
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	otherFiller := "0x8e4a35c3b4b2d3ac5e1d03cb3fb68bd1c2b4e1f0"
+	pair := testPair
+
+	It("should create an order assigned to the filler of the accepted quote", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		request, err := store.CreateQuoteRequest(requester, pair, big.NewInt(100000000), time.Minute, config)
+		Expect(err).NotTo(HaveOccurred())
+		open, err := store.OpenQuoteRequests(pair)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(HaveLen(1))
+
+		validUntil := time.Now().Add(time.Minute)
+		low := model.Quote{QuoteRequestID: request.ID, Filler: otherFiller, ReceiveAmount: "98000000", ValidUntil: validUntil}
+		Expect(store.CreateQuote(&low)).To(Succeed())
+		high := model.Quote{QuoteRequestID: request.ID, Filler: filler, ReceiveAmount: "99000000", ValidUntil: validUntil}
+		Expect(store.CreateQuote(&high)).To(Succeed())
+		Expect(store.CreateQuote(&model.Quote{QuoteRequestID: request.ID, Filler: filler, ReceiveAmount: "99000000", ValidUntil: time.Now().Add(time.Hour)})).NotTo(Succeed())
+
+		request, err = store.GetQuoteRequest(request.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(request.Quotes).To(HaveLen(2))
+		Expect(request.Quotes[0].ID).To(Equal(high.ID))
+
+		_, err = store.AcceptQuote(otherFiller, request.ID, high.ID, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", requester, newSecretHash(), "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		Expect(err).To(HaveOccurred())
+		id, err := store.AcceptQuote(requester, request.ID, high.ID, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", requester, newSecretHash(), "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = store.AcceptQuote(requester, request.ID, low.ID, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", requester, newSecretHash(), "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		Expect(err).To(HaveOccurred())
+
+		order, err := store.GetOrder(id)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Created))
+		Expect(order.Taker).To(Equal(filler))
+		Expect(*order.QuoteID).To(Equal(high.ID))
+		Expect(order.InitiatorAtomicSwap.Amount).To(Equal("100000000"))
+		Expect(order.FollowerAtomicSwap.Amount).To(Equal("99000000"))
+
+		request, err = store.GetQuoteRequest(request.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(request.Status).To(Equal(model.QuoteRequestAccepted))
+		Expect(*request.OrderID).To(Equal(id))
+		Expect(request.Quotes[0].Accepted).To(BeTrue())
+
+		// only the filler who quoted can fill the order and it is not an open order
+		orders, _, err := store.FilterOrders(model.OrderFilter{OrderPair: pair, Status: model.Created, Unassigned: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orders).To(BeEmpty())
+		Expect(store.FillOrder(id, otherFiller, otherFiller, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(MatchError(ContainSubstring("can only be filled by")))
+		Expect(store.FillOrder(id, filler, filler, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)).To(Succeed())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should not accept quotes after the window closes", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		request, err := store.CreateQuoteRequest(requester, pair, big.NewInt(100000000), time.Minute, config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Gorm().Model(&model.QuoteRequest{}).Where("id = ?", request.ID).Update("expires_at", time.Now().Add(-time.Second)).Error).NotTo(HaveOccurred())
+		Expect(store.CreateQuote(&model.Quote{QuoteRequestID: request.ID, Filler: filler, ReceiveAmount: "99000000", ValidUntil: time.Now().Add(time.Minute)})).To(MatchError(ContainSubstring("closed")))
+		open, err := store.OpenQuoteRequests(pair)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(open).To(BeEmpty())
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
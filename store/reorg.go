@@ -0,0 +1,121 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// number of processed blocks retained per htlc contract for reorg detection
+const processedBlocksRetained = 128
+
+// record the hash of the last block of a range processed by the evm watcher
+func (s *store) RecordBlockHash(chain model.Chain, contract string, blockNumber uint64, blockHash string) error {
+	contract = strings.ToLower(contract)
+	block := model.ProcessedBlock{
+		Chain:       chain,
+		Contract:    contract,
+		BlockNumber: blockNumber,
+		BlockHash:   blockHash,
+	}
+	if tx := s.db.Save(&block); tx.Error != nil {
+		return tx.Error
+	}
+
+	// prune the blocks which are too old to be reorged out
+	blocks := []model.ProcessedBlock{}
+	if tx := s.db.Where("chain = ? AND contract = ?", chain, contract).Order("block_number DESC").Offset(processedBlocksRetained).Limit(1).Find(&blocks); tx.Error != nil {
+		return tx.Error
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	if tx := s.db.Where("chain = ? AND contract = ? AND block_number <= ?", chain, contract, blocks[0].BlockNumber).Delete(&model.ProcessedBlock{}); tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// get the most recently processed blocks of the given htlc contract, newest first
+func (s *store) GetProcessedBlocks(chain model.Chain, contract string, limit int) ([]model.ProcessedBlock, error) {
+	blocks := []model.ProcessedBlock{}
+	if tx := s.db.Where("chain = ? AND contract = ?", chain, strings.ToLower(contract)).Order("block_number DESC").Limit(limit).Find(&blocks); tx.Error != nil {
+		return nil, tx.Error
+	}
+	return blocks, nil
+}
+
+// revert the swap updates made by the blocks after the given block, the affected
+// orders are moved back to filled so that the watcher processes them again
+func (s *store) RollbackBlocks(chain model.Chain, contract string, blockNumber uint64) error {
+	return s.rollbackBlocks(chain, contract, blockNumber, blockNumber)
+}
+
+// revert the swap updates of an L2 made after the given L1 block, the swaps of an
+// L2 record the L1 blocks of their updates, and rewind its block cursor to the given L2 block
+func (s *store) RollbackL2Blocks(chain model.Chain, contract string, blockNumber, l1BlockNumber uint64) error {
+	return s.rollbackBlocks(chain, contract, blockNumber, l1BlockNumber)
+}
+
+func (s *store) rollbackBlocks(chain model.Chain, contract string, blockNumber, swapBlockNumber uint64) error {
+	contract = strings.ToLower(contract)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		swaps := []model.AtomicSwap{}
+		if err := tx.Where("chain = ? AND LOWER(asset) = ? AND (initiate_block_number > ? OR redeem_block_number > ? OR refund_block_number > ?)", chain, contract, swapBlockNumber, swapBlockNumber, swapBlockNumber).Find(&swaps).Error; err != nil {
+			return err
+		}
+		actor := "reorg:" + string(chain)
+		for i := range swaps {
+			rollbackSwap(&swaps[i], swapBlockNumber)
+			if err := saveSwap(tx, &swaps[i], actor); err != nil {
+				return err
+			}
+			orders := []model.Order{}
+			if err := tx.Where("(initiator_atomic_swap_id = ? OR follower_atomic_swap_id = ?) AND status IN ?", swaps[i].ID, swaps[i].ID, []model.Status{model.Executed, model.FailedSoft, model.FailedHard}).Find(&orders).Error; err != nil {
+				return err
+			}
+			for j := range orders {
+				orders[j].Status = model.Filled
+				if err := saveOrder(tx, &orders[j], actor); err != nil {
+					return err
+				}
+			}
+		}
+		if err := tx.Where("chain = ? AND contract = ? AND block_number > ?", chain, contract, blockNumber).Delete(&model.ProcessedBlock{}).Error; err != nil {
+			return err
+		}
+		return (&store{mu: s.mu, db: tx, prices: s.prices}).UpdateBlockCursor(chain, contract, blockNumber)
+	})
+}
+
+// reverts the on-chain updates of a swap which happened after the given block,
+// confirmations of a surviving initiate are recounted by the watcher
+func rollbackSwap(swap *model.AtomicSwap, blockNumber uint64) {
+	if swap.RefundBlockNumber > blockNumber {
+		swap.RefundTxHash = ""
+		swap.RefundBlockNumber = 0
+	}
+	if swap.RedeemBlockNumber > blockNumber {
+		swap.RedeemTxHash = ""
+		swap.RedeemBlockNumber = 0
+		swap.Secret = ""
+	}
+	if swap.InitiateBlockNumber > blockNumber {
+		swap.InitiateTxHash = ""
+		swap.InitiateBlockNumber = 0
+	}
+
+	switch {
+	case swap.InitiateTxHash == "":
+		swap.Status = model.NotStarted
+		swap.CurrentConfirmations = 0
+	case swap.RedeemTxHash != "":
+		swap.Status = model.Redeemed
+	case swap.RefundTxHash != "":
+		swap.Status = model.Refunded
+	default:
+		swap.Status = model.Detected
+		swap.CurrentConfirmations = 0
+	}
+}
@@ -0,0 +1,120 @@
+package store_test
+
+import (
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Reorgs", func() {
+	contract := "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"
+
+	It("should return the processed blocks newest first", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.RecordBlockHash(model.EthereumSepolia, contract, 100, "0x100")).To(Succeed())
+		Expect(store.RecordBlockHash(model.EthereumSepolia, contract, 110, "0x110")).To(Succeed())
+		Expect(store.RecordBlockHash(model.EthereumSepolia, contract, 120, "0x120")).To(Succeed())
+
+		blocks, err := store.GetProcessedBlocks(model.EthereumSepolia, contract, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blocks).To(HaveLen(2))
+		Expect(blocks[0].BlockNumber).To(Equal(uint64(120)))
+		Expect(blocks[0].BlockHash).To(Equal("0x120"))
+		Expect(blocks[1].BlockNumber).To(Equal(uint64(110)))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should revert the swaps updated after the fork block", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		initiator := model.AtomicSwap{
+			Status:              model.Redeemed,
+			Chain:               model.EthereumSepolia,
+			Asset:               model.Asset(contract),
+			Secret:              "secret",
+			InitiateTxHash:      "0xinit",
+			InitiateBlockNumber: 100,
+			RedeemTxHash:        "0xredeem",
+			RedeemBlockNumber:   105,
+		}
+		follower := model.AtomicSwap{
+			Status:              model.Initiated,
+			Chain:               model.EthereumSepolia,
+			Asset:               model.Asset(contract),
+			InitiateTxHash:      "0xinit2",
+			InitiateBlockNumber: 110,
+		}
+		Expect(store.Gorm().Create(&initiator).Error).NotTo(HaveOccurred())
+		Expect(store.Gorm().Create(&follower).Error).NotTo(HaveOccurred())
+		order := model.Order{
+			SecretHash:            "secretHash",
+			Status:                model.Executed,
+			InitiatorAtomicSwapID: initiator.ID,
+			FollowerAtomicSwapID:  follower.ID,
+		}
+		Expect(store.Gorm().Create(&order).Error).NotTo(HaveOccurred())
+		Expect(store.RecordBlockHash(model.EthereumSepolia, contract, 100, "0x100")).To(Succeed())
+		Expect(store.RecordBlockHash(model.EthereumSepolia, contract, 110, "0x110")).To(Succeed())
+
+		Expect(store.RollbackBlocks(model.EthereumSepolia, contract, 100)).To(Succeed())
+
+		Expect(store.Gorm().First(&initiator, initiator.ID).Error).NotTo(HaveOccurred())
+		Expect(initiator.Status).To(Equal(model.Detected))
+		Expect(initiator.InitiateTxHash).To(Equal("0xinit"))
+		Expect(initiator.RedeemTxHash).To(BeEmpty())
+		Expect(initiator.RedeemBlockNumber).To(BeZero())
+		Expect(initiator.Secret).To(BeEmpty())
+
+		Expect(store.Gorm().First(&follower, follower.ID).Error).NotTo(HaveOccurred())
+		Expect(follower.Status).To(Equal(model.NotStarted))
+		Expect(follower.InitiateTxHash).To(BeEmpty())
+
+		Expect(store.Gorm().First(&order, order.ID).Error).NotTo(HaveOccurred())
+		Expect(order.Status).To(Equal(model.Filled))
+
+		blocks, err := store.GetProcessedBlocks(model.EthereumSepolia, contract, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(blocks).To(HaveLen(1))
+		Expect(blocks[0].BlockNumber).To(Equal(uint64(100)))
+
+		block, err := store.GetBlockCursor(model.EthereumSepolia, contract)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block).To(Equal(uint64(100)))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should revert the swaps of an L2 by their L1 blocks", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		swap := model.AtomicSwap{
+			Status:              model.Refunded,
+			Chain:               model.EthereumArbitrum,
+			Asset:               model.Asset(contract),
+			InitiateTxHash:      "0xinit",
+			InitiateBlockNumber: 20,
+			RefundTxHash:        "0xrefund",
+			RefundBlockNumber:   30,
+		}
+		Expect(store.Gorm().Create(&swap).Error).NotTo(HaveOccurred())
+		Expect(store.RecordBlockHash(model.EthereumArbitrum, contract, 100, "0x100")).To(Succeed())
+		Expect(store.RecordBlockHash(model.EthereumArbitrum, contract, 120, "0x120")).To(Succeed())
+
+		Expect(store.RollbackL2Blocks(model.EthereumArbitrum, contract, 100, 25)).To(Succeed())
+
+		Expect(store.Gorm().First(&swap, swap.ID).Error).NotTo(HaveOccurred())
+		Expect(swap.Status).To(Equal(model.Detected))
+		Expect(swap.InitiateTxHash).To(Equal("0xinit"))
+		Expect(swap.RefundTxHash).To(BeEmpty())
+
+		block, err := store.GetBlockCursor(model.EthereumArbitrum, contract)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(block).To(Equal(uint64(100)))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
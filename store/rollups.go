@@ -0,0 +1,257 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+const orderRollupCursor = "orders"
+
+// RollupOrders recomputes the hourly and daily buckets of the orders updated since
+// the last rollup and records the trades of the executed ones. Buckets are
+// recomputed from all of their orders, so rolling up the same orders again is harmless.
+func (s *store) RollupOrders(config model.Network) error {
+	cursor := model.RollupCursor{}
+	if err := s.db.Where("name = ?", orderRollupCursor).FirstOrInit(&cursor, model.RollupCursor{Name: orderRollupCursor}).Error; err != nil {
+		return err
+	}
+	// orders updated while rolling up are rolled up again on the next run
+	now := time.Now().UTC()
+
+	createdAt := []time.Time{}
+	if err := s.db.Unscoped().Model(&model.Order{}).
+		Where("updated_at >= ? OR deleted_at >= ?", cursor.Until, cursor.Until).
+		Pluck("created_at", &createdAt).Error; err != nil {
+		return err
+	}
+	hours := map[time.Time]bool{}
+	days := map[time.Time]bool{}
+	for _, t := range createdAt {
+		hours[model.Hourly.Bucket(t)] = true
+		days[model.Daily.Bucket(t)] = true
+	}
+	for _, hour := range sortedBuckets(hours) {
+		if err := s.rollupHour(hour, config); err != nil {
+			return err
+		}
+	}
+	for _, day := range sortedBuckets(days) {
+		if err := s.rollupDay(day); err != nil {
+			return err
+		}
+	}
+
+	if err := s.recordTrades(cursor.Until, config); err != nil {
+		return err
+	}
+
+	cursor.Until = now
+	return s.db.Save(&cursor).Error
+}
+
+// rolls up the orders created in the hour
+func (s *store) rollupHour(start time.Time, config model.Network) error {
+	orders := []model.Order{}
+	if err := s.db.Unscoped().Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap").
+		Where("created_at >= ? AND created_at < ?", start, start.Add(time.Hour)).
+		Find(&orders).Error; err != nil {
+		return err
+	}
+
+	orderRollups := map[string]map[model.Status]*model.OrderRollup{}
+	userRollups := map[string]*model.UserRollup{}
+	for _, order := range orders {
+		// partially fillable orders are rolled up as their parts
+		if order.InitiatorAtomicSwap == nil || order.FollowerAtomicSwap == nil || order.Partial {
+			continue
+		}
+		initiatorValue, err := s.usdValue([]model.AtomicSwap{*order.InitiatorAtomicSwap}, config)
+		if err != nil {
+			return err
+		}
+		followerValue, err := s.usdValue([]model.AtomicSwap{*order.FollowerAtomicSwap}, config)
+		if err != nil {
+			return err
+		}
+
+		if orderRollups[order.OrderPair] == nil {
+			orderRollups[order.OrderPair] = map[model.Status]*model.OrderRollup{}
+		}
+		rollup, ok := orderRollups[order.OrderPair][order.Status]
+		if !ok {
+			rollup = &model.OrderRollup{Granularity: model.Hourly, BucketStart: start, OrderPair: order.OrderPair, Status: order.Status}
+			orderRollups[order.OrderPair][order.Status] = rollup
+		}
+		rollup.Trades++
+		// a trade is counted once, by the value its maker sent
+		rollup.Volume = rollup.Volume.Add(initiatorValue)
+
+		if order.Status != model.Executed {
+			continue
+		}
+		// makers send the initiator swap and takers the follower swap
+		for _, leg := range []struct {
+			wallet string
+			value  model.Decimal
+		}{{order.Maker, initiatorValue}, {order.Taker, followerValue}} {
+			if leg.wallet == "" {
+				continue
+			}
+			rollup, ok := userRollups[leg.wallet]
+			if !ok {
+				rollup = &model.UserRollup{Granularity: model.Hourly, BucketStart: start, Wallet: leg.wallet}
+				userRollups[leg.wallet] = rollup
+			}
+			rollup.Trades++
+			rollup.Volume = rollup.Volume.Add(leg.value)
+		}
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRollups(tx, model.Hourly, start, flattenOrderRollups(orderRollups), flattenUserRollups(userRollups))
+	})
+}
+
+// rolls up the hourly buckets of the day
+func (s *store) rollupDay(start time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		end := start.Add(model.Daily.Duration())
+
+		hourlyOrders := []model.OrderRollup{}
+		if err := tx.Where("granularity = ? AND bucket_start >= ? AND bucket_start < ?", model.Hourly, start, end).Find(&hourlyOrders).Error; err != nil {
+			return err
+		}
+		orderRollups := map[string]map[model.Status]*model.OrderRollup{}
+		for _, hourly := range hourlyOrders {
+			if orderRollups[hourly.OrderPair] == nil {
+				orderRollups[hourly.OrderPair] = map[model.Status]*model.OrderRollup{}
+			}
+			rollup, ok := orderRollups[hourly.OrderPair][hourly.Status]
+			if !ok {
+				rollup = &model.OrderRollup{Granularity: model.Daily, BucketStart: start, OrderPair: hourly.OrderPair, Status: hourly.Status}
+				orderRollups[hourly.OrderPair][hourly.Status] = rollup
+			}
+			rollup.Trades += hourly.Trades
+			rollup.Volume = rollup.Volume.Add(hourly.Volume)
+		}
+
+		hourlyUsers := []model.UserRollup{}
+		if err := tx.Where("granularity = ? AND bucket_start >= ? AND bucket_start < ?", model.Hourly, start, end).Find(&hourlyUsers).Error; err != nil {
+			return err
+		}
+		userRollups := map[string]*model.UserRollup{}
+		for _, hourly := range hourlyUsers {
+			rollup, ok := userRollups[hourly.Wallet]
+			if !ok {
+				rollup = &model.UserRollup{Granularity: model.Daily, BucketStart: start, Wallet: hourly.Wallet}
+				userRollups[hourly.Wallet] = rollup
+			}
+			rollup.Trades += hourly.Trades
+			rollup.Volume = rollup.Volume.Add(hourly.Volume)
+		}
+
+		return replaceRollups(tx, model.Daily, start, flattenOrderRollups(orderRollups), flattenUserRollups(userRollups))
+	})
+}
+
+func replaceRollups(tx *gorm.DB, granularity model.Granularity, start time.Time, orderRollups []model.OrderRollup, userRollups []model.UserRollup) error {
+	if err := tx.Where("granularity = ? AND bucket_start = ?", granularity, start).Delete(&model.OrderRollup{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("granularity = ? AND bucket_start = ?", granularity, start).Delete(&model.UserRollup{}).Error; err != nil {
+		return err
+	}
+	if len(orderRollups) > 0 {
+		if err := tx.Create(&orderRollups).Error; err != nil {
+			return err
+		}
+	}
+	if len(userRollups) > 0 {
+		if err := tx.Create(&userRollups).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func flattenOrderRollups(rollups map[string]map[model.Status]*model.OrderRollup) []model.OrderRollup {
+	flat := []model.OrderRollup{}
+	for _, statuses := range rollups {
+		for _, rollup := range statuses {
+			flat = append(flat, *rollup)
+		}
+	}
+	return flat
+}
+
+func flattenUserRollups(rollups map[string]*model.UserRollup) []model.UserRollup {
+	flat := []model.UserRollup{}
+	for _, rollup := range rollups {
+		flat = append(flat, *rollup)
+	}
+	return flat
+}
+
+func sortedBuckets(buckets map[time.Time]bool) []time.Time {
+	sorted := make([]time.Time, 0, len(buckets))
+	for bucket := range buckets {
+		sorted = append(sorted, bucket)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	return sorted
+}
+
+// OrderStats returns the rolled up orders created in [from, to), of all pairs if
+// the order pair is empty. Buckets without orders are omitted.
+func (s *store) OrderStats(granularity model.Granularity, from, to time.Time, orderPair string) ([]model.OrderStats, error) {
+	tx := s.db.Where("granularity = ? AND bucket_start >= ? AND bucket_start < ?", granularity, granularity.Bucket(from), to)
+	if orderPair != "" {
+		tx = tx.Where(equalFold("order_pair"), orderPair)
+	}
+	rollups := []model.OrderRollup{}
+	if err := tx.Order("bucket_start ASC").Find(&rollups).Error; err != nil {
+		return nil, err
+	}
+
+	stats := []model.OrderStats{}
+	for _, rollup := range rollups {
+		if len(stats) == 0 || !stats[len(stats)-1].Start.Equal(rollup.BucketStart) {
+			stats = append(stats, model.OrderStats{Start: rollup.BucketStart.UTC()})
+		}
+		bucket := &stats[len(stats)-1]
+		bucket.Trades += rollup.Trades
+		switch rollup.Status {
+		case model.Executed:
+			bucket.Executed += rollup.Trades
+			bucket.Volume = bucket.Volume.Add(rollup.Volume)
+		case model.FailedSoft, model.FailedHard:
+			bucket.Failed += rollup.Trades
+		case model.Cancelled:
+			bucket.Cancelled += rollup.Trades
+		}
+	}
+	for i := range stats {
+		if finished := stats[i].Executed + stats[i].Failed + stats[i].Cancelled; finished > 0 {
+			stats[i].SuccessRate = float64(stats[i].Executed) / float64(finished)
+			stats[i].FailureRate = float64(stats[i].Failed+stats[i].Cancelled) / float64(finished)
+		}
+	}
+	return stats, nil
+}
+
+// UserStats returns the rolled up executed orders of the wallet created in [from, to)
+func (s *store) UserStats(wallet string, granularity model.Granularity, from, to time.Time) ([]model.UserStats, error) {
+	rollups := []model.UserRollup{}
+	if err := s.db.Where("granularity = ? AND bucket_start >= ? AND bucket_start < ? AND "+equalFold("wallet"), granularity, granularity.Bucket(from), to, wallet).
+		Order("bucket_start ASC").Find(&rollups).Error; err != nil {
+		return nil, err
+	}
+	stats := make([]model.UserStats, len(rollups))
+	for i, rollup := range rollups {
+		stats[i] = model.UserStats{Start: rollup.BucketStart.UTC(), Volume: rollup.Volume, Trades: rollup.Trades}
+	}
+	return stats, nil
+}
@@ -0,0 +1,111 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Order rollups", func() {
+	maker := testMaker
+	taker := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	pair := testPair
+	token := model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+	network := model.Network{
+		model.BitcoinTestnet:  model.NetworkConfig{Assets: map[model.Asset]model.Token{model.Primary: {Decimals: 8}}},
+		model.EthereumSepolia: model.NetworkConfig{Assets: map[model.Asset]model.Token{token: {Decimals: 8}}},
+	}
+
+	// inserts an order swapping 1 btc worth $40000 for 1 wbtc worth $40000
+	orderAt := func(store Store, createdAt time.Time, status model.Status) model.Order {
+		initiator := model.AtomicSwap{Chain: model.BitcoinTestnet, Asset: model.Primary, Amount: "100000000", PriceByOracle: model.NewDecimalFromFloat(40000)}
+		follower := model.AtomicSwap{Chain: model.EthereumSepolia, Asset: token, Amount: "100000000", PriceByOracle: model.NewDecimalFromFloat(40000)}
+		return insertOrder(store, model.Order{Model: gorm.Model{CreatedAt: createdAt}, Taker: taker, Status: status}, initiator, follower)
+	}
+
+	It("should roll up orders into hourly and daily buckets", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		day := time.Now().UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+		orderAt(store, day.Add(time.Hour+time.Minute), model.Executed)
+		orderAt(store, day.Add(time.Hour+2*time.Minute), model.FailedSoft)
+		pending := orderAt(store, day.Add(time.Hour+3*time.Minute), model.Filled)
+		cancelled := orderAt(store, day.Add(3*time.Hour), model.Cancelled)
+		Expect(store.Gorm().Delete(&cancelled).Error).NotTo(HaveOccurred())
+		Expect(store.RollupOrders(network)).To(Succeed())
+
+		hourly, err := store.OrderStats(model.Hourly, day, day.Add(24*time.Hour), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hourly).To(HaveLen(2))
+		Expect(hourly[0].Start).To(Equal(day.Add(time.Hour)))
+		Expect(hourly[0].Trades).To(Equal(int64(3)))
+		Expect(hourly[0].Executed).To(Equal(int64(1)))
+		Expect(hourly[0].Failed).To(Equal(int64(1)))
+		Expect(hourly[0].Volume.String()).To(Equal("40000"))
+		Expect(hourly[0].SuccessRate).To(Equal(0.5))
+		Expect(hourly[1].Cancelled).To(Equal(int64(1)))
+		Expect(hourly[1].FailureRate).To(Equal(1.0))
+
+		daily, err := store.OrderStats(model.Daily, day, day.Add(24*time.Hour), pair)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(daily).To(HaveLen(1))
+		Expect(daily[0].Start).To(Equal(day))
+		Expect(daily[0].Trades).To(Equal(int64(4)))
+		Expect(daily[0].SuccessRate).To(BeNumerically("~", 1.0/3))
+
+		other, err := store.OrderStats(model.Daily, day, day.Add(24*time.Hour), "bitcoin_testnet-ethereum_sepolia")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(other).To(BeEmpty())
+
+		// orders updated after a rollup are rolled up again
+		pending.Status = model.Executed
+		Expect(store.Gorm().Save(&pending).Error).NotTo(HaveOccurred())
+		Expect(store.RollupOrders(network)).To(Succeed())
+		Expect(store.RollupOrders(network)).To(Succeed())
+
+		daily, err = store.OrderStats(model.Daily, day, day.Add(24*time.Hour), "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(daily[0].Trades).To(Equal(int64(4)))
+		Expect(daily[0].Executed).To(Equal(int64(2)))
+		Expect(daily[0].Volume.String()).To(Equal("80000"))
+
+		// makers send the initiator swap and takers the follower swap
+		users, err := store.UserStats(taker, model.Daily, day, day.Add(24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(HaveLen(1))
+		Expect(users[0].Trades).To(Equal(int64(2)))
+		Expect(users[0].Volume.String()).To(Equal("80000"))
+		users, err = store.UserStats(maker, model.Hourly, day.Add(2*time.Hour), day.Add(24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(BeEmpty())
+		Expect(dropTestDB()).To(Succeed())
+	})
+	It("should count the volume of a pair as the volume its makers sent", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		day := time.Now().UTC().Truncate(24 * time.Hour).Add(-24 * time.Hour)
+		orderAt(store, day.Add(time.Hour), model.Executed)
+		// the follower is priced differently than the initiator
+		initiator := model.AtomicSwap{Chain: model.BitcoinTestnet, Asset: model.Primary, Amount: "50000000", PriceByOracle: model.NewDecimalFromFloat(40000)}
+		follower := model.AtomicSwap{Chain: model.EthereumSepolia, Asset: token, Amount: "50000000", PriceByOracle: model.NewDecimalFromFloat(39000)}
+		insertOrder(store, model.Order{Model: gorm.Model{CreatedAt: day.Add(2 * time.Hour)}, Taker: taker, Status: model.Executed}, initiator, follower)
+		Expect(store.RollupOrders(network)).To(Succeed())
+
+		pairs, err := store.OrderStats(model.Daily, day, day.Add(24*time.Hour), pair)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pairs).To(HaveLen(1))
+		makers, err := store.UserStats(maker, model.Daily, day, day.Add(24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(makers).To(HaveLen(1))
+		Expect(pairs[0].Volume.String()).To(Equal("60000"))
+		Expect(pairs[0].Volume.String()).To(Equal(makers[0].Volume.String()))
+		takers, err := store.UserStats(taker, model.Daily, day, day.Add(24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(takers[0].Volume.String()).To(Equal("59500"))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
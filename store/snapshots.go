@@ -0,0 +1,143 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// snapshots are kept as taken for a day, the first of each hour for a month and
+// the first of each day after that
+const (
+	tvlRawRetention    = 24 * time.Hour
+	tvlHourlyRetention = 30 * 24 * time.Hour
+)
+
+// SnapshotTVL records the value locked in the active swaps of every configured
+// asset at its current oracle price and downsamples old snapshots. Assets without
+// a price are skipped and reported in the error after the others are recorded.
+func (s *store) SnapshotTVL(config model.Config) error {
+	takenAt := time.Now().UTC()
+
+	locked := []struct {
+		Chain  model.Chain
+		Asset  string
+		Amount model.Decimal
+	}{}
+	if err := s.db.Model(&model.AtomicSwap{}).
+		Select(fmt.Sprintf("chain, LOWER(asset) AS asset, COALESCE(SUM(%s), 0) AS amount", castAmount(s.db, "amount"))).
+		Where("status > ? AND status < ?", model.NotStarted, model.RedeemDetected).
+		Group("chain, LOWER(asset)").
+		Scan(&locked).Error; err != nil {
+		return err
+	}
+	amounts := map[model.Chain]map[string]model.Decimal{}
+	for _, row := range locked {
+		if amounts[row.Chain] == nil {
+			amounts[row.Chain] = map[string]model.Decimal{}
+		}
+		amounts[row.Chain][row.Asset] = row.Amount
+	}
+
+	snapshots := []model.TVLSnapshot{}
+	failed := []string{}
+	for chain, network := range config.Network {
+		for asset, token := range network.Assets {
+			price, err := s.price(chain, asset, config)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s:%s: %v", chain, asset, err))
+				continue
+			}
+			amount := model.NewDecimalFromBigInt(amounts[chain][strings.ToLower(string(asset))].Floor(), token.Decimals)
+			usdPrice := price.Price
+			snapshots = append(snapshots, model.TVLSnapshot{
+				TakenAt: takenAt,
+				Chain:   chain,
+				Asset:   asset,
+				Amount:  amount,
+				Price:   usdPrice,
+				Value:   amount.Mul(usdPrice),
+			})
+		}
+	}
+	if len(snapshots) > 0 {
+		if err := s.db.Create(&snapshots).Error; err != nil {
+			return err
+		}
+	}
+	if err := s.downsampleTVL(takenAt); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to price locked assets: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (s *store) downsampleTVL(now time.Time) error {
+	if err := s.downsampleTVLTo("", model.Hourly, model.Hourly.Bucket(now.Add(-tvlRawRetention))); err != nil {
+		return err
+	}
+	return s.downsampleTVLTo(model.Hourly, model.Daily, model.Daily.Bucket(now.Add(-tvlHourlyRetention)))
+}
+
+// keeps the first snapshot of an asset in each bucket taken before the cutoff,
+// which is the start of a bucket so that only complete buckets are downsampled
+func (s *store) downsampleTVLTo(from, to model.Granularity, cutoff time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		snapshots := []model.TVLSnapshot{}
+		if err := tx.Where("resolution = ? AND taken_at < ?", from, cutoff).Order("taken_at ASC, id ASC").Find(&snapshots).Error; err != nil {
+			return err
+		}
+		kept := map[string]bool{}
+		keep, drop := []uint{}, []uint{}
+		for _, snapshot := range snapshots {
+			key := fmt.Sprintf("%s:%s:%d", snapshot.Chain, strings.ToLower(string(snapshot.Asset)), to.Bucket(snapshot.TakenAt).Unix())
+			if kept[key] {
+				drop = append(drop, snapshot.ID)
+				continue
+			}
+			kept[key] = true
+			keep = append(keep, snapshot.ID)
+		}
+		if len(keep) > 0 {
+			if err := tx.Model(&model.TVLSnapshot{}).Where("id IN ?", keep).Update("resolution", to).Error; err != nil {
+				return err
+			}
+		}
+		if len(drop) > 0 {
+			if err := tx.Delete(&model.TVLSnapshot{}, drop).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// TVLSeries returns the value locked on the chain, or on every chain if it is
+// empty, at the snapshots taken in [from, to)
+func (s *store) TVLSeries(chain model.Chain, from, to time.Time) ([]model.TVLPoint, error) {
+	tx := s.db.Where("taken_at >= ? AND taken_at < ?", from, to)
+	if chain != "" {
+		tx = tx.Where("chain = ?", chain)
+	}
+	snapshots := []model.TVLSnapshot{}
+	if err := tx.Order("taken_at ASC, chain ASC, id ASC").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	points := []model.TVLPoint{}
+	for _, snapshot := range snapshots {
+		snapshot.TakenAt = snapshot.TakenAt.UTC()
+		if len(points) == 0 || !points[len(points)-1].Time.Equal(snapshot.TakenAt) || points[len(points)-1].Chain != snapshot.Chain {
+			points = append(points, model.TVLPoint{Time: snapshot.TakenAt, Chain: snapshot.Chain, Assets: []model.TVLSnapshot{}})
+		}
+		point := &points[len(points)-1]
+		point.Value = point.Value.Add(snapshot.Value)
+		point.Assets = append(point.Assets, snapshot)
+	}
+	return points, nil
+}
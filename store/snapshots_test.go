@@ -0,0 +1,90 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("TVL snapshots", func() {
+	token := model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+
+	It("should snapshot the value locked in active swaps", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		swaps := []model.AtomicSwap{
+			{Chain: model.BitcoinTestnet, Asset: model.Primary, Amount: "100000000", Status: model.Initiated},
+			{Chain: model.BitcoinTestnet, Asset: model.Primary, Amount: "50000000", Status: model.Detected},
+			{Chain: model.BitcoinTestnet, Asset: model.Primary, Amount: "70000000", Status: model.Redeemed},
+			{Chain: model.EthereumSepolia, Asset: model.NewSecondary("0x130ff59b75a415d0bccc2e996acaf27ce70fd5ef"), Amount: "200000000", Status: model.Initiated},
+		}
+		Expect(store.Gorm().Create(&swaps).Error).NotTo(HaveOccurred())
+
+		before := time.Now().UTC().Add(-time.Second)
+		Expect(store.SnapshotTVL(config)).To(Succeed())
+		points, err := store.TVLSeries("", before, time.Now().UTC().Add(time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(points).To(HaveLen(2))
+		values := map[model.Chain]string{}
+		for _, point := range points {
+			Expect(point.Assets).To(HaveLen(1))
+			values[point.Chain] = point.Value.String()
+		}
+		Expect(values).To(Equal(map[model.Chain]string{
+			model.BitcoinTestnet:  "60000",
+			model.EthereumSepolia: "80000",
+		}))
+
+		points, err = store.TVLSeries(model.EthereumSepolia, before, time.Now().UTC().Add(time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(points).To(HaveLen(1))
+		Expect(points[0].Assets[0].Asset).To(Equal(token))
+		Expect(points[0].Assets[0].Amount.String()).To(Equal("2"))
+		Expect(points[0].Assets[0].Price.String()).To(Equal("40000"))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should downsample old snapshots", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		day := time.Now().UTC().Truncate(24 * time.Hour)
+		snapshot := func(takenAt time.Time) model.TVLSnapshot {
+			return model.TVLSnapshot{TakenAt: takenAt, Chain: model.BitcoinTestnet, Asset: model.Primary, Value: model.NewDecimalFromFloat(1)}
+		}
+		snapshots := []model.TVLSnapshot{
+			// raw snapshots of two days ago are thinned to one per hour
+			snapshot(day.Add(-48 * time.Hour)),
+			snapshot(day.Add(-48*time.Hour + 5*time.Minute)),
+			snapshot(day.Add(-47 * time.Hour)),
+			// raw snapshots of two months ago are thinned to one per day
+			snapshot(day.Add(-60 * 24 * time.Hour)),
+			snapshot(day.Add(-60*24*time.Hour + time.Hour)),
+			snapshot(day.Add(-60*24*time.Hour + 2*time.Hour)),
+			// recent snapshots are kept
+			snapshot(time.Now().UTC().Add(-time.Minute)),
+			snapshot(time.Now().UTC().Add(-2 * time.Minute)),
+		}
+		Expect(store.Gorm().Create(&snapshots).Error).NotTo(HaveOccurred())
+		Expect(store.SnapshotTVL(model.Config{})).To(Succeed())
+
+		points, err := store.TVLSeries(model.BitcoinTestnet, day.Add(-48*time.Hour), day.Add(-46*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(points).To(HaveLen(2))
+		Expect(points[0].Time).To(Equal(day.Add(-48 * time.Hour)))
+		Expect(points[1].Time).To(Equal(day.Add(-47 * time.Hour)))
+
+		points, err = store.TVLSeries(model.BitcoinTestnet, day.Add(-61*24*time.Hour), day.Add(-59*24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(points).To(HaveLen(1))
+		Expect(points[0].Time).To(Equal(day.Add(-60 * 24 * time.Hour)))
+
+		points, err = store.TVLSeries(model.BitcoinTestnet, time.Now().UTC().Add(-time.Hour), time.Now().UTC())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(points).To(HaveLen(2))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
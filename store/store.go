@@ -1,14 +1,12 @@
 package store
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"math/big"
-	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,6 +15,7 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/price"
 	"github.com/catalogfi/orderbook/rest"
 	"github.com/catalogfi/orderbook/swapper/bitcoin"
 	"github.com/catalogfi/orderbook/watcher"
@@ -25,9 +24,9 @@ import (
 )
 
 type store struct {
-	mu    *sync.RWMutex
-	db    *gorm.DB
-	cache map[string]Price
+	mu     *sync.RWMutex
+	db     *gorm.DB
+	prices *price.Cache
 }
 
 type Store interface {
@@ -35,9 +34,24 @@ type Store interface {
 	watcher.Store
 
 	Gorm() *gorm.DB
-}
-
-func New(dialector gorm.Dialector, setupPath string, opts ...gorm.Option) (Store, error) {
+	// Prices returns the price cache of the store, to be shared with other services quoting prices
+	Prices() *price.Cache
+	// RollupOrders rolls up the statistics and trades of the orders updated since the last rollup
+	RollupOrders(config model.Network) error
+	// SnapshotTVL records the value locked per chain and asset and downsamples old snapshots
+	SnapshotTVL(config model.Config) error
+	// DrawLotteries draws the lotteries which have ended and fills their orders with the winners
+	DrawLotteries(config model.Network) error
+	// UpdateBond records the bond of a filler verified on chain
+	UpdateBond(filler string, amount *big.Int, verifiedAt time.Time) error
+	// UpdateInventoryBalance records the balance of the address of an inventory read on chain
+	UpdateInventoryBalance(inventory model.Inventory, amount *big.Int, readAt time.Time) error
+	// ScoreFillers scores the reputation of the fillers from the orders they filled
+	ScoreFillers() error
+}
+
+// New opens the database and applies all pending migrations
+func New(dialector gorm.Dialector, opts ...gorm.Option) (Store, error) {
 	db, err := gorm.Open(dialector, opts...)
 	if err != nil {
 		return nil, err
@@ -52,26 +66,10 @@ func New(dialector gorm.Dialector, setupPath string, opts ...gorm.Option) (Store
 	sqlDB.SetMaxOpenConns(maxConnections)
 	sqlDB.SetConnMaxIdleTime(10 * time.Minute)
 
-	if err := db.AutoMigrate(&model.Order{}, &model.AtomicSwap{}, &model.Blacklist{}); err != nil {
+	if err := MigrateUp(db, 0); err != nil {
 		return nil, err
 	}
-	if setupPath != "" {
-		err = setupTriggers(db, setupPath)
-		if err != nil {
-			return nil, err
-		}
-	}
-	return &store{mu: new(sync.RWMutex), cache: make(map[string]Price), db: db}, nil
-}
-
-func setupTriggers(db *gorm.DB, setupPath string) error {
-	c, ioErr := os.ReadFile(setupPath)
-	if ioErr != nil {
-		return fmt.Errorf("error reading file from path : %s,error : %v", setupPath, ioErr)
-	}
-	sql := string(c)
-	tx := db.Exec(sql)
-	return tx.Error
+	return &store{mu: new(sync.RWMutex), prices: price.NewCache(), db: db}, nil
 }
 
 func (s *store) GetSecrets(lastUpdated time.Time) ([]model.SecretRevealed, error) {
@@ -82,182 +80,63 @@ func (s *store) GetSecrets(lastUpdated time.Time) ([]model.SecretRevealed, error
 	return secrets, nil
 }
 
-func (s *store) totalVolume(from, to time.Time, config model.Network) (*big.Int, error) {
-	orders := []model.Order{}
-	if err := s.db.Where("created_at BETWEEN ? AND ? AND status = ?", from, to, model.Executed).Find(&orders).Error; err != nil {
-		return nil, err
-	}
-	swapIDs := make([]uint, 2*len(orders))
-	for i, order := range orders {
-		swapIDs[2*i] = order.InitiatorAtomicSwapID
-		swapIDs[2*i+1] = order.FollowerAtomicSwapID
-	}
-	swaps := []model.AtomicSwap{}
-	if err := s.db.Find(&swaps, swapIDs).Error; err != nil {
-		return nil, err
-	}
-	return s.usdValue(swaps, config)
-}
-
-func (s *store) userVolume(from, to time.Time, user string, config model.Network) (*big.Int, error) {
-	orders := []model.Order{}
-	if err := s.db.Where("created_at BETWEEN ? AND ? AND status = ? AND (maker = ? OR taker = ?)", from, to, model.Executed, user, user).Find(&orders).Error; err != nil {
-		return nil, err
-	}
-	swapIDs := make([]uint, len(orders))
-	for i, order := range orders {
-		if order.Maker == user {
-			swapIDs[i] = order.InitiatorAtomicSwapID
-		} else {
-			swapIDs[i] = order.FollowerAtomicSwapID
-		}
-	}
-	swaps := []model.AtomicSwap{}
-	if err := s.db.Find(&swaps, swapIDs).Error; err != nil {
-		return nil, err
-	}
-	return s.usdValue(swaps, config)
-}
-
-// maintain a cache for the price and refresh it at the TTL interval
-func (s *store) price(chain model.Chain, asset model.Asset, config model.Config) (Price, error) {
+// get the median price of the asset from its oracles, cached for the TTL interval
+func (s *store) price(chain model.Chain, asset model.Asset, config model.Config) (price.Price, error) {
 	_, ok := config.Network[chain]
 	if !ok {
-		return Price{}, fmt.Errorf("unsupported chain: %s", chain)
+		return price.Price{}, fmt.Errorf("unsupported chain: %s", chain)
 	}
-	_, ok = config.Network[chain].Assets[asset]
+	token, ok := config.Network[chain].Assets[asset]
 	if !ok {
-		return Price{}, fmt.Errorf("unsupported asset: %s", asset)
+		return price.Price{}, fmt.Errorf("unsupported asset: %s", asset)
 	}
 
-	priceObj, ok := s.cache[config.Network[chain].Assets[asset].Oracle]
-	if !ok || time.Now().Unix()-priceObj.Timestamp > config.PriceTTL {
-		updatedPrice, err := GetPrice(config.Network[chain].Assets[asset].Oracle)
-		if err != nil {
-			return Price{}, err
-		}
-		s.cache[config.Network[chain].Assets[asset].Oracle] = updatedPrice
-		priceObj = updatedPrice
+	sources := append([]string{token.Oracle}, token.Oracles...)
+	ttl := time.Duration(config.PriceTTL) * time.Second
+	oracle, err := price.NewMedianOracleFromSources(sources, ttl, config.PriceDeviation)
+	if err != nil {
+		return price.Price{}, err
 	}
-	return priceObj, nil
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return s.prices.Get(ctx, strings.Join(sources, ","), ttl, oracle)
 }
 
 // total amount of funds that are currently locked in active atomic swaps related to this system
-func (s *store) ValueLockedByChain(chain model.Chain, config model.Network) (*big.Int, error) {
+func (s *store) ValueLockedByChain(chain model.Chain, config model.Network) (model.Decimal, error) {
 	// s.mu.RLock()
 	// defer s.mu.RUnlock()
 	swaps := []model.AtomicSwap{}
 	if tx := s.db.Where("chain = ? AND status > ? AND status < ?", chain, model.NotStarted, model.RedeemDetected).Find(&swaps); tx.Error != nil {
-		return nil, tx.Error
-	}
-	return s.usdValue(swaps, config)
-}
-
-// total amount of value traded by the user in the last 24 hrs in USD
-func (s *store) ValueTradedByUserYesterday(user string, config model.Network) (*big.Int, error) {
-	// s.mu.RLock()
-	// defer s.mu.RUnlock()
-
-	return s.valueTradedByUserYesterday(user, config)
-}
-func (s *store) ValueTradedByUserYesterdayInSats(user string, config model.Network) (*big.Int, error) {
-	// s.mu.RLock()
-	// defer s.mu.RUnlock()
-
-	yesterday := time.Now().UTC().Truncate(24 * time.Hour)
-	var initiatorSum uint64
-	if tx := s.db.Table("orders").
-		Select("COALESCE(SUM(CAST(initiator_atomic_swaps.amount as bigint)), 0)").
-		Joins("JOIN atomic_swaps as initiator_atomic_swaps ON initiator_atomic_swaps.id = orders.initiator_atomic_swap_id").
-		Where("orders.maker = ? AND orders.status >= ? AND orders.status < ? AND orders.created_at >= ?", user, model.Created, model.FailedSoft, yesterday).
-		Scan(&initiatorSum); tx.Error != nil {
-		return nil, tx.Error
-	}
-
-	var followerSum uint64
-	if tx := s.db.Table("orders").
-		Select("COALESCE(SUM(CAST(follower_atomic_swaps.amount as bigint)), 0)").
-		Joins("JOIN atomic_swaps as follower_atomic_swaps ON follower_atomic_swaps.id = orders.follower_atomic_swap_id").
-		Where("orders.taker = ? AND orders.status >= ? AND orders.status < ? AND orders.created_at >= ?", user, model.Created, model.FailedSoft, yesterday).
-		Scan(&followerSum); tx.Error != nil {
-		return nil, tx.Error
-	}
-	sum := big.NewInt(0)
-	sum.Add(sum, new(big.Int).SetUint64(initiatorSum))
-	sum.Add(sum, new(big.Int).SetUint64(followerSum))
-	return sum, nil
-}
-
-func (s *store) valueTradedByUserYesterday(user string, config model.Network) (*big.Int, error) {
-	yesterday := time.Now().UTC().Truncate(24 * time.Hour)
-	orders := []model.Order{}
-	if tx := s.db.Where("(maker = ? OR taker = ?) AND status >= ? AND status < ? AND created_at >= ?", user, user, model.Created, model.FailedSoft, yesterday).Find(&orders); tx.Error != nil {
-		return nil, tx.Error
-	}
-	if len(orders) == 0 {
-		return big.NewInt(0), nil
-	}
-	swapIDs := make([]uint, len(orders))
-	for i, order := range orders {
-		swapIDs[i] = order.InitiatorAtomicSwapID
-		if order.Taker == user {
-			swapIDs[i] = order.FollowerAtomicSwapID
-		}
-	}
-	swaps := []model.AtomicSwap{}
-	if tx := s.db.Find(&swaps, swapIDs); tx.Error != nil {
-		return nil, tx.Error
+		return model.Decimal{}, tx.Error
 	}
 	return s.usdValue(swaps, config)
 }
 
 // calculates the cummulative usd value of all the given swaps
-func (s *store) usdValue(swaps []model.AtomicSwap, config model.Network) (*big.Int, error) {
-	tvlF := big.NewFloat(0)
-
-	// scoping the cache
-	{
-		cacheNormalisers := map[model.Asset]*big.Int{}
-		for _, swap := range swaps {
-			if swap.FilledAmount == "" {
-				swap.FilledAmount = "0"
-			}
-			swapAmount, ok := new(big.Int).SetString(swap.Amount, 10)
-			if !ok {
-				return nil, fmt.Errorf("currupted value stored for amount: %v", swap.Amount)
-			}
-			normaliser, ok := cacheNormalisers[swap.Asset]
-			if !ok {
-				decimals := config[swap.Chain].Assets[swap.Asset].Decimals
-				if decimals == 0 {
-					// TODO: maintain legacy assets and blacklist pairs while creation
-					decimals = 8
-				}
-				normaliser = new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
-				cacheNormalisers[swap.Asset] = normaliser
-			}
-			normalisedLockedAmount := new(big.Float).Quo(new(big.Float).SetInt(swapAmount), new(big.Float).SetInt(normaliser))
-			lockedAmountValue := new(big.Float).Mul(big.NewFloat(swap.PriceByOracle), normalisedLockedAmount)
-			tvlF.Add(lockedAmountValue, tvlF)
-
+func (s *store) usdValue(swaps []model.AtomicSwap, config model.Network) (model.Decimal, error) {
+	tvl := model.Decimal{}
+	for _, swap := range swaps {
+		swapAmount, ok := new(big.Int).SetString(swap.Amount, 10)
+		if !ok {
+			return model.Decimal{}, fmt.Errorf("currupted value stored for amount: %v", swap.Amount)
 		}
+		decimals := config[swap.Chain].Assets[swap.Asset].Decimals
+		if decimals == 0 {
+			// TODO: maintain legacy assets and blacklist pairs while creation
+			decimals = 8
+		}
+		tvl = tvl.Add(s.calculateUSDValue(swapAmount, swap.PriceByOracle, decimals))
 	}
-
-	// ignoring accuracy
-	tvl, _ := tvlF.Int(nil)
 	return tvl, nil
 }
 
-func (s *store) calculateUSDValue(amount *big.Int, price float64, decimals int64) *big.Int {
-	normaliser := new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)
-	normalisedAmount := new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(normaliser))
-	amountValue := new(big.Float).Mul(big.NewFloat(price), normalisedAmount)
-	val, _ := amountValue.Int(nil)
-	return val
+func (s *store) calculateUSDValue(amount *big.Int, price model.Decimal, decimals int64) model.Decimal {
+	return model.NewDecimalFromBigInt(amount, decimals).Mul(price)
 }
 
 // create a new order with the given details
-func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, secretHash, userBtcWalletAddress string, sendAmount, receiveAmount, feeInBtc, feeInSeed *big.Int, IsDiscounted bool, config model.Config, afterHook ...rest.AfterHook) (uint, error) {
+func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, secretHash, userBtcWalletAddress string, sendAmount, receiveAmount, feeInBtc, feeInSeed *big.Int, IsDiscounted bool, terms model.OrderTerms, config model.Config, afterHook ...rest.AfterHook) (uint, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	// check if creatorAddress is valid eth address
@@ -291,6 +170,13 @@ func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sec
 	if secretHash, err = CheckHash(secretHash); err != nil {
 		return 0, err
 	}
+	var existing int64
+	if tx := s.db.Unscoped().Model(&model.Order{}).Where("secret_hash = ?", secretHash).Count(&existing); tx.Error != nil {
+		return 0, tx.Error
+	}
+	if existing > 0 {
+		return 0, fmt.Errorf("an order with secret hash %s already exists", secretHash)
+	}
 
 	if sendAmount.Cmp(new(big.Int).SetInt64(0)) <= 0 {
 		return 0, fmt.Errorf("invalid send amount")
@@ -298,6 +184,30 @@ func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sec
 	if receiveAmount.Cmp(new(big.Int).SetInt64(0)) <= 0 {
 		return 0, fmt.Errorf("invalid receive amount")
 	}
+	now := time.Now().UTC()
+	if terms, err = terms.Validate(now, receiveAmount); err != nil {
+		return 0, err
+	}
+	if terms.Type == model.AuctionOrder && IsDiscounted {
+		return 0, fmt.Errorf("auction orders cannot be discounted")
+	}
+	if len(terms.PartSecretHashes) > 0 && IsDiscounted {
+		return 0, fmt.Errorf("partially fillable orders cannot be discounted")
+	}
+	partSecretHashes, err := s.checkPartSecretHashes(secretHash, terms.PartSecretHashes)
+	if err != nil {
+		return 0, err
+	}
+	allowedFillers, err := checkAllowedFillers(creator, terms.AllowedFillers)
+	if err != nil {
+		return 0, err
+	}
+	lotterySeed, lotterySeedHash := "", ""
+	if terms.LotteryWindow > 0 {
+		if lotterySeed, lotterySeedHash, err = newLotterySeed(); err != nil {
+			return 0, err
+		}
+	}
 
 	initiatorSwapPrice, err := s.price(sendChain, sendAsset, config)
 	if err != nil {
@@ -309,29 +219,23 @@ func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sec
 		return 0, fmt.Errorf("failed to get price for %s: %v", receiveAsset, err)
 	}
 
-	if config.DailyLimit != "" {
-		// get the total amount traded by the user in the last 24 hrs for limit checks
-		tradedVolumeinSats, err := s.ValueTradedByUserYesterdayInSats(creator, config.Network)
-		if err != nil {
-			return 0, err
-		}
-		currentValue := new(big.Int).Add(tradedVolumeinSats, sendAmount)
-		dailyLimit, ok := new(big.Int).SetString(config.DailyLimit, 10)
-		if !ok {
-			return 0, fmt.Errorf("invalid daily limit: %v", err)
-		}
-
-		if limit, ok := GrantedLimits[strings.ToLower(creator)]; ok && time.Now().UTC().Before(limit.Expiry) {
-			dailyLimit = big.NewInt(limit.Amount)
-		}
+	// wallet specific overrides of the trade limits
+	if config, err = s.applyUserLimits(creator, orderPair, config); err != nil {
+		return 0, err
+	}
 
-		if currentValue.Cmp(dailyLimit) > 0 {
-			return 0, fmt.Errorf("reached daily limit,daily limit : %s, current value : %s", dailyLimit, currentValue)
-		}
+	// value the order with the same oracle price stored on its initiator swap
+	if err := s.checkUSDLimits(creator, model.AtomicSwap{
+		Chain:         sendChain,
+		Asset:         sendAsset,
+		Amount:        sendAmount.String(),
+		PriceByOracle: initiatorSwapPrice.Price,
+	}, config); err != nil {
+		return 0, err
 	}
 
 	// get the number of orders to calculate user specific nonce
-	orders, err := s.FilterOrders(creator, "", "", "", 0, 0, 0, 0, 0, 0, 0, false)
+	orders, _, err := s.FilterOrders(model.OrderFilter{Maker: creator})
 	if err != nil {
 		return 0, err
 	}
@@ -358,18 +262,13 @@ func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sec
 			return 0, fmt.Errorf("invalid send amount: %s", sendAmount)
 		}
 	}
-	var price float64
-	// ignoring accuracy
+	// price of the order as the ratio of the amounts in their smallest units
+	priceDenominator := receiveAmount
 	if IsDiscounted {
-		newReceiveAmt := new(big.Int).Sub(receiveAmount, feeInBtc)
-		price, _ = new(big.Float).Quo(new(big.Float).SetInt(sendAmount), new(big.Float).SetInt(newReceiveAmt)).Float64()
-	} else {
-		price, _ = new(big.Float).Quo(new(big.Float).SetInt(sendAmount), new(big.Float).SetInt(receiveAmount)).Float64()
-	}
-	if math.IsInf(price, 0) {
-		return 0, fmt.Errorf("invalid amount in price")
+		priceDenominator = new(big.Int).Sub(receiveAmount, feeInBtc)
 	}
-	if math.IsNaN(price) {
+	orderPrice, err := model.NewDecimalFromBigInt(sendAmount, 0).Quo(model.NewDecimalFromBigInt(priceDenominator, 0))
+	if err != nil {
 		return 0, fmt.Errorf("invalid amount in price")
 	}
 
@@ -403,6 +302,14 @@ func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sec
 		}
 		return 0, tx.Error
 	}
+	for _, swap := range []*model.AtomicSwap{&initiatorAtomicSwap, &followerAtomicSwap} {
+		if err := appendSwapEvent(trx, model.NotStarted, swap, "maker:"+creator); err != nil {
+			if err := trx.Rollback().Error; err != nil {
+				return 0, fmt.Errorf("failed to create order %v", err)
+			}
+			return 0, err
+		}
+	}
 
 	order := model.Order{
 		Maker:                 creator,
@@ -411,13 +318,45 @@ func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sec
 		FollowerAtomicSwapID:  followerAtomicSwap.ID,
 		InitiatorAtomicSwap:   &initiatorAtomicSwap,
 		FollowerAtomicSwap:    &followerAtomicSwap,
-		Price:                 price,
+		Price:                 orderPrice,
 		SecretHash:            secretHash,
 		Status:                model.Created,
 		SecretNonce:           uint64(len(orders)) + 1,
 		UserBtcWalletAddress:  userBtcWalletAddress,
 		IsDiscounted:          IsDiscounted,
 		FeeInSeed:             feeInSeed.String(),
+		Type:                  terms.Type,
+	}
+	if !terms.ExpiresAt.IsZero() {
+		expiresAt := terms.ExpiresAt.UTC()
+		order.ExpiresAt = &expiresAt
+	}
+	if terms.Type == model.AuctionOrder {
+		order.Auction = &model.AuctionCurve{
+			StartAmount: receiveAmount.String(),
+			EndAmount:   terms.AuctionEndAmount.String(),
+			StartsAt:    now,
+			EndsAt:      *order.ExpiresAt,
+		}
+	}
+	if terms.Quote != nil {
+		order.Taker = terms.Quote.Filler
+		order.QuoteID = &terms.Quote.ID
+	}
+	if len(allowedFillers) > 0 {
+		order.AllowedFillers = allowedFillers
+	}
+	if terms.LotteryWindow > 0 {
+		lotteryEndsAt := now.Add(terms.LotteryWindow)
+		order.LotteryEndsAt = &lotteryEndsAt
+		order.LotterySecret = lotterySeed
+		order.LotterySeedHash = lotterySeedHash
+	}
+	if len(partSecretHashes) > 0 {
+		order.Partial = true
+		order.FilledAmount = "0"
+		order.RemainingAmount = sendAmount.String()
+		order.PartSecretHashes = partSecretHashes
 	}
 	if tx := trx.Create(&order); tx.Error != nil {
 		if err := trx.Rollback().Error; err != nil {
@@ -425,6 +364,20 @@ func (s *store) CreateOrder(creator, sendAddress, receiveAddress, orderPair, sec
 		}
 		return 0, tx.Error
 	}
+	if err := appendOrderEvent(trx, model.Unknown, &order, "maker:"+creator); err != nil {
+		if err := trx.Rollback().Error; err != nil {
+			return 0, fmt.Errorf("failed to create order %v", err)
+		}
+		return 0, err
+	}
+	if terms.Quote != nil {
+		if err := acceptQuote(trx, terms.Quote, order.ID); err != nil {
+			if err := trx.Rollback().Error; err != nil {
+				return 0, fmt.Errorf("failed to create order %v", err)
+			}
+			return 0, err
+		}
+	}
 
 	if IsDiscounted {
 		if len(afterHook) != 1 {
@@ -454,31 +407,134 @@ func (s *store) FillOrder(orderID uint, filler, sendAddress, receiveAddress stri
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	order := &model.Order{}
-	if tx := s.db.First(order, orderID); tx.Error != nil {
-		return tx.Error
+	// the order is locked until the transaction ends, other replicas filling or
+	// cancelling it wait for the lock and see the updated status
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if err := checkFillable(order, filler, now); err != nil {
+			return err
+		}
+		return s.fillOrder(tx, order, filler, sendAddress, receiveAddress, config, now)
+	})
+}
+
+// fill the locked order with the atomic swaps of the filler
+func (s *store) fillOrder(tx *gorm.DB, order *model.Order, filler, sendAddress, receiveAddress string, config model.Network, now time.Time) error {
+	// a partially fillable order is filled by a child order for its remaining amount
+	if order.Partial {
+		remaining, ok := new(big.Int).SetString(order.RemainingAmount, 10)
+		if !ok {
+			return fmt.Errorf("constraint violation: corrupted remaining amount: %v", order.RemainingAmount)
+		}
+		_, err := s.fillPart(tx, order, filler, sendAddress, receiveAddress, remaining, config, now)
+		return err
+	}
+	initiateAtomicSwap := &model.AtomicSwap{}
+	if err := tx.First(initiateAtomicSwap, order.InitiatorAtomicSwapID).Error; err != nil {
+		return err
+	}
+	followerAtomicSwap := &model.AtomicSwap{}
+	if err := tx.First(followerAtomicSwap, order.FollowerAtomicSwapID).Error; err != nil {
+		return err
+	}
+	// the receive amount of an auction is locked in when it is filled
+	if order.Auction != nil {
+		receiveAmount, err := order.Auction.AmountAt(now)
+		if err != nil {
+			return fmt.Errorf("constraint violation: corrupted auction: %v", err)
+		}
+		sendAmount, ok := new(big.Int).SetString(initiateAtomicSwap.Amount, 10)
+		if !ok {
+			return fmt.Errorf("constraint violation: corrupted send amount: %v", initiateAtomicSwap.Amount)
+		}
+		followerAtomicSwap.Amount = receiveAmount.String()
+		if order.Price, err = model.NewDecimalFromBigInt(sendAmount, 0).Quo(model.NewDecimalFromBigInt(receiveAmount, 0)); err != nil {
+			return fmt.Errorf("invalid amount in price")
+		}
+	}
+	if err := s.fillSwaps(order.OrderPair, order.SecretHash, sendAddress, receiveAddress, initiateAtomicSwap, followerAtomicSwap, config); err != nil {
+		return err
+	}
+	order.Taker = filler
+	order.Status = model.Filled
+	order.FilledAt = &now
+
+	if err := transitionOrder(tx, order, model.Created, "filler:"+filler); err != nil {
+		return err
 	}
+	if err := saveSwap(tx, initiateAtomicSwap, "filler:"+filler); err != nil {
+		return err
+	}
+	return saveSwap(tx, followerAtomicSwap, "filler:"+filler)
+}
+
+// check that the order can be filled by the filler at the given time
+func checkFillable(order *model.Order, filler string, now time.Time) error {
 	if order.Status != model.Created {
 		return fmt.Errorf("order already filled, current status: %v", order.Status)
 	}
-	fromChain, toChain, _, _, err := model.ParseOrderPair(order.OrderPair)
+	// orders created from a quote are assigned to the filler who quoted
+	if order.Taker != "" && order.Taker != filler {
+		return fmt.Errorf("order can only be filled by %s", order.Taker)
+	}
+	if !order.CanBeFilledBy(filler) {
+		return fmt.Errorf("order is private and cannot be filled by %s", filler)
+	}
+	// lottery orders are filled by the winner when the lottery is drawn
+	if order.InLottery() {
+		return fmt.Errorf("order is assigned by a lottery ending at %s", order.LotteryEndsAt.UTC().Format(time.RFC3339))
+	}
+	if order.ExpiresAt != nil && !now.Before(*order.ExpiresAt) {
+		return fmt.Errorf("order expired at %s", order.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// validate the fillers a private order is restricted to, which have to be
+// ethereum addresses other than the maker
+func checkAllowedFillers(creator string, allowedFillers []string) ([]string, error) {
+	seen := map[string]bool{}
+	fillers := make([]string, 0, len(allowedFillers))
+	for _, filler := range allowedFillers {
+		filler = strings.ToLower(filler)
+		if err := CheckAddress(model.Ethereum, filler); err != nil {
+			return nil, fmt.Errorf("invalid allowed filler: %v", err)
+		}
+		if filler == strings.ToLower(creator) {
+			return nil, fmt.Errorf("maker cannot be an allowed filler")
+		}
+		if seen[filler] {
+			continue
+		}
+		seen[filler] = true
+		fillers = append(fillers, filler)
+	}
+	return fillers, nil
+}
+
+// fill the details of the filler in the atomic swaps of an order on the given pair
+// locked with the given secret hash
+func (s *store) fillSwaps(orderPair, secretHash, sendAddress, receiveAddress string, initiateAtomicSwap, followerAtomicSwap *model.AtomicSwap, config model.Network) error {
+	fromChain, toChain, fromAsset, toAsset, err := model.ParseOrderPair(orderPair)
 	if err != nil {
 		return fmt.Errorf("constraint violation: corrupted order pair: %v", err)
 	}
+	if _, ok := config[fromChain].Assets[fromAsset]; !ok {
+		return fmt.Errorf("unsupported asset %s on %s", fromAsset, fromChain)
+	}
+	if _, ok := config[toChain].Assets[toAsset]; !ok {
+		return fmt.Errorf("unsupported asset %s on %s", toAsset, toChain)
+	}
 	if err := CheckAddress(fromChain, receiveAddress); err != nil {
 		return fmt.Errorf("invalid receive address: %v", err)
 	}
 	if err := CheckAddress(toChain, sendAddress); err != nil {
 		return fmt.Errorf("invalid send address: %v", err)
 	}
-	initiateAtomicSwap := &model.AtomicSwap{}
-	if tx := s.db.First(initiateAtomicSwap, order.InitiatorAtomicSwapID); tx.Error != nil {
-		return tx.Error
-	}
-	followerAtomicSwap := &model.AtomicSwap{}
-	if tx := s.db.First(followerAtomicSwap, order.FollowerAtomicSwapID); tx.Error != nil {
-		return tx.Error
-	}
 	toChainAmount, err := s.ValueLockedByChain(toChain, config)
 	if err != nil {
 		return fmt.Errorf("failed to calculate value locked on %s: %v", toChain, err)
@@ -489,118 +545,193 @@ func (s *store) FillOrder(orderID uint, filler, sendAddress, receiveAddress stri
 	}
 	initiatorTimeLock := strconv.FormatInt(config[fromChain].Expiry*2, 10)
 	followerTimelock := strconv.FormatInt(config[toChain].Expiry, 10)
-	initiatorSwapID, err := GetSwapId(fromChain, initiateAtomicSwap.InitiatorAddress, receiveAddress, initiatorTimeLock, order.SecretHash)
+	initiatorSwapID, err := GetSwapId(fromChain, initiateAtomicSwap.InitiatorAddress, receiveAddress, initiatorTimeLock, secretHash)
 	if err != nil {
 		return fmt.Errorf("failed to calculate on-chain identifier %s: %v", fromChain, err)
 	}
-	followerSwapID, err := GetSwapId(toChain, sendAddress, followerAtomicSwap.RedeemerAddress, followerTimelock, order.SecretHash)
+	followerSwapID, err := GetSwapId(toChain, sendAddress, followerAtomicSwap.RedeemerAddress, followerTimelock, secretHash)
 	if err != nil {
 		return fmt.Errorf("failed to calculate on-chain identifier %s: %v", toChain, err)
 	}
 	initiateAtomicSwap.RedeemerAddress = receiveAddress
 	initiateAtomicSwap.Timelock = initiatorTimeLock
-	initiateAtomicSwap.MinimumConfirmations = GetMinConfirmations(fromChainAmount, fromChain, false)
+	initiateAtomicSwap.MinimumConfirmations = GetMinConfirmations(fromChainAmount.Floor(), fromChain, false)
 	initiateAtomicSwap.OnChainIdentifier = initiatorSwapID
 	followerAtomicSwap.InitiatorAddress = sendAddress
 	followerAtomicSwap.Timelock = followerTimelock
-	followerAtomicSwap.MinimumConfirmations = GetMinConfirmations(toChainAmount, toChain, true)
+	followerAtomicSwap.MinimumConfirmations = GetMinConfirmations(toChainAmount.Floor(), toChain, true)
 	followerAtomicSwap.OnChainIdentifier = followerSwapID
-	order.Taker = filler
-	order.Status = model.Filled
-	if tx := s.db.Save(initiateAtomicSwap); tx.Error != nil {
-		return tx.Error
-	}
-	if tx := s.db.Save(followerAtomicSwap); tx.Error != nil {
-		return tx.Error
-	}
-	if tx := s.db.Save(order); tx.Error != nil {
-		return tx.Error
-	}
 	return nil
 }
 
 // delete the given user's order if it is not filled
 func (s *store) CancelOrder(creator string, orderID uint) error {
-	order := &model.Order{}
-	if tx := s.db.First(order, orderID); tx.Error != nil {
-		return tx.Error
-	}
-	if order.Maker != creator {
-		return fmt.Errorf("order can be cancelled only by its creator")
-	}
-	if order.Status != model.Created {
-		return fmt.Errorf("order can be cancelled only if it is not filled, current status: %v", order.Status)
-	}
-	order.Status = model.Cancelled
-	if tx := s.db.Save(order); tx.Error != nil {
-		return fmt.Errorf("failed to update status:%v", tx.Error)
-	}
-	if tx := s.db.Delete(order); tx.Error != nil {
-		return tx.Error
-	}
-	return nil
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderID)
+		if err != nil {
+			return err
+		}
+		if order.Maker != creator {
+			return fmt.Errorf("order can be cancelled only by its creator")
+		}
+		if order.Status != model.Created {
+			return fmt.Errorf("order can be cancelled only if it is not filled, current status: %v", order.Status)
+		}
+		// a partially filled order is closed to further fills, its filled parts complete
+		if order.Partial && order.FilledAmount != "0" {
+			now := time.Now().UTC()
+			order.Status = model.Filled
+			order.FilledAt = &now
+			return transitionOrder(tx, order, model.Created, "maker:"+creator)
+		}
+		order.Status = model.Cancelled
+		if err := transitionOrder(tx, order, model.Created, "maker:"+creator); err != nil {
+			return fmt.Errorf("failed to update status:%v", err)
+		}
+		return tx.Delete(order).Error
+	})
 }
 
 // filter the orders based on the given query parameters
-func (s *store) FilterOrders(maker, taker, orderPair, secretHash string, status model.Status, minPrice, maxPrice float64, minAmount, maxAmount float64, page, perPage int, verbose bool) ([]model.Order, error) {
+func (s *store) FilterOrders(filter model.OrderFilter) ([]model.Order, string, error) {
 	orders := []model.Order{}
-	tx := s.db.Table("orders")
-	if orderPair != "" {
-		tx = tx.Where("order_pair ilike ?", orderPair)
+	tx := s.db.Table("orders").Select("orders.*")
+	if filter.OrderPair != "" {
+		tx = tx.Where(equalFold("order_pair"), filter.OrderPair)
 	}
+
+	// conditions on the atomic swaps of the orders
 	joinAtomicSwaps := false
-	if minAmount != 0 {
+	if filter.MinAmount != 0 {
+		joinAtomicSwaps = true
+		tx = tx.Where(castAmount(s.db, "initiator_swaps.amount")+" >= ?", uint(filter.MinAmount))
+	}
+	if filter.MaxAmount != 0 {
+		joinAtomicSwaps = true
+		tx = tx.Where(castAmount(s.db, "initiator_swaps.amount")+" <= ?", uint(filter.MaxAmount))
+	}
+	if filter.Chain != "" {
+		joinAtomicSwaps = true
+		tx = tx.Where("initiator_swaps.chain = ? OR follower_swaps.chain = ?", filter.Chain, filter.Chain)
+	}
+	if filter.Asset != "" {
 		joinAtomicSwaps = true
-		tx = tx.Where("atomic_swaps.amount >= ?", uint(minAmount))
+		tx = tx.Where(equalFold("initiator_swaps.asset")+" OR "+equalFold("follower_swaps.asset"), filter.Asset, filter.Asset)
 	}
-	if maxAmount != 0 {
+	if filter.SwapStatus != nil {
 		joinAtomicSwaps = true
-		tx = tx.Where("atomic_swaps.amount <= ?", uint(maxAmount))
+		tx = tx.Where("initiator_swaps.status = ? OR follower_swaps.status = ?", *filter.SwapStatus, *filter.SwapStatus)
+	}
+	if filter.TxHash != "" {
+		joinAtomicSwaps = true
+		conditions := []string{}
+		values := []interface{}{}
+		for _, swap := range []string{"initiator_swaps", "follower_swaps"} {
+			for _, column := range []string{"initiate_tx_hash", "redeem_tx_hash", "refund_tx_hash"} {
+				conditions = append(conditions, equalFold(swap+"."+column))
+				values = append(values, filter.TxHash)
+			}
+		}
+		tx = tx.Where(strings.Join(conditions, " OR "), values...)
 	}
 	if joinAtomicSwaps {
-		tx = tx.Joins("JOIN atomic_swaps ON orders.initiator_atomic_swap_id = atomic_swaps.id ")
+		tx = tx.Joins("JOIN atomic_swaps initiator_swaps ON orders.initiator_atomic_swap_id = initiator_swaps.id").
+			Joins("JOIN atomic_swaps follower_swaps ON orders.follower_atomic_swap_id = follower_swaps.id")
+	}
+
+	if filter.MinPrice != 0 {
+		tx = tx.Where("orders.price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice != 0 {
+		tx = tx.Where("orders.price <= ?", filter.MaxPrice)
+	}
+	if filter.Status != model.Unknown {
+		tx = tx.Where("orders.status = ?", filter.Status)
 	}
-	if minPrice != 0 {
-		tx = tx.Where("price >= ?", minPrice)
+	if filter.Type != "" {
+		tx = tx.Where("orders.type = ?", filter.Type)
 	}
-	if maxPrice != 0 {
-		tx = tx.Where("price <= ?", maxPrice)
+	if !filter.ExpiresAfter.IsZero() {
+		tx = tx.Where("orders.expires_at IS NULL OR orders.expires_at > ?", filter.ExpiresAfter)
 	}
-	if status != model.Unknown {
-		tx = tx.Where("orders.status = ?", status)
+	if filter.Maker != "" {
+		tx = tx.Where("orders.maker = ?", filter.Maker)
 	}
-	if maker != "" {
-		tx = tx.Where("maker = ?", maker)
+	if filter.Taker != "" {
+		tx = tx.Where("orders.taker = ?", filter.Taker)
 	}
-	if taker != "" {
-		tx = tx.Where("taker = ?", taker)
+	if filter.Unassigned {
+		tx = tx.Where("orders.taker = '' OR orders.taker IS NULL").
+			Where("orders.allowed_fillers = '' OR orders.allowed_fillers IS NULL")
 	}
-	if secretHash != "" {
-		tx = tx.Where("orders.secret_hash = ?", secretHash)
+	if filter.HidePrivate {
+		conditions := []string{"(orders.allowed_fillers = '' OR orders.allowed_fillers IS NULL) AND (orders.taker = '' OR orders.taker IS NULL OR orders.status <> ?)"}
+		values := []interface{}{model.Created}
+		if filter.Viewer != "" {
+			// addresses have a fixed length, so a substring of the allowed fillers is one of them
+			conditions = append(conditions, "orders.maker = ?", "orders.taker = ?", "orders.allowed_fillers LIKE ?")
+			values = append(values, filter.Viewer, filter.Viewer, "%"+filter.Viewer+"%")
+		}
+		tx = tx.Where(strings.Join(conditions, " OR "), values...)
+	}
+	if filter.SecretHash != "" {
+		tx = tx.Where("orders.secret_hash = ?", filter.SecretHash)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		tx = tx.Where("orders.created_at >= ?", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		tx = tx.Where("orders.created_at < ?", filter.CreatedBefore)
 	}
 
+	sort, err := parseOrderSort(filter.Sort)
+	if err != nil {
+		return nil, "", err
+	}
+	tx = tx.Order(sort.orderBy())
+
 	// pagination
-	if page != 0 && perPage != 0 {
-		tx = tx.Offset((page - 1) * perPage).Limit(perPage)
+	if filter.Limit > 0 || filter.Cursor != "" {
+		if filter.Cursor != "" {
+			cursor, err := decodeOrderCursor(filter.Cursor, sort)
+			if err != nil {
+				return nil, "", err
+			}
+			query, args := sort.after(cursor)
+			tx = tx.Where(query, args...)
+		}
+		if filter.Limit > 0 {
+			// one more order tells whether there is a next page
+			tx = tx.Limit(filter.Limit + 1)
+		}
+	} else if filter.Page != 0 && filter.PerPage != 0 {
+		tx = tx.Offset((filter.Page - 1) * filter.PerPage).Limit(filter.PerPage)
 	}
 
 	// check if verbose
-	if verbose {
+	if filter.Verbose {
 		tx = tx.Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap")
-		tx.Order("id ASC")
 	}
 
 	if tx = tx.Find(&orders); tx.Error != nil {
-		return nil, tx.Error
+		return nil, "", tx.Error
 	}
 
-	return orders, nil
+	nextCursor := ""
+	if filter.Limit > 0 && len(orders) > filter.Limit {
+		orders = orders[:filter.Limit]
+		last := orders[len(orders)-1]
+		nextCursor = encodeOrderCursor(sort, last)
+	}
+	return orders, nextCursor, nil
 }
 
-// filter the orders based on the given query parameters
+// get the orders made or taken by the address and the open private orders it is
+// allowed to fill
 func (s *store) GetOrdersByAddress(address string) ([]model.Order, error) {
 	orders := []model.Order{}
-	if tx := s.db.Where("maker = ? OR taker = ?", address, address).Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap").Find(&orders); tx.Error != nil {
+	// addresses have a fixed length, so a substring of the allowed fillers is one of them
+	if tx := s.db.Where("maker = ? OR taker = ? OR (status = ? AND allowed_fillers LIKE ?)", address, address, model.Created, "%"+address+"%").Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap").Find(&orders); tx.Error != nil {
 		return nil, tx.Error
 	}
 	return orders, nil
@@ -617,7 +748,7 @@ func (s *store) GetPendingOrdersForAddress(address string) ([]model.Order, error
 // get all the orders with active atomic swaps
 func (s *store) GetActiveOrders() ([]model.Order, error) {
 	orders := []model.Order{}
-	if tx := s.db.Where("status IN ?", []model.Status{model.Created, model.Filled}).Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap").Find(&orders); tx.Error != nil {
+	if tx := s.db.Where("status IN ?", []model.Status{model.Created, model.Filled}).Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap").Preload("Children").Find(&orders); tx.Error != nil {
 		return nil, tx.Error
 	}
 	return orders, nil
@@ -637,8 +768,8 @@ func (s *store) GetActiveSwaps(chain model.Chain) ([]model.AtomicSwap, error) {
 
 func (s *store) SwapByOCID(ocID string) (model.AtomicSwap, error) {
 	swap := model.AtomicSwap{}
-	if tx := s.db.Where("on_chain_identifier ilike ?", ocID).First(&swap); tx.Error != nil {
-		return model.AtomicSwap{}, tx.Error
+	if tx := s.db.Where(equalFold("on_chain_identifier"), ocID).First(&swap); tx.Error != nil {
+		return model.AtomicSwap{}, notFound(tx.Error)
 	}
 	return swap, nil
 }
@@ -646,10 +777,17 @@ func (s *store) SwapByOCID(ocID string) (model.AtomicSwap, error) {
 // update the given atomic swap objects on the db
 // @dev should only be used internally and cannot be called by an end user
 func (s *store) UpdateSwap(swap *model.AtomicSwap) error {
-	if tx := s.db.Save(swap); tx.Error != nil {
-		return tx.Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return saveSwap(tx, swap, "watcher:"+string(swap.Chain))
+	})
+}
+
+// maps a missing record to model.ErrNotFound so that callers need not know gorm
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.ErrNotFound
 	}
-	return nil
+	return err
 }
 
 // get the order with the given order id
@@ -659,10 +797,12 @@ func (s *store) GetOrder(orderID uint) (*model.Order, error) {
 		FollowerAtomicSwap:  &model.AtomicSwap{},
 	}
 	if tx := s.db.First(order, orderID); tx.Error != nil {
-		return nil, tx.Error
+		return nil, notFound(tx.Error)
 	}
-
-	return order, s.fillSwapDetails(order)
+	if err := s.fillSwapDetails(order); err != nil {
+		return nil, err
+	}
+	return order, s.fillChildren(order)
 }
 func (s *store) GetOrderBySwapID(swapID uint) (*model.Order, error) {
 	order := &model.Order{
@@ -678,16 +818,15 @@ func (s *store) GetOrderBySwapID(swapID uint) (*model.Order, error) {
 // update the given order and the internal atomic swap objects on the db
 // @dev should only be used internally and cannot be called by an end user
 func (s *store) UpdateOrder(order *model.Order) error {
-	if tx := s.db.Save(order.FollowerAtomicSwap); tx.Error != nil {
-		return tx.Error
-	}
-	if tx := s.db.Save(order.InitiatorAtomicSwap); tx.Error != nil {
-		return tx.Error
-	}
-	if tx := s.db.Save(order); tx.Error != nil {
-		return tx.Error
-	}
-	return nil
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveSwap(tx, order.FollowerAtomicSwap, "watcher:"+string(order.FollowerAtomicSwap.Chain)); err != nil {
+			return err
+		}
+		if err := saveSwap(tx, order.InitiatorAtomicSwap, "watcher:"+string(order.InitiatorAtomicSwap.Chain)); err != nil {
+			return err
+		}
+		return saveOrder(tx, order, "watcher:orders")
+	})
 }
 
 // fills the atomic swap objects in the given order
@@ -707,6 +846,10 @@ func (s *store) Gorm() *gorm.DB {
 	return s.db
 }
 
+func (s *store) Prices() *price.Cache {
+	return s.prices
+}
+
 func GetSwapId(Chain model.Chain, InitiatorAddress string, RedeemerAddress string, Timelock string, SecretHash string) (string, error) {
 	secHash, err := hex.DecodeString(SecretHash)
 	if err != nil {
@@ -804,36 +947,6 @@ func GetMinConfirmations(value *big.Int, chain model.Chain, isFollower bool) uin
 	return 0
 }
 
-type Price struct {
-	Price     float64
-	Timestamp int64
-}
-
-func GetPrice(oracle string) (Price, error) {
-	resp, err := http.Get(oracle)
-	if err != nil {
-		return Price{}, fmt.Errorf("failed to build get request: %v", err)
-	}
-	defer resp.Body.Close()
-	var apiResponse struct {
-		Data      map[string]interface{} `json:"data"`
-		Timestamp int64                  `json:"timestamp"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return Price{}, fmt.Errorf("failed to decode response: %v", err)
-	}
-	priceUsdStr, ok := apiResponse.Data["priceUsd"].(string)
-	if !ok {
-		return Price{}, fmt.Errorf("failed to parse price from: %v", apiResponse.Data)
-	}
-	priceUsd, err := strconv.ParseFloat(priceUsdStr, 64)
-	if err != nil {
-		return Price{}, fmt.Errorf("failed to convert priceUsd to float64: %v", err)
-	}
-	return Price{priceUsd, apiResponse.Timestamp}, nil
-}
-
 func getParams(chain model.Chain) *chaincfg.Params {
 	switch chain {
 	case model.Bitcoin:
@@ -3,14 +3,12 @@ package store_test
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"os"
+	"math/big"
 
-	"github.com/catalogfi/orderbook/internal/path"
 	"github.com/catalogfi/orderbook/model"
 	. "github.com/catalogfi/orderbook/store"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -19,12 +17,22 @@ import (
 // 	model.Ethereum: "http://127.0.0.1:8545",
 // }
 
+// prices are static so that the tests do not depend on a price api
+const testOracle = "static:40000"
+
+// amounts are parsed by the rest server, an invalid amount reaches the store as zero
+func amount(value string) *big.Int {
+	amount, ok := new(big.Int).SetString(value, 10)
+	Expect(ok).To(BeTrue())
+	return amount
+}
+
 var config = model.Config{
 	Network: model.Network{
 		"bitcoin_testnet": model.NetworkConfig{
 			Assets: map[model.Asset]model.Token{
 				model.Primary: {
-					Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+					Oracle:   testOracle,
 					Decimals: 8,
 				},
 			},
@@ -33,16 +41,17 @@ var config = model.Config{
 		"ethereum_sepolia": model.NetworkConfig{
 			Assets: map[model.Asset]model.Token{
 				model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-					Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+					Oracle:       testOracle,
 					TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 					Decimals:     8,
 				}},
 			RPC:    map[string]string{"ethrpc": "https://gateway.tenderly.co/public/sepolia"},
 			Expiry: 0},
 	},
-	DailyLimit: "350000",
 	MinTxLimit: "3000",
 	MaxTxLimit: "10000000000",
+	// 5 BTC at the price of the test oracle
+	DailyUSDLimit: "200000",
 }
 
 var secretHash string
@@ -55,16 +64,16 @@ var _ = BeforeEach(func() {
 
 var _ = Describe("Store", func() {
 	It("should be able to get locked amount", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F2db", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F2db", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 
-		initiatorUnfilledOrders, err := store.FilterOrders("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "", "", model.Created, 0, 0, 0, 0, 0, 0, true)
+		initiatorUnfilledOrders, _, err := store.FilterOrders(model.OrderFilter{Maker: "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", OrderPair: "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", Status: model.Created, Verbose: true})
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(len(initiatorUnfilledOrders)).Should(BeNumerically(">", 0))
@@ -74,7 +83,7 @@ var _ = Describe("Store", func() {
 
 		store.UpdateOrder(&order)
 
-		followerUnfilledOrders, err := store.FilterOrders("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "", "", model.Status(1), 0, 0, 0, 0, 0, 0, true)
+		followerUnfilledOrders, _, err := store.FilterOrders(model.OrderFilter{Maker: "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", OrderPair: "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", Status: model.Status(1), Verbose: true})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(followerUnfilledOrders)).Should(BeNumerically(">", 0))
 
@@ -84,20 +93,20 @@ var _ = Describe("Store", func() {
 		_, err = store.ValueLockedByChain(model.Ethereum, config.Network)
 		Expect(err).NotTo(HaveOccurred())
 
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 	It("Error, when using invalid AtomicSwap address", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eD", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eD", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
 	})
 
 	It("should be able to fill an order", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).NotTo(HaveOccurred())
@@ -111,13 +120,13 @@ var _ = Describe("Store", func() {
 		// Expect(order.InitiatorAtomicSwap.Timelock).To(Equal(uint64(144)))
 		// Expect(order.FollowerAtomicSwap.Timelock).To(Equal(uint64(144)))
 		Expect(order.Status).To(Equal(model.Filled))
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("should be able to cancel an order", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		cid, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		cid, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order, err := store.GetOrder(cid)
 		Expect(err).NotTo(HaveOccurred())
@@ -126,13 +135,13 @@ var _ = Describe("Store", func() {
 		Expect(err).NotTo(HaveOccurred())
 		_, err = store.GetOrder(cid)
 		Expect(err).To(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	// It("shouldn't be able to cancel a filled order", func() {
-	// 	store, err := New(sqlite.Open("test.db"),path.SQLSetupPath, &gorm.Config{})
+	// 	store, err := New(testDialector(),path.SQLSetupPath, &gorm.Config{})
 	// 	Expect(err).NotTo(HaveOccurred())
-	// 	cid, err := store.CreateOrder("creator", "sendAddress", "receiveAddress", "ETH:ETH-BTC:BTC", "100", "200", "secretHash", "receivebtcAddress", config)
+	// 	cid, err := store.CreateOrder("creator", "sendAddress", "receiveAddress", "ETH:ETH-BTC:BTC", "secretHash", "receivebtcAddress", amount("100"), amount("200"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 	// 	Expect(err).NotTo(HaveOccurred())
 	// 	err = store.FillOrder(cid, "filler", "sendFollowerAddress", "reciveFollowerAddress", config.Network)
 	// 	Expect(err).NotTo(HaveOccurred())
@@ -141,22 +150,22 @@ var _ = Describe("Store", func() {
 	// 	Expect(order.ID).To(Equal(cid))
 	// 	err = store.CancelOrder("creator", cid)
 	// 	Expect(err).To(HaveOccurred())
-	// 	Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+	// 	Expect(dropTestDB()).To(Succeed())
 	// })
 
 	It("should be able to get all open orders", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		cid1, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		cid1, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 
-		cid2, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F3dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		cid2, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F3dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 
-		cid3, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F4dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		cid3, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F4dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F5dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F5dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 
 		orders, err := store.GetActiveOrders()
@@ -183,113 +192,113 @@ var _ = Describe("Store", func() {
 		orders, err = store.GetActiveOrders()
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(orders)).To(Equal(4))
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, shoudl happen cause it crosses daily amount value", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "10000000000000", "1000000000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F3dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F3dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("350000000"), amount("350000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F4dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F872E0F4dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, creating order with amount less than MinTxlimit", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "1000", "1000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("1000"), amount("1000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 	It("Error, creating order with amount less than MaxTxlimit", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "10000000000000000000000", "10000000000000000000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("10000000000000000000000"), amount("10000000000000000000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Error, giving wrong creator address", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58a5B8f9872E0", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58a5B8f9872E0", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong unsupported chain format", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "shinto/ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "shinto/ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong send send chain", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoi_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoi_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong recive chain", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereu_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereu_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong send address", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJa", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJa", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong receive address", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F8", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F8", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong recive chain", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong send amount", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "1,00000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("0"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, giving wrong receive amount", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "1,00000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("0"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, manually changing the daily limit to a wrong value", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		var tconfig = model.Config{
 			Network: model.Network{
@@ -297,7 +306,7 @@ var _ = Describe("Store", func() {
 				"bitcoin_testnet": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.Primary: {
-							Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:   testOracle,
 							Decimals: 8,
 						},
 					},
@@ -307,7 +316,7 @@ var _ = Describe("Store", func() {
 				"ethereum_sepolia": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-							Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:       testOracle,
 							TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 							Decimals:     8,
 						}},
@@ -317,17 +326,17 @@ var _ = Describe("Store", func() {
 					RPC:    map[string]string{"ethrpc": "https://mainnet.infura.io/v3/47b89f1cf0cd47419f9a57674278610b"},
 					Expiry: 0},
 			},
-			DailyLimit: "3,50000",
-			MinTxLimit: "3000",
-			MaxTxLimit: "10000000000",
+			DailyUSDLimit: "3,50000",
+			MinTxLimit:    "3000",
+			MaxTxLimit:    "10000000000",
 		}
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", tconfig)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, tconfig)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, manually changing the Min limit to a wrong value", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		var tconfig = model.Config{
 			Network: model.Network{
@@ -335,7 +344,7 @@ var _ = Describe("Store", func() {
 				"bitcoin_testnet": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.Primary: {
-							Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:   testOracle,
 							Decimals: 8,
 						},
 					},
@@ -345,7 +354,7 @@ var _ = Describe("Store", func() {
 				"ethereum_sepolia": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-							Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:       testOracle,
 							TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 							Decimals:     8,
 						}},
@@ -355,16 +364,15 @@ var _ = Describe("Store", func() {
 					RPC:    map[string]string{"ethrpc": "https://mainnet.infura.io/v3/47b89f1cf0cd47419f9a57674278610b"},
 					Expiry: 0},
 			},
-			DailyLimit: "350000",
 			MinTxLimit: "3,000",
 			MaxTxLimit: "10000000000",
 		}
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", tconfig)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, tconfig)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 	It("Error, manually changing the Max limit to a wrong value", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		var tconfig = model.Config{
 			Network: model.Network{
@@ -372,7 +380,7 @@ var _ = Describe("Store", func() {
 				"bitcoin_testnet": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.Primary: {
-							Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:   testOracle,
 							Decimals: 8,
 						},
 					},
@@ -382,7 +390,7 @@ var _ = Describe("Store", func() {
 				"ethereum_sepolia": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-							Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:       testOracle,
 							TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 							Decimals:     8,
 						}},
@@ -392,41 +400,40 @@ var _ = Describe("Store", func() {
 					RPC:    map[string]string{"ethrpc": "https://mainnet.infura.io/v3/47b89f1cf0cd47419f9a57674278610b"},
 					Expiry: 0},
 			},
-			DailyLimit: "350000",
 			MinTxLimit: "3000",
 			MaxTxLimit: "1,0,000000000",
 		}
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", tconfig)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, tconfig)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, fill order sender addreses wrong", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).Should(HaveOccurred())
 
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, fill order reciever address wrong", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSj", config.Network)
 		Expect(err).Should(HaveOccurred())
 
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, changing the order after creation", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order1, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
@@ -437,19 +444,19 @@ var _ = Describe("Store", func() {
 
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bc1qznd382dqapcp0j2xf5jyu548g55743jy3ywwqc", config.Network)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, trying to fill an non-existent order", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(5, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, trying to fill an order with wrong config file for sendChain", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		var tconfig = model.Config{
 			Network: model.Network{
@@ -457,7 +464,7 @@ var _ = Describe("Store", func() {
 				"bitcoin_testnet": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.Primary: {
-							Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:   testOracle,
 							Decimals: 8,
 						},
 					},
@@ -467,7 +474,7 @@ var _ = Describe("Store", func() {
 				"ethereum_sepolia": model.NetworkConfig{
 					// Assets: map[model.Asset]model.Token{
 					// 	model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-					// 		Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+					// 		Oracle:       testOracle,
 					// 		TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 					// 		Decimals:     8,
 					// 	}},
@@ -477,11 +484,10 @@ var _ = Describe("Store", func() {
 					RPC:    map[string]string{"ethrpc": "https://mainnet.infura.io/v3/47b89f1cf0cd47419f9a57674278610b"},
 					Expiry: 0},
 			},
-			DailyLimit: "350000",
 			MinTxLimit: "3000",
 			MaxTxLimit: "10000000000",
 		}
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order1, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
@@ -490,11 +496,11 @@ var _ = Describe("Store", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", tconfig.Network)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 	It("Error, trying to fill an order with wrong config file for recieverChain", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		var tconfig = model.Config{
 			Network: model.Network{
@@ -502,7 +508,7 @@ var _ = Describe("Store", func() {
 				"bitcoin_testnet": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.Primary: {
-							Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:   testOracle,
 							Decimals: 8,
 						},
 					},
@@ -512,7 +518,7 @@ var _ = Describe("Store", func() {
 				"ethereum_sepolia": model.NetworkConfig{
 					// Assets: map[model.Asset]model.Token{
 					// 	model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-					// 		Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+					// 		Oracle:       testOracle,
 					// 		TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 					// 		Decimals:     8,
 					// 	}},
@@ -522,11 +528,10 @@ var _ = Describe("Store", func() {
 					RPC:    map[string]string{"ethrpc": "https://mainnet.infura.io/v3/47b89f1cf0cd47419f9a57674278610b"},
 					Expiry: 0},
 			},
-			DailyLimit: "350000",
 			MinTxLimit: "3000",
 			MaxTxLimit: "10000000000",
 		}
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order1, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
@@ -535,35 +540,35 @@ var _ = Describe("Store", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", tconfig.Network)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Error, trying to cancel order by another creator", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.CancelOrder("mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", id)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 	It("Error, trying to cancel order which doesnt exist", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.CancelOrder("mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", 5)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Error, trying to cancel a filled order", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order1, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
@@ -572,14 +577,14 @@ var _ = Describe("Store", func() {
 		Expect(err).NotTo(HaveOccurred())
 		err = store.CancelOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", id)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Trying to filter order with all the details", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).NotTo(HaveOccurred())
@@ -591,21 +596,21 @@ var _ = Describe("Store", func() {
 		err = store.UpdateOrder(order1)
 		Expect(err).NotTo(HaveOccurred())
 
-		orders, err := store.FilterOrders("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "", model.Status(2), float64(0.5), float64(10000), float64(0.5), float64(100000), 1, 1, true)
+		orders, _, err := store.FilterOrders(model.OrderFilter{Maker: "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", Taker: "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", OrderPair: "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", SecretHash: secretHash, Status: model.Status(2), MinPrice: 0.5, MaxPrice: 10000, MinAmount: 0.5, MaxAmount: 100000, Page: 1, PerPage: 1, Verbose: true})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(orders)).Should(BeNumerically(">=", 0))
-		orders1, err := store.FilterOrders("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "", model.Status(2), float64(0.5), float64(10000), float64(0.5), float64(100000), 0, 0, true)
+		orders1, _, err := store.FilterOrders(model.OrderFilter{Maker: "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", Taker: "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", OrderPair: "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", SecretHash: secretHash, Status: model.Status(2), MinPrice: 0.5, MaxPrice: 10000, MinAmount: 0.5, MaxAmount: 100000, Verbose: true})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(orders1)).Should(BeNumerically(">=", 0))
-		orders2, err := store.FilterOrders("", "", "", "", "-status,-price,id", 1, 0, 0, 0, 0, 0, 0, true)
+		orders2, _, err := store.FilterOrders(model.OrderFilter{Status: 1, Verbose: true})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(orders2)).Should(BeNumerically(">=", 0))
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Error, failed to get send price", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		var tconfig = model.Config{
 			Network: model.Network{
@@ -613,7 +618,7 @@ var _ = Describe("Store", func() {
 				"bitcoin_testnet": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.Primary: {
-							Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:   testOracle,
 							Decimals: 8,
 						},
 					},
@@ -623,7 +628,7 @@ var _ = Describe("Store", func() {
 				"ethereum_sepolia": model.NetworkConfig{
 					// Assets: map[model.Asset]model.Token{
 					// 	model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-					// 		Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+					// 		Oracle:       testOracle,
 					// 		TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 					// 		Decimals:     8,
 					// 	}},
@@ -633,18 +638,17 @@ var _ = Describe("Store", func() {
 					RPC:    map[string]string{"ethrpc": "https://mainnet.infura.io/v3/47b89f1cf0cd47419f9a57674278610b"},
 					Expiry: 0},
 			},
-			DailyLimit: "350000",
 			MinTxLimit: "3000",
 			MaxTxLimit: "10000000000",
 		}
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", tconfig)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, tconfig)
 		Expect(err).Should(HaveOccurred())
 
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, failed to get receive price", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
 		var tconfig = model.Config{
 			Network: model.Network{
@@ -652,7 +656,7 @@ var _ = Describe("Store", func() {
 				"bitcoin_testnet": model.NetworkConfig{
 					// Assets: map[model.Asset]model.Token{
 					// 	model.Primary: {
-					// 		Oracle:   "https://api.coincap.io/v2/assets/bitcoin",
+					// 		Oracle:   testOracle,
 					// 		Decimals: 8,
 					// 	},
 					// },
@@ -662,7 +666,7 @@ var _ = Describe("Store", func() {
 				"ethereum_sepolia": model.NetworkConfig{
 					Assets: map[model.Asset]model.Token{
 						model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {
-							Oracle:       "https://api.coincap.io/v2/assets/bitcoin",
+							Oracle:       testOracle,
 							TokenAddress: "0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF",
 							Decimals:     8,
 						}},
@@ -672,19 +676,18 @@ var _ = Describe("Store", func() {
 					RPC:    map[string]string{"ethrpc": "https://mainnet.infura.io/v3/47b89f1cf0cd47419f9a57674278610b"},
 					Expiry: 0},
 			},
-			DailyLimit: "350000",
 			MinTxLimit: "3000",
 			MaxTxLimit: "10000000000",
 		}
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", tconfig)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, tconfig)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Error, if Amount is corrupted", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order1, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
@@ -698,36 +701,36 @@ var _ = Describe("Store", func() {
 
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", config.Network)
 		Expect(err).Should(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 	It("Error, creating order with wrong config file", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", model.Config{})
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF-bitcoin_testnet", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, model.Config{})
 		Expect(err).Should(HaveOccurred())
 
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 	})
 
 	It("Creating filling and then creating with same address", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id1, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id1, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id1, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F873E0F2dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F873E0F2dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Error, deleting database after creating and then trying to fill", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id1, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id1, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 		err = store.FillOrder(id1, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).Should(HaveOccurred())
@@ -735,24 +738,24 @@ var _ = Describe("Store", func() {
 	})
 
 	It("Error, creating order in a deleted database", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F873E0F2dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		Expect(dropTestDB()).To(Succeed())
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2daE6B5ca5B8f9Ec6F873E0F2dc", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).Should(HaveOccurred())
 
 	})
 
 	It("Error, updating order in a deleted database", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order1, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 		orderp := "bitcoin/optimism"
 		order1.OrderPair = orderp
 		err = store.UpdateOrder(order1)
@@ -760,44 +763,47 @@ var _ = Describe("Store", func() {
 	})
 
 	It("Error, attempting to cancel order when Db is deleted", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 		err = store.CancelOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", id)
 		Expect(err).Should(HaveOccurred())
 
 	})
 
 	It("Getting order by the specifying address", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		orders, err := store.GetOrdersByAddress("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da")
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(orders)).Should(Equal(1))
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Getting active swaps", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		// only swaps of filled orders are watched
+		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).NotTo(HaveOccurred())
 		swaps, err := store.GetActiveSwaps(model.EthereumSepolia)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(swaps)).Should(Equal(1))
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Updating a swap", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
@@ -807,14 +813,14 @@ var _ = Describe("Store", func() {
 		order, err = store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(order.InitiatorAtomicSwap.FilledAmount).Should(Equal("1000"))
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 
 	It("Getting the database", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		_, err = store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		_, err = store.Gorm().DB()
 		Expect(err).NotTo(HaveOccurred())
@@ -822,14 +828,14 @@ var _ = Describe("Store", func() {
 	})
 
 	It("Error, Updating a swap in a deleted database", func() {
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		order, err := store.GetOrder(id)
 		Expect(err).NotTo(HaveOccurred())
 		order.InitiatorAtomicSwap.FilledAmount = "1000"
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 		err = store.UpdateSwap(order.InitiatorAtomicSwap)
 		Expect(err).Should(HaveOccurred())
@@ -838,9 +844,9 @@ var _ = Describe("Store", func() {
 
 	It("Getting swap usng onchain indentifiers", func() {
 
-		store, err := New(sqlite.Open("test.db"), path.SQLSetupPath, &gorm.Config{})
+		store, err := New(testDialector(), &gorm.Config{})
 		Expect(err).NotTo(HaveOccurred())
-		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", "100000000", "100000000", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config)
+		id, err := store.CreateOrder("0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "bitcoin_testnet-ethereum_sepolia:0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF", secretHash, "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", amount("100000000"), amount("100000000"), big.NewInt(0), big.NewInt(0), false, model.OrderTerms{}, config)
 		Expect(err).NotTo(HaveOccurred())
 		err = store.FillOrder(id, "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "0x17100301bB2FF58aE6B5ca5B8f9Ec6F872E0F2da", "mg54DDo5jfNkx5tF4d7Ag6G6VrJaSjr7ES", config.Network)
 		Expect(err).NotTo(HaveOccurred())
@@ -856,7 +862,7 @@ var _ = Describe("Store", func() {
 		swap, err := store.SwapByOCID("16345785D8A0000")
 		Expect(err).NotTo(HaveOccurred())
 		Expect(swap.Chain).Should(Equal(model.EthereumSepolia))
-		Expect(os.Remove("test.db")).NotTo(HaveOccurred())
+		Expect(dropTestDB()).To(Succeed())
 
 	})
 })
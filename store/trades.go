@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// records the trades of the orders executed since the given time, and removes the
+// trades of orders which are no longer executed after a reorg. Orders without a
+// valid trade are skipped.
+func (s *store) recordTrades(since time.Time, config model.Network) error {
+	orders := []model.Order{}
+	if err := s.db.Unscoped().Preload("InitiatorAtomicSwap").Preload("FollowerAtomicSwap").
+		Where("updated_at >= ? OR deleted_at >= ?", since, since).
+		Find(&orders).Error; err != nil {
+		return err
+	}
+	trades := []model.Trade{}
+	reverted := []uint{}
+	for _, order := range orders {
+		// the parts of a partially fillable order are its trades
+		if order.Partial {
+			continue
+		}
+		if order.Status != model.Executed || order.DeletedAt.Valid {
+			reverted = append(reverted, order.ID)
+			continue
+		}
+		trade, err := model.NewTrade(order, config)
+		if err != nil {
+			// a corrupt order must not stall the trades and rollups of the others
+			s.db.Logger.Warn(context.Background(), "skipping the trade of order %d: %v", order.ID, err)
+			continue
+		}
+		trades = append(trades, trade)
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if len(reverted) > 0 {
+			if err := tx.Where("order_id IN ?", reverted).Delete(&model.Trade{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(trades) == 0 {
+			return nil
+		}
+		// a trade keeps the time it was first recorded with
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&trades).Error
+	})
+}
+
+// Trades returns the most recent trades of a pair, or of all pairs if it is empty,
+// executed in [from, to), newest first
+func (s *store) Trades(orderPair string, from, to time.Time, limit int) ([]model.Trade, error) {
+	tx := s.db.Where("executed_at >= ? AND executed_at < ?", from, to)
+	if orderPair != "" {
+		tx = tx.Where("order_pair = ?", orderPair)
+	}
+	trades := []model.Trade{}
+	if err := tx.Order("executed_at DESC, order_id DESC").Limit(limit).Find(&trades).Error; err != nil {
+		return nil, err
+	}
+	for i := range trades {
+		trades[i].ExecutedAt = trades[i].ExecutedAt.UTC()
+	}
+	return trades, nil
+}
+
+// Candles aggregates the trades of a pair executed in [from, to) into candles,
+// intervals without trades are omitted
+func (s *store) Candles(orderPair string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error) {
+	trades := []model.Trade{}
+	if err := s.db.Where("order_pair = ? AND executed_at >= ? AND executed_at < ?", orderPair, from, to).
+		Order("executed_at ASC, order_id ASC").
+		Find(&trades).Error; err != nil {
+		return nil, err
+	}
+	return model.BuildCandles(trades, interval), nil
+}
@@ -0,0 +1,64 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Trades", func() {
+	pair := testPair
+	token := model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+
+	It("should record the trades of executed orders", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		trade := func(sendAmount, receiveAmount string, status model.Status) model.Order {
+			initiator := model.AtomicSwap{Chain: model.BitcoinTestnet, Asset: model.Primary, Amount: sendAmount}
+			follower := model.AtomicSwap{Chain: model.EthereumSepolia, Asset: token, Amount: receiveAmount}
+			return insertOrder(store, model.Order{Status: status}, initiator, follower)
+		}
+		executed := trade("100000000", "99000000", model.Executed)
+		trade("100000000", "101000000", model.Executed)
+		trade("100000000", "100000000", model.Filled)
+		// orders without a valid trade are skipped
+		trade("0", "100000000", model.Executed)
+		Expect(store.RollupOrders(config.Network)).To(Succeed())
+
+		from, to := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+		trades, err := store.Trades(pair, from, to, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trades).To(HaveLen(2))
+		Expect(trades[0].Price.String()).To(Equal("1.01"))
+		Expect(trades[1].Price.String()).To(Equal("0.99"))
+		Expect(trades[1].SendAmount.String()).To(Equal("1"))
+
+		trades, err = store.Trades("", from, to, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trades).To(HaveLen(1))
+
+		candles, err := store.Candles(pair, model.Day1, from, to)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(candles).NotTo(BeEmpty())
+		volume := model.Decimal{}
+		for _, candle := range candles {
+			volume = volume.Add(candle.Volume)
+		}
+		Expect(volume.String()).To(Equal("2"))
+
+		// trades of orders moved back by a reorg are removed
+		executed.Status = model.Filled
+		Expect(store.Gorm().Save(&executed).Error).NotTo(HaveOccurred())
+		Expect(store.RollupOrders(config.Network)).To(Succeed())
+		trades, err = store.Trades(pair, from, to, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(trades).To(HaveLen(1))
+		Expect(trades[0].Price.String()).To(Equal("1.01"))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+)
+
+type usdLimit struct {
+	name   string
+	window time.Duration
+	limit  model.Decimal
+}
+
+// parses the configured usd limits, windows without a limit are skipped
+func usdLimits(config model.Config) ([]usdLimit, error) {
+	limits := []usdLimit{}
+	for _, limit := range []struct {
+		name   string
+		window time.Duration
+		value  string
+	}{
+		{"24h", 24 * time.Hour, config.DailyUSDLimit},
+		{"7d", 7 * 24 * time.Hour, config.WeeklyUSDLimit},
+		{"30d", 30 * 24 * time.Hour, config.MonthlyUSDLimit},
+	} {
+		if limit.value == "" {
+			continue
+		}
+		value, err := model.NewDecimal(limit.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s usd limit: %v", limit.name, limit.value)
+		}
+		limits = append(limits, usdLimit{name: limit.name, window: limit.window, limit: value})
+	}
+	return limits, nil
+}
+
+// checks if sending the given swap keeps the value sent by the user within the
+// usd limits, every window ends now and is valued with the oracle price stored on the swaps
+func (s *store) checkUSDLimits(user string, swap model.AtomicSwap, config model.Config) error {
+	limits, err := usdLimits(config)
+	if err != nil || len(limits) == 0 {
+		return err
+	}
+	longest := time.Duration(0)
+	for _, limit := range limits {
+		if limit.window > longest {
+			longest = limit.window
+		}
+	}
+
+	now := time.Now().UTC()
+	orders := []model.Order{}
+	// the parts of a partially fillable order are valued with the order for its maker
+	if tx := s.db.Where("((maker = ? AND parent_order_id IS NULL) OR taker = ?) AND status >= ? AND status < ? AND created_at >= ?", user, user, model.Created, model.FailedSoft, now.Add(-longest)).Find(&orders); tx.Error != nil {
+		return tx.Error
+	}
+	swapIDs := make([]uint, len(orders))
+	for i, order := range orders {
+		swapIDs[i] = order.InitiatorAtomicSwapID
+		if order.Taker == user {
+			swapIDs[i] = order.FollowerAtomicSwapID
+		}
+	}
+	swaps := []model.AtomicSwap{}
+	if len(swapIDs) > 0 {
+		if tx := s.db.Find(&swaps, swapIDs); tx.Error != nil {
+			return tx.Error
+		}
+	}
+	swapsByID := make(map[uint]model.AtomicSwap, len(swaps))
+	for _, swap := range swaps {
+		swapsByID[swap.ID] = swap
+	}
+
+	for _, limit := range limits {
+		sent := []model.AtomicSwap{swap}
+		for i, order := range orders {
+			sentSwap, ok := swapsByID[swapIDs[i]]
+			if !ok || order.CreatedAt.Before(now.Add(-limit.window)) {
+				continue
+			}
+			sent = append(sent, sentSwap)
+		}
+		value, err := s.usdValue(sent, config.Network)
+		if err != nil {
+			return err
+		}
+		if value.Cmp(limit.limit) > 0 {
+			return fmt.Errorf("reached %s usd limit, limit : %s, current value : %s", limit.name, limit.limit, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package store_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("USD limits", func() {
+	var (
+		oracle    *httptest.Server
+		usdConfig model.Config
+	)
+
+	BeforeEach(func() {
+		oracle = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"data":{"priceUsd":"40000"},"timestamp":%d}`, time.Now().Unix())
+		}))
+		usdConfig = model.Config{
+			Network: model.Network{
+				model.BitcoinTestnet: model.NetworkConfig{
+					Assets: map[model.Asset]model.Token{
+						model.Primary: {Oracle: oracle.URL, Decimals: 8},
+					},
+				},
+				model.EthereumSepolia: model.NetworkConfig{
+					Assets: map[model.Asset]model.Token{
+						model.NewSecondary("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF"): {Oracle: oracle.URL, Decimals: 8},
+					},
+				},
+			},
+			DailyUSDLimit:  "100000",
+			WeeklyUSDLimit: "150000",
+		}
+	})
+
+	AfterEach(func() {
+		oracle.Close()
+	})
+
+	// an order sending 1 BTC, valued at 40000 USD
+	usdOrder := testOrder{ReceiveAmount: "100000000", Config: &usdConfig}
+
+	It("should enforce the limits over rolling windows", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		Expect(createOrder(store, usdOrder)).Error().To(MatchError(ContainSubstring("reached 24h usd limit")))
+
+		// orders older than a day only count towards the weekly limit
+		Expect(store.Gorm().Model(&model.Order{}).Where("1 = 1").Update("created_at", time.Now().UTC().Add(-25*time.Hour)).Error).NotTo(HaveOccurred())
+		Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		Expect(createOrder(store, usdOrder)).Error().To(MatchError(ContainSubstring("reached 7d usd limit")))
+
+		Expect(store.Gorm().Model(&model.Order{}).Where("1 = 1").Update("created_at", time.Now().UTC().Add(-8*24*time.Hour)).Error).NotTo(HaveOccurred())
+		Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should not count cancelled orders", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		Expect(store.Gorm().Model(&model.Order{}).Where("1 = 1").Update("status", model.Cancelled).Error).NotTo(HaveOccurred())
+		Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should apply the usd limits of a user limit", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.GrantUserLimit(&model.UserLimit{
+			Wallet:         testMaker,
+			DailyUSDLimit:  "200000",
+			WeeklyUSDLimit: "200000",
+			Expiry:         time.Now().Add(time.Hour),
+			GrantedBy:      testMaker,
+			Reason:         "market maker",
+		})).To(Succeed())
+		for i := 0; i < 5; i++ {
+			Expect(createOrder(store, usdOrder)).Error().To(Succeed())
+		}
+		Expect(createOrder(store, usdOrder)).Error().To(MatchError(ContainSubstring("reached 24h usd limit")))
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should reject invalid limits", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		usdConfig.MonthlyUSDLimit = "1,000"
+		Expect(createOrder(store, usdOrder)).Error().To(MatchError(ContainSubstring("invalid 30d usd limit")))
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	"gorm.io/gorm"
+)
+
+// grant a limit override to a wallet, the issuer and the reason are recorded with it
+func (s *store) GrantUserLimit(limit *model.UserLimit) error {
+	limit.Wallet = strings.ToLower(limit.Wallet)
+	limit.GrantedBy = strings.ToLower(limit.GrantedBy)
+	limit.OrderPair = strings.ToLower(limit.OrderPair)
+	if err := CheckAddress(model.Ethereum, limit.Wallet); err != nil {
+		return fmt.Errorf("invalid wallet: %v", err)
+	}
+	if limit.OrderPair != "" {
+		if _, _, _, _, err := model.ParseOrderPair(limit.OrderPair); err != nil {
+			return err
+		}
+	}
+	for name, amount := range map[string]string{"min tx limit": limit.MinTxLimit, "max tx limit": limit.MaxTxLimit} {
+		if amount == "" {
+			continue
+		}
+		if value, ok := new(big.Int).SetString(amount, 10); !ok || value.Sign() < 0 {
+			return fmt.Errorf("invalid %s: %s", name, amount)
+		}
+	}
+	for name, amount := range map[string]string{"daily usd limit": limit.DailyUSDLimit, "weekly usd limit": limit.WeeklyUSDLimit, "monthly usd limit": limit.MonthlyUSDLimit} {
+		if amount == "" {
+			continue
+		}
+		if value, err := model.NewDecimal(amount); err != nil || value.Sign() < 0 {
+			return fmt.Errorf("invalid %s: %s", name, amount)
+		}
+	}
+	if limit.MinTxLimit == "" && limit.MaxTxLimit == "" && limit.DailyUSDLimit == "" && limit.WeeklyUSDLimit == "" && limit.MonthlyUSDLimit == "" {
+		return fmt.Errorf("limit does not override anything")
+	}
+	if !limit.Expiry.After(time.Now().UTC()) {
+		return fmt.Errorf("limit has already expired")
+	}
+	if limit.GrantedBy == "" || limit.Reason == "" {
+		return fmt.Errorf("granted by and reason are required")
+	}
+	limit.ID = 0
+	limit.RevokedBy = ""
+	if tx := s.db.Create(limit); tx.Error != nil {
+		return tx.Error
+	}
+	return nil
+}
+
+// get the limit overrides of the given wallet, or of all wallets if it is empty,
+// revoked and expired limits are included when history is set
+func (s *store) GetUserLimits(wallet string, history bool) ([]model.UserLimit, error) {
+	limits := []model.UserLimit{}
+	tx := s.db
+	if history {
+		tx = tx.Unscoped()
+	} else {
+		tx = tx.Where("expiry > ?", time.Now().UTC())
+	}
+	if wallet != "" {
+		tx = tx.Where("wallet = ?", strings.ToLower(wallet))
+	}
+	if tx = tx.Order("id DESC").Find(&limits); tx.Error != nil {
+		return nil, tx.Error
+	}
+	return limits, nil
+}
+
+// revoke the given limit override, the limit is kept for auditing
+func (s *store) RevokeUserLimit(id uint, revokedBy string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		limit := model.UserLimit{}
+		if err := tx.First(&limit, id).Error; err != nil {
+			return err
+		}
+		limit.RevokedBy = strings.ToLower(revokedBy)
+		if err := tx.Save(&limit).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&limit).Error
+	})
+}
+
+// overrides the trade limits in the given config with the active limit of the
+// given wallet, limits scoped to the order pair take precedence over global ones
+func (s *store) applyUserLimits(wallet, orderPair string, config model.Config) (model.Config, error) {
+	limits := []model.UserLimit{}
+	if tx := s.db.Where("wallet = ? AND (order_pair = '' OR order_pair = ?) AND expiry > ?", strings.ToLower(wallet), strings.ToLower(orderPair), time.Now().UTC()).
+		Order("order_pair DESC, id DESC").Limit(1).Find(&limits); tx.Error != nil {
+		return config, tx.Error
+	}
+	if len(limits) == 0 {
+		return config, nil
+	}
+	if limits[0].MinTxLimit != "" {
+		config.MinTxLimit = limits[0].MinTxLimit
+	}
+	if limits[0].MaxTxLimit != "" {
+		config.MaxTxLimit = limits[0].MaxTxLimit
+	}
+	if limits[0].DailyUSDLimit != "" {
+		config.DailyUSDLimit = limits[0].DailyUSDLimit
+	}
+	if limits[0].WeeklyUSDLimit != "" {
+		config.WeeklyUSDLimit = limits[0].WeeklyUSDLimit
+	}
+	if limits[0].MonthlyUSDLimit != "" {
+		config.MonthlyUSDLimit = limits[0].MonthlyUSDLimit
+	}
+	return config, nil
+}
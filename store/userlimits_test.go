@@ -0,0 +1,79 @@
+package store_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/model"
+	. "github.com/catalogfi/orderbook/store"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("User limits", func() {
+	wallet := "0xE103abfa0f867e53cef1ad2cb0dcbc193b385a93"
+	admin := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+
+	It("should grant, list and revoke limits", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		limit := model.UserLimit{
+			Wallet:        wallet,
+			DailyUSDLimit: "250000",
+			Expiry:        time.Now().Add(24 * time.Hour),
+			GrantedBy:     admin,
+			Reason:        "market maker onboarding",
+		}
+		Expect(store.GrantUserLimit(&limit)).To(Succeed())
+		Expect(limit.ID).NotTo(BeZero())
+
+		limits, err := store.GetUserLimits(wallet, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(HaveLen(1))
+		Expect(limits[0].Wallet).To(Equal("0xe103abfa0f867e53cef1ad2cb0dcbc193b385a93"))
+		Expect(limits[0].GrantedBy).To(Equal(admin))
+		Expect(limits[0].Reason).To(Equal("market maker onboarding"))
+
+		Expect(store.RevokeUserLimit(limit.ID, admin)).To(Succeed())
+		limits, err = store.GetUserLimits(wallet, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(BeEmpty())
+
+		// revoked limits are kept for auditing
+		limits, err = store.GetUserLimits("", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(limits).To(HaveLen(1))
+		Expect(limits[0].RevokedBy).To(Equal(admin))
+		Expect(limits[0].DeletedAt.Valid).To(BeTrue())
+		Expect(dropTestDB()).To(Succeed())
+	})
+
+	It("should reject invalid limits", func() {
+		store, err := New(testDialector(), &gorm.Config{})
+		Expect(err).NotTo(HaveOccurred())
+
+		valid := model.UserLimit{
+			Wallet:     wallet,
+			MaxTxLimit: "100000000",
+			Expiry:     time.Now().Add(time.Hour),
+			GrantedBy:  admin,
+			Reason:     "vip",
+		}
+		for _, modify := range []func(limit *model.UserLimit){
+			func(limit *model.UserLimit) { limit.Wallet = "not a wallet" },
+			func(limit *model.UserLimit) { limit.MaxTxLimit = "1,000" },
+			func(limit *model.UserLimit) { limit.MaxTxLimit = "" },
+			func(limit *model.UserLimit) { limit.DailyUSDLimit = "1,000" },
+			func(limit *model.UserLimit) { limit.Expiry = time.Now().Add(-time.Hour) },
+			func(limit *model.UserLimit) { limit.Reason = "" },
+			func(limit *model.UserLimit) { limit.OrderPair = "bitcoin" },
+		} {
+			limit := valid
+			modify(&limit)
+			Expect(store.GrantUserLimit(&limit)).NotTo(Succeed())
+		}
+		Expect(store.RevokeUserLimit(1, admin)).NotTo(Succeed())
+		Expect(dropTestDB()).To(Succeed())
+	})
+})
@@ -68,6 +68,15 @@ func (s *initiatorSwap) Initiate() (string, error) {
 	return txHash, nil
 }
 
+func (s *initiatorSwap) IsInitiated() (bool, string, error) {
+	// any utxo of the script, in the mempool or not, funds it
+	_, txHashes, _, err := s.watcher.IsDetected()
+	if err != nil {
+		return false, "", err
+	}
+	return txHashes != "", txHashes, nil
+}
+
 func (s *initiatorSwap) Expired() (bool, error) {
 	return s.watcher.Expired()
 }
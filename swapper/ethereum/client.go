@@ -29,6 +29,7 @@ var (
 type Client interface {
 	GetTransactOpts(privKey *ecdsa.PrivateKey) (*bind.TransactOpts, error)
 	GetCurrentBlock() (uint64, error)
+	GetBlockHash(blockNumber uint64) (common.Hash, error)
 	GetL1CurrentBlock() (uint64, error)
 	GetL1BlockAt(uint64) (uint64, error)
 	GetProvider() *ethclient.Client
@@ -83,6 +84,14 @@ func (client *client) GetCurrentBlock() (uint64, error) {
 	return client.provider.BlockNumber(context.Background())
 }
 
+func (client *client) GetBlockHash(blockNumber uint64) (common.Hash, error) {
+	header, err := client.provider.HeaderByNumber(context.Background(), new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
 type L2Block struct {
 	Data struct {
 		L1BlockNumber string `json:"l1BlockNumber"`
@@ -78,6 +78,11 @@ func (initiatorSwap *initiatorSwap) Initiate() (string, error) {
 	return initiatorSwap.client.InitiateGardenHTLC(initiatorSwap.atomicSwapAddr, initiatorSwap.initiator, initiatorSwap.redeemerAddr, initiatorSwap.tokenAddr, initiatorSwap.expiry, initiatorSwap.amount, initiatorSwap.secretHash)
 }
 
+func (initiatorSwap *initiatorSwap) IsInitiated() (bool, string, error) {
+	initiated, txHash, _, err := initiatorSwap.watcher.IsDetected()
+	return initiated, txHash, err
+}
+
 func (initiatorSwap *initiatorSwap) Expired() (bool, error) {
 	return initiatorSwap.watcher.Expired()
 }
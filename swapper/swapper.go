@@ -14,6 +14,9 @@ var (
 
 type InitiatorSwap interface {
 	Initiate() (string, error)
+	// reports whether the swap is funded and by which transactions, a funding
+	// that is not confirmed yet counts as initiated where the chain shows it
+	IsInitiated() (bool, string, error)
 	WaitForRedeem() ([]byte, string, error)
 	IsRedeemed() (bool, []byte, string, error)
 	Refund() (string, error)
@@ -0,0 +1,144 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/catalogfi/orderbook/internal/job"
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/swapper/ethereum"
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// DefaultBondInterval is the time between verifications of the bonds when it is
+// not configured
+const DefaultBondInterval = 10 * time.Minute
+
+// abi of the bond contracts holding the bonds of the fillers
+const bondContractABI = `[{"type":"function","name":"bondOf","stateMutability":"view","inputs":[{"name":"filler","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}]`
+
+type BondStore interface {
+	// get the fillers with their bond addresses
+	GetFillers() ([]model.Filler, error)
+	// record the bond of a filler verified on chain
+	UpdateBond(filler string, amount *big.Int, verifiedAt time.Time) error
+}
+
+// BondReader reads the bond of a filler on chain
+type BondReader interface {
+	BondOf(ctx context.Context, filler, bondAddress string) (*big.Int, error)
+}
+
+type evmBondReader struct {
+	client   ethereum.Client
+	token    string
+	contract string
+	abi      abi.ABI
+}
+
+// NewBondReader returns a reader of the bonds held by the bond contract of the
+// config, or of the balances of the bond addresses without one
+func NewBondReader(client ethereum.Client, config model.BondConfig) (BondReader, error) {
+	contractABI, err := abi.JSON(strings.NewReader(bondContractABI))
+	if err != nil {
+		return nil, err
+	}
+	if config.Contract != "" && !common.IsHexAddress(config.Contract) {
+		return nil, fmt.Errorf("invalid bond contract %s", config.Contract)
+	}
+	if config.Token != "" && !common.IsHexAddress(config.Token) {
+		return nil, fmt.Errorf("invalid bond token %s", config.Token)
+	}
+	return &evmBondReader{
+		client:   client,
+		token:    config.Token,
+		contract: config.Contract,
+		abi:      contractABI,
+	}, nil
+}
+
+func (reader *evmBondReader) BondOf(ctx context.Context, filler, bondAddress string) (*big.Int, error) {
+	if reader.contract != "" {
+		data, err := reader.abi.Pack("bondOf", common.HexToAddress(filler))
+		if err != nil {
+			return nil, err
+		}
+		contract := common.HexToAddress(reader.contract)
+		result, err := reader.client.GetProvider().CallContract(ctx, geth.CallMsg{To: &contract, Data: data}, nil)
+		if err != nil {
+			return nil, err
+		}
+		values, err := reader.abi.Unpack("bondOf", result)
+		if err != nil {
+			return nil, err
+		}
+		bond, ok := values[0].(*big.Int)
+		if !ok {
+			return nil, fmt.Errorf("unexpected bond %v", values[0])
+		}
+		return bond, nil
+	}
+	if reader.token != "" {
+		return reader.client.GetERC20Balance(common.HexToAddress(reader.token), common.HexToAddress(bondAddress))
+	}
+	return reader.client.GetProvider().BalanceAt(ctx, common.HexToAddress(bondAddress), nil)
+}
+
+// NewVerifier returns a job periodically verifying the bonds of the registered
+// fillers on chain
+func NewVerifier(store BondStore, reader BondReader, config model.BondConfig, logger *zap.Logger) job.Job {
+	logger = logger.With(zap.String("service", "bonds"))
+	return job.NewPeriodic(job.Interval(config.VerifyInterval, DefaultBondInterval), func(ctx context.Context) error {
+		return verifyBonds(ctx, store, reader, logger)
+	}, logger)
+}
+
+// NewEVMVerifier returns a verifier of the bonds on the bond chain of the config
+func NewEVMVerifier(store BondStore, config model.Config, logger *zap.Logger) (job.Job, error) {
+	netConfig, ok := config.Network[config.Bond.Chain]
+	if !ok || !config.Bond.Chain.IsEVM() {
+		return nil, fmt.Errorf("unsupported bond chain %s", config.Bond.Chain)
+	}
+	client, err := ethereum.NewClient(logger, netConfig.RPC["ethrpc"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client: %v", err)
+	}
+	reader, err := NewBondReader(client, config.Bond)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(store, reader, config.Bond, logger), nil
+}
+
+// verify the bonds of the registered fillers, a bond which cannot be read keeps
+// its last verified amount
+func verifyBonds(ctx context.Context, store BondStore, reader BondReader, logger *zap.Logger) error {
+	fillers, err := store.GetFillers()
+	if err != nil {
+		return fmt.Errorf("failed to get fillers: %v", err)
+	}
+	for _, filler := range fillers {
+		if !filler.Registered {
+			continue
+		}
+		bondAddress := filler.BondAddress
+		if bondAddress == "" {
+			bondAddress = filler.Address
+		}
+		bond, err := reader.BondOf(ctx, filler.Address, bondAddress)
+		if err != nil {
+			logger.Error("read bond", zap.String("filler", filler.Address), zap.Error(err))
+			continue
+		}
+		if err := store.UpdateBond(filler.Address, bond, time.Now().UTC()); err != nil {
+			logger.Error("update bond", zap.String("filler", filler.Address), zap.Error(err))
+		}
+	}
+	return nil
+}
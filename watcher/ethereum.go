@@ -17,7 +17,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type EthereumWatcher struct {
@@ -67,6 +66,14 @@ func NewEthereumWatcher(store Store, chain model.Chain, config model.NetworkConf
 	if err != nil {
 		return nil, fmt.Errorf("failed to load client: %v", err)
 	}
+	return NewEthereumWatcherWithClient(store, chain, config, address, startBlock, blockSpan, ethClient, screener, logger)
+}
+
+func NewEthereumWatcherWithClient(store Store, chain model.Chain, config model.NetworkConfig, address common.Address, startBlock uint64, blockSpan uint64, ethClient ethereum.Client, screener screener.Screener, logger *zap.Logger) (*EthereumWatcher, error) {
+	startBlock, err := LoadStartBlock(store, chain, address, startBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block cursor: %v", err)
+	}
 	gardenHTLC, _ := GardenHTLC.NewGardenHTLC(address, ethClient.GetProvider())
 	gardenHTLCAbi, _ := GardenHTLC.GardenHTLCMetaData.GetAbi()
 	return &EthereumWatcher{
@@ -87,92 +94,182 @@ func NewEthereumWatcher(store Store, chain model.Chain, config model.NetworkConf
 }
 
 func (w *EthereumWatcher) Watch() {
+	w.logger.Info("started ethereum watcher", zap.String("htlc", w.gardenHTLCAddr.Hex()), zap.Any("chain", w.chain))
+
+	for {
+		if err := w.Poll(); err != nil {
+			var nonrecoverable *NonRecoverableError
+			if errors.As(err, &nonrecoverable) {
+				w.logger.Error("an unrecoverable error occurred while handling evm logs, shutting down", zap.Error(err), zap.Any("chain", w.chain))
+				return
+			}
+			w.logger.Error("failed to handle evm logs", zap.Error(err))
+		}
+		time.Sleep(w.interval)
+	}
+}
+
+// Poll processes the logs emitted since the last processed block. Swap updates
+// made by blocks which have since been reorged out are rolled back first.
+func (w *EthereumWatcher) Poll() error {
 	eventIds := [][]common.Hash{{
 		w.ABI.Events["Initiated"].ID,
 		w.ABI.Events["Redeemed"].ID,
 		w.ABI.Events["Refunded"].ID,
 	}}
 
-	fmt.Println("Starting watcher for ", w.gardenHTLCAddr, " on ", w.chain, eventIds)
+	currentBlock, err := w.client.GetCurrentBlock()
+	if err != nil {
+		return fmt.Errorf("failed to get current block number: %v", err)
+	}
+	if w.startBlock > currentBlock {
+		// rpc is lagging behind the blocks we have already processed,
+		// wait for it to catch up before checking for reorgs
+		w.logger.Error("start block is greater than current block", zap.Uint64("startBlock", w.startBlock), zap.Uint64("currentBlock", currentBlock))
+		return nil
+	}
 
-	for {
-		currentBlock, err := w.client.GetCurrentBlock()
-		fmt.Println("Current block: ", currentBlock)
+	forkBlock, reorged, err := FindForkPoint(w.client, w.store, w.chain, w.gardenHTLCAddr.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to check for reorgs: %v", err)
+	}
+	if reorged {
+		w.logger.Warn("chain reorganization detected, rolling back swaps", zap.Any("chain", w.chain), zap.Uint64("forkBlock", forkBlock), zap.Uint64("startBlock", w.startBlock))
+		if err := w.store.RollbackBlocks(w.chain, w.gardenHTLCAddr.Hex(), forkBlock); err != nil {
+			return fmt.Errorf("failed to rollback blocks after %d: %v", forkBlock, err)
+		}
+		w.startBlock = forkBlock
+	}
+	if w.startBlock == currentBlock {
+		return nil
+	}
+
+	blockHash, err := w.client.GetBlockHash(currentBlock)
+	if err != nil {
+		return fmt.Errorf("failed to get block hash: %v", err)
+	}
+	logsSlice, err := w.client.GetLogs(w.gardenHTLCAddr, w.startBlock, currentBlock, eventIds, w.blockSpan)
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %v", err)
+	}
+	// the rpc and screener calls are made before the transaction so that they do not
+	// hold it open. It is rolled back on failure and the block range processed again.
+	err = RetryOnWatcherError(func() error {
+		initiates, err := FetchEVMInitiates(eventIds, logsSlice, w.store, w.screener, w.GardenHTLC)
 		if err != nil {
-			w.logger.Error("failed to get current block number", zap.Error(err))
-			continue
+			return err
 		}
-		if w.startBlock == currentBlock {
-			time.Sleep(1 * time.Second)
-			continue
-		} else if w.startBlock > currentBlock {
-			//this might happen because of a reorg or rpc is giving incorrect block number
-			//So when this happens, just process it again.
-			w.logger.Error("start block is greater than current block", zap.Uint64("startBlock", w.startBlock), zap.Uint64("currentBlock", currentBlock))
-			w.startBlock = currentBlock
+		return w.store.Transaction(func(store Store) error {
+			if err := HandleEVMLogs(eventIds, logsSlice, initiates, store, w.logger); err != nil {
+				return err
+			}
+			if err := store.UpdateBlockCursor(w.chain, w.gardenHTLCAddr.Hex(), currentBlock); err != nil {
+				return err
+			}
+			return store.RecordBlockHash(w.chain, w.gardenHTLCAddr.Hex(), currentBlock, blockHash.Hex())
+		})
+	}, retryCount)
+	if err != nil {
+		return err
+	}
+	if err := UpdateEVMConfirmations(w.store, w.chain, currentBlock); err != nil {
+		w.logger.Error("failed to update confirmations", zap.Error(err))
+	}
+
+	w.startBlock = currentBlock
+	return nil
+}
+
+// LoadStartBlock resumes from the persisted block cursor of the given htlc contract,
+// the configured start block is only used for contracts which were never watched before
+func LoadStartBlock(store Store, chain model.Chain, contract common.Address, startBlock uint64) (uint64, error) {
+	cursor, err := store.GetBlockCursor(chain, contract.Hex())
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return startBlock, nil
 		}
-		fromBlock := w.startBlock
-		toBlock := currentBlock
-		logsSlice, err := w.client.GetLogs(w.gardenHTLCAddr, fromBlock, toBlock, eventIds, w.blockSpan)
-		if err != nil {
-			w.logger.Error("failed to get logs", zap.Error(err))
+		return 0, err
+	}
+	return cursor, nil
+}
+
+// EVMInitiate is the swap an initiate log created on chain and whether its initiator
+// is blacklisted, the initiator is only screened if the swap is waiting for it
+type EVMInitiate struct {
+	Swap        Swap
+	Screened    bool
+	Blacklisted bool
+}
+
+// FetchEVMInitiates reads the swaps created by the initiate logs from the contract
+// and screens their initiators, keyed by the on chain identifier of the swap
+func FetchEVMInitiates(eventIds [][]common.Hash, logs []types.Log, store Store, screener screener.Screener, contract *GardenHTLC.GardenHTLC) (map[common.Hash]EVMInitiate, error) {
+	initiates := make(map[common.Hash]EVMInitiate)
+	for _, log := range logs {
+		if log.Topics[0] != eventIds[0][0] {
 			continue
 		}
-		werr := HandleEVMLogs(eventIds, logsSlice, w.store, w.screener, w.GardenHTLC, w.logger)
-		if werr != nil {
-			var nonrecoverable *NonRecoverableError
-			if errors.As(werr, &nonrecoverable) {
-				w.logger.Error("an unrecoverable error occurred while handling evm logs, shutting down", zap.Error(werr), zap.Any("chain", w.chain))
-				return
-			}
-			w.logger.Error("failed to handle evm logs", zap.Error(werr))
+		cSwap, err := RetryWithReturnValue(func() (Swap, error) {
+			return contract.Orders(nil, log.Topics[1])
+		}, retryCount)
+		if err != nil {
+			return nil, NewNonRecoverableError(fmt.Errorf("failed to get swap order: %s", err))
 		}
-		err = UpdateEVMConfirmations(w.store, w.chain, currentBlock)
+		initiate, err := ScreenEVMInitiate(log, store, cSwap, screener)
 		if err != nil {
-			w.logger.Error("failed to update confirmations", zap.Error(err))
+			return nil, err
 		}
+		initiates[log.Topics[1]] = initiate
+	}
+	return initiates, nil
+}
 
-		w.startBlock = currentBlock
-		time.Sleep(w.interval)
+// ScreenEVMInitiate checks whether the initiator of a swap waiting for its initiate
+// is blacklisted
+func ScreenEVMInitiate(log types.Log, store Store, cSwap Swap, screener screener.Screener) (EVMInitiate, error) {
+	initiate := EVMInitiate{Swap: cSwap, Screened: screener == nil}
+	if screener == nil {
+		return initiate, nil
+	}
+	swap, err := store.SwapByOCID(log.Topics[1].Hex()[2:])
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return initiate, nil
+		}
+		return initiate, NewRecoverableError(fmt.Errorf("failed to get swap by ocid: %s", err))
 	}
+	if swap.InitiateTxHash != "" {
+		return initiate, nil
+	}
+	isBlacklisted, err := screener.IsBlacklisted(map[string]model.Chain{cSwap.Initiator.Hex(): swap.Chain})
+	if err != nil {
+		return initiate, NewRecoverableError(fmt.Errorf("failed to check if address is blacklisted, %s", cSwap.Initiator.Hex()))
+	}
+	initiate.Screened = true
+	initiate.Blacklisted = isBlacklisted
+	return initiate, nil
 }
 
-func HandleEVMLogs(eventIds [][]common.Hash, logs []types.Log, store Store, screener screener.Screener, contract *GardenHTLC.GardenHTLC, logger *zap.Logger) error {
+// HandleEVMLogs updates the swaps of the logs, it runs inside a transaction and
+// does not retry, logs which are ignored do not fail it
+func HandleEVMLogs(eventIds [][]common.Hash, logs []types.Log, initiates map[common.Hash]EVMInitiate, store Store, logger *zap.Logger) error {
 	for _, log := range logs {
+		var err error
 		switch log.Topics[0] {
 		case eventIds[0][0]:
-			cSwap, err := RetryWithReturnValue(func() (Swap, error) {
-				return contract.Orders(nil, log.Topics[1])
-			}, retryCount)
-			if err != nil {
-				return NewNonRecoverableError(fmt.Errorf("failed to get swap order: %s", err))
-			}
-			handler := func() error {
-				return HandleEVMInitiate(log, store, cSwap, screener)
-			}
-			wErr := RetryOnWatcherError(handler, retryCount)
-			var nonrecoverable *NonRecoverableError
-			if wErr != nil && errors.As(wErr, &nonrecoverable) {
-				return wErr
-			}
+			err = HandleEVMInitiate(log, store, initiates[log.Topics[1]])
 		case eventIds[0][1]:
-			handler := func() error {
-				return HandleEVMRedeem(store, log)
-			}
-			err := RetryOnWatcherError(handler, retryCount)
-			var nonrecoverable *NonRecoverableError
-			if err != nil && errors.As(err, &nonrecoverable) {
-				return err
-			}
+			err = HandleEVMRedeem(store, log)
 		case eventIds[0][2]:
-			handler := func() error {
-				return HandleEVMRefund(store, log)
-			}
-			err := RetryOnWatcherError(handler, retryCount)
-			var nonrecoverable *NonRecoverableError
-			if err != nil && errors.As(err, &nonrecoverable) {
-				return err
-			}
+			err = HandleEVMRefund(store, log)
+		}
+		var ignorable *IgnorableError
+		if errors.As(err, &ignorable) {
+			logger.Warn("ignoring evm log", zap.String("txHash", log.TxHash.Hex()), zap.Error(err))
+			continue
+		}
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -212,10 +309,10 @@ func UpdateEVMConfirmations(store Store, chain model.Chain, currentBlock uint64)
 	return nil
 }
 
-func HandleEVMInitiate(log types.Log, store Store, cSwap Swap, screener screener.Screener) error {
+func HandleEVMInitiate(log types.Log, store Store, initiate EVMInitiate) error {
 	swap, err := store.SwapByOCID(log.Topics[1].Hex()[2:])
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, model.ErrNotFound) {
 			return nil
 		}
 		return NewRecoverableError(fmt.Errorf("failed to get swap by ocid: %s", err))
@@ -224,14 +321,13 @@ func HandleEVMInitiate(log types.Log, store Store, cSwap Swap, screener screener
 		return nil
 	}
 
-	if screener != nil {
-		isBlacklisted, err := screener.IsBlacklisted(map[string]model.Chain{cSwap.Initiator.Hex(): swap.Chain})
-		if err != nil {
-			return NewRecoverableError(fmt.Errorf("failed to check if address is blacklisted, %s", cSwap.Initiator.Hex()))
-		}
-		if isBlacklisted {
-			return NewIgnorableError(fmt.Errorf("address is blacklisted, %s", cSwap.Initiator.Hex()))
-		}
+	// the swap was created after its initiator was screened
+	if !initiate.Screened {
+		return NewRecoverableError(fmt.Errorf("initiator of swap %s was not screened", swap.OnChainIdentifier))
+	}
+	cSwap := initiate.Swap
+	if initiate.Blacklisted {
+		return NewIgnorableError(fmt.Errorf("address is blacklisted, %s", cSwap.Initiator.Hex()))
 	}
 
 	amount, ok := new(big.Int).SetString(swap.Amount, 10)
@@ -269,7 +365,7 @@ func HandleEVMRedeem(store Store, log types.Log) error {
 	swap, err := store.SwapByOCID(log.Topics[1].Hex()[2:])
 
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, model.ErrNotFound) {
 			return nil
 		}
 		return NewRecoverableError(
@@ -287,6 +383,7 @@ func HandleEVMRedeem(store Store, log types.Log) error {
 	}
 	swap.Secret = hex.EncodeToString(log.Data[64:])
 	swap.RedeemTxHash = log.TxHash.Hex()
+	swap.RedeemBlockNumber = log.BlockNumber
 	swap.Status = model.Redeemed
 	err = store.UpdateSwap(&swap)
 	if err != nil {
@@ -300,7 +397,7 @@ func HandleEVMRedeem(store Store, log types.Log) error {
 func HandleEVMRefund(store Store, log types.Log) error {
 	swap, err := store.SwapByOCID(log.Topics[1].Hex()[2:])
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, model.ErrNotFound) {
 			return nil
 		}
 		return NewRecoverableError(
@@ -311,6 +408,7 @@ func HandleEVMRefund(store Store, log types.Log) error {
 		return nil
 	}
 	swap.RefundTxHash = log.TxHash.String()
+	swap.RefundBlockNumber = log.BlockNumber
 	swap.Status = model.Refunded
 	err = store.UpdateSwap(&swap)
 	if err != nil {
@@ -346,7 +444,8 @@ func RetryWithReturnValue[T any](f func() (T, error), retries int) (T, error) {
 	var err error
 	var nilData T
 	for i := 0; i < retries; i++ {
-		data, err := f()
+		var data T
+		data, err = f()
 		if err == nil {
 			return data, nil
 		}
@@ -17,7 +17,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type EthereumL2Watcher struct {
@@ -56,6 +55,14 @@ func NewEthereumL2Watcher(store Store, chain model.Chain, config model.NetworkCo
 	if err != nil {
 		return nil, fmt.Errorf("failed to load client: %v", err)
 	}
+	return NewEthereumL2WatcherWithClient(store, chain, config, address, startBlock, blockSpan, ethClient, screener, logger)
+}
+
+func NewEthereumL2WatcherWithClient(store Store, chain model.Chain, config model.NetworkConfig, address common.Address, startBlock uint64, blockSpan uint64, ethClient ethereum.Client, screener screener.Screener, logger *zap.Logger) (*EthereumL2Watcher, error) {
+	startBlock, err := LoadStartBlock(store, chain, address, startBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block cursor: %v", err)
+	}
 	gardenSwap, _ := GardenHTLC.NewGardenHTLC(address, ethClient.GetProvider())
 	gardenSwapAbi, _ := GardenHTLC.GardenHTLCMetaData.GetAbi()
 	return &EthereumL2Watcher{
@@ -76,95 +83,165 @@ func NewEthereumL2Watcher(store Store, chain model.Chain, config model.NetworkCo
 }
 
 func (w *EthereumL2Watcher) Watch() {
+	for {
+		if err := w.Poll(); err != nil {
+			var nonrecoverable *NonRecoverableError
+			if errors.As(err, &nonrecoverable) {
+				w.logger.Error("an unrecoverable error occurred while handling EVML2 logs, shutting down", zap.Error(err), zap.Any("chain", w.chain))
+				return
+			}
+			w.logger.Error("failed to handle EVML2 logs", zap.Error(err))
+		}
+		time.Sleep(w.interval)
+	}
+}
+
+// Poll processes the logs emitted since the last processed block. Swap updates
+// made by blocks which have since been reorged out are rolled back first.
+func (w *EthereumL2Watcher) Poll() error {
 	eventIds := [][]common.Hash{{
 		w.ABI.Events["Initiated"].ID,
 		w.ABI.Events["Redeemed"].ID,
 		w.ABI.Events["Refunded"].ID,
 	}}
 
-	for {
-		currentBlock, err := w.client.GetCurrentBlock()
+	currentBlock, err := w.client.GetCurrentBlock()
+	if err != nil {
+		return fmt.Errorf("failed to get current block number: %v", err)
+	}
+	currentL1Block, err := w.client.GetL1CurrentBlock()
+	if err != nil {
+		return fmt.Errorf("failed to get current l1 block number: %v", err)
+	}
+	if w.startBlock > currentBlock {
+		// rpc is lagging behind the blocks we have already processed,
+		// wait for it to catch up before checking for reorgs
+		w.logger.Error("start block is greater than current block", zap.Uint64("startBlock", w.startBlock), zap.Uint64("currentBlock", currentBlock))
+		return nil
+	}
+
+	forkBlock, reorged, err := FindForkPoint(w.client, w.store, w.chain, w.gardenSwapAddr.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to check for reorgs: %v", err)
+	}
+	if reorged {
+		block, l1Block, err := w.rollbackPoint(forkBlock)
 		if err != nil {
-			w.logger.Error("failed to get current block number", zap.Error(err))
-			continue
+			return fmt.Errorf("failed to find the l1 block of %d: %v", forkBlock, err)
 		}
-		currentL1Block, err := w.client.GetL1CurrentBlock()
-		if err != nil {
-			w.logger.Error("failed to get current block number", zap.Error(err))
-			continue
+		w.logger.Warn("chain reorganization detected, rolling back swaps", zap.Any("chain", w.chain), zap.Uint64("forkBlock", forkBlock), zap.Uint64("rollbackBlock", block), zap.Uint64("l1Block", l1Block), zap.Uint64("startBlock", w.startBlock))
+		if err := w.store.RollbackL2Blocks(w.chain, w.gardenSwapAddr.Hex(), block, l1Block); err != nil {
+			return fmt.Errorf("failed to rollback blocks after %d: %v", block, err)
 		}
-		if w.startBlock == currentBlock {
-			time.Sleep(1 * time.Second)
-			continue
-		} else if w.startBlock > currentBlock {
-			//this might happen because of a reorg or rpc is giving incorrect block number
-			//So when this happens, just process it again.
-			w.logger.Error("start block is greater than current block", zap.Uint64("startBlock", w.startBlock), zap.Uint64("currentBlock", currentBlock))
-			w.startBlock = currentBlock
+		w.startBlock = block
+	}
+	if w.startBlock == currentBlock {
+		return nil
+	}
+
+	blockHash, err := w.client.GetBlockHash(currentBlock)
+	if err != nil {
+		return fmt.Errorf("failed to get block hash: %v", err)
+	}
+	logsSlice, err := w.client.GetLogs(w.gardenSwapAddr, w.startBlock, currentBlock, eventIds, w.blockSpan)
+	if err != nil {
+		return fmt.Errorf("failed to get logs: %v", err)
+	}
+	// the rpc and screener calls are made before the transaction so that they do not
+	// hold it open. It is rolled back on failure and the block range processed again.
+	err = RetryOnWatcherError(func() error {
+		initiates, err := FetchEVMInitiates(eventIds, logsSlice, w.store, w.screener, w.GardenHTLC)
+		if err != nil {
+			return err
 		}
-		fromBlock := w.startBlock
-		toBlock := currentBlock
-		logsSlice, err := w.client.GetLogs(w.gardenSwapAddr, fromBlock, toBlock, eventIds, w.blockSpan)
+		l1Blocks, err := w.fetchL1Blocks(logsSlice)
 		if err != nil {
-			w.logger.Error("failed to get logs", zap.Error(err))
-			continue
+			return err
 		}
-		werr := w.HandleEVML2Logs(eventIds, logsSlice, w.store, w.screener, w.GardenHTLC, w.logger)
-		if werr != nil {
-			var nonrecoverable *NonRecoverableError
-			if errors.As(werr, &nonrecoverable) {
-				w.logger.Error("an unrecoverable error occurred while handling EVML2 logs, shutting down", zap.Error(werr), zap.Any("chain", w.chain))
-				return
+		return w.store.Transaction(func(store Store) error {
+			if err := w.HandleEVML2Logs(eventIds, logsSlice, initiates, l1Blocks, store); err != nil {
+				return err
 			}
-			w.logger.Error("failed to handle EVML2 logs", zap.Error(werr))
-		}
+			if err := store.UpdateBlockCursor(w.chain, w.gardenSwapAddr.Hex(), currentBlock); err != nil {
+				return err
+			}
+			return store.RecordBlockHash(w.chain, w.gardenSwapAddr.Hex(), currentBlock, blockHash.Hex())
+		})
+	}, retryCount)
+	if err != nil {
+		return err
+	}
+	if err := w.UpdateEVML2Confirmations(w.store, w.chain, currentL1Block); err != nil {
+		w.logger.Error("failed to update confirmations", zap.Error(err))
+	}
 
-		err = w.UpdateEVML2Confirmations(w.store, w.chain, currentL1Block)
+	w.startBlock = currentBlock
+	return nil
+}
+
+// the swaps of an L2 record the L1 blocks of their updates and many L2 blocks map
+// to the same L1 block, so the L2 blocks are reprocessed from a block of an earlier
+// L1 block than the fork block. Returns that block and its L1 block.
+func (w *EthereumL2Watcher) rollbackPoint(forkBlock uint64) (uint64, uint64, error) {
+	forkL1Block, err := w.client.GetL1BlockAt(forkBlock)
+	if err != nil {
+		return 0, 0, err
+	}
+	block, step := forkBlock, uint64(ReorgDepth)
+	for block > 0 {
+		if block > step {
+			block -= step
+		} else {
+			block = 0
+		}
+		l1Block, err := w.client.GetL1BlockAt(block)
 		if err != nil {
-			w.logger.Error("failed to update confirmations", zap.Error(err))
+			return 0, 0, err
+		}
+		if l1Block < forkL1Block {
+			return block, l1Block, nil
 		}
+		step *= 2
+	}
+	return 0, 0, nil
+}
 
-		w.startBlock = currentBlock
-		time.Sleep(w.interval)
+// the l1 blocks of the blocks of the logs, swaps record their updates at l1 blocks
+func (w *EthereumL2Watcher) fetchL1Blocks(logs []types.Log) (map[uint64]uint64, error) {
+	l1Blocks := make(map[uint64]uint64)
+	for _, log := range logs {
+		if _, ok := l1Blocks[log.BlockNumber]; ok {
+			continue
+		}
+		l1Block, err := w.client.GetL1BlockAt(log.BlockNumber)
+		if err != nil {
+			return nil, NewRecoverableError(fmt.Errorf("failed to get l1 block number of %d: %s", log.BlockNumber, err))
+		}
+		l1Blocks[log.BlockNumber] = l1Block
 	}
+	return l1Blocks, nil
 }
 
-func (w *EthereumL2Watcher) HandleEVML2Logs(eventIds [][]common.Hash, logs []types.Log, store Store, screener screener.Screener, contract *GardenHTLC.GardenHTLC, logger *zap.Logger) error {
+// HandleEVML2Logs updates the swaps of the logs, it runs inside a transaction and
+// does not retry, logs which are ignored do not fail it
+func (w *EthereumL2Watcher) HandleEVML2Logs(eventIds [][]common.Hash, logs []types.Log, initiates map[common.Hash]EVMInitiate, l1Blocks map[uint64]uint64, store Store) error {
 	for _, log := range logs {
+		var err error
 		switch log.Topics[0] {
 		case eventIds[0][0]:
-			cSwap, err := RetryWithReturnValue(func() (Swap, error) {
-				return contract.Orders(nil, log.Topics[1])
-			}, retryCount)
-			if err != nil {
-				return NewNonRecoverableError(fmt.Errorf("failed to get swap order: %s", err))
-			}
-			handler := func() error {
-				return w.HandleEVML2Initiate(log, store, cSwap, screener)
-			}
-			wErr := RetryOnWatcherError(handler, retryCount)
-			var nonrecoverable *NonRecoverableError
-			if wErr != nil && errors.As(wErr, &nonrecoverable) {
-				return wErr
-			}
+			err = w.HandleEVML2Initiate(log, store, initiates[log.Topics[1]], l1Blocks[log.BlockNumber])
 		case eventIds[0][1]:
-			handler := func() error {
-				return HandleEVML2Redeem(store, log)
-			}
-			err := RetryOnWatcherError(handler, retryCount)
-			var nonrecoverable *NonRecoverableError
-			if err != nil && errors.As(err, &nonrecoverable) {
-				return err
-			}
+			err = w.HandleEVML2Redeem(store, log, l1Blocks[log.BlockNumber])
 		case eventIds[0][2]:
-			handler := func() error {
-				return HandleEVML2Refund(store, log)
-			}
-			err := RetryOnWatcherError(handler, retryCount)
-			var nonrecoverable *NonRecoverableError
-			if err != nil && errors.As(err, &nonrecoverable) {
-				return err
-			}
+			err = w.HandleEVML2Refund(store, log, l1Blocks[log.BlockNumber])
+		}
+		var ignorable *IgnorableError
+		if errors.As(err, &ignorable) {
+			w.logger.Warn("ignoring evm log", zap.String("txHash", log.TxHash.Hex()), zap.Error(err))
+			continue
+		}
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -204,10 +281,10 @@ func (w *EthereumL2Watcher) UpdateEVML2Confirmations(store Store, chain model.Ch
 	return nil
 }
 
-func (w *EthereumL2Watcher) HandleEVML2Initiate(log types.Log, store Store, cSwap Swap, screener screener.Screener) error {
+func (w *EthereumL2Watcher) HandleEVML2Initiate(log types.Log, store Store, initiate EVMInitiate, currentL1Block uint64) error {
 	swap, err := store.SwapByOCID(log.Topics[1].Hex()[2:])
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, model.ErrNotFound) {
 			return nil
 		}
 		return NewRecoverableError(fmt.Errorf("failed to get swap by ocid: %s", err))
@@ -216,14 +293,13 @@ func (w *EthereumL2Watcher) HandleEVML2Initiate(log types.Log, store Store, cSwa
 		return nil
 	}
 
-	if screener != nil {
-		isBlacklisted, err := screener.IsBlacklisted(map[string]model.Chain{cSwap.Initiator.Hex(): swap.Chain})
-		if err != nil {
-			return NewRecoverableError(fmt.Errorf("failed to check if address is blacklisted, %s", cSwap.Initiator.Hex()))
-		}
-		if isBlacklisted {
-			return NewIgnorableError(fmt.Errorf("address is blacklisted, %s", cSwap.Initiator.Hex()))
-		}
+	// the swap was created after its initiator was screened
+	if !initiate.Screened {
+		return NewRecoverableError(fmt.Errorf("initiator of swap %s was not screened", swap.OnChainIdentifier))
+	}
+	cSwap := initiate.Swap
+	if initiate.Blacklisted {
+		return NewIgnorableError(fmt.Errorf("address is blacklisted, %s", cSwap.Initiator.Hex()))
 	}
 
 	amount, ok := new(big.Int).SetString(swap.Amount, 10)
@@ -246,11 +322,6 @@ func (w *EthereumL2Watcher) HandleEVML2Initiate(log types.Log, store Store, cSwa
 		return NewIgnorableError(fmt.Errorf("incorrect redeemer: %s", swap.RedeemerAddress))
 	}
 
-	currentL1Block, err := w.client.GetL1BlockAt(log.BlockNumber)
-	if err != nil {
-		return NewRecoverableError(fmt.Errorf("failed to get current block number"))
-	}
-
 	w.logger.Info(fmt.Sprintf("Mapping %d on l2 to %d", log.BlockNumber, currentL1Block))
 
 	swap.InitiateTxHash = log.TxHash.String()
@@ -264,11 +335,11 @@ func (w *EthereumL2Watcher) HandleEVML2Initiate(log types.Log, store Store, cSwa
 	return nil
 }
 
-func HandleEVML2Redeem(store Store, log types.Log) error {
+func (w *EthereumL2Watcher) HandleEVML2Redeem(store Store, log types.Log, redeemL1Block uint64) error {
 	swap, err := store.SwapByOCID(log.Topics[1].Hex()[2:])
 
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, model.ErrNotFound) {
 			return nil
 		}
 		return NewRecoverableError(
@@ -286,6 +357,7 @@ func HandleEVML2Redeem(store Store, log types.Log) error {
 	}
 	swap.Secret = hex.EncodeToString(log.Data[64:])
 	swap.RedeemTxHash = log.TxHash.Hex()
+	swap.RedeemBlockNumber = redeemL1Block
 	swap.Status = model.Redeemed
 	err = store.UpdateSwap(&swap)
 	if err != nil {
@@ -296,10 +368,10 @@ func HandleEVML2Redeem(store Store, log types.Log) error {
 	return nil
 }
 
-func HandleEVML2Refund(store Store, log types.Log) error {
+func (w *EthereumL2Watcher) HandleEVML2Refund(store Store, log types.Log, refundL1Block uint64) error {
 	swap, err := store.SwapByOCID(log.Topics[1].Hex()[2:])
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, model.ErrNotFound) {
 			return nil
 		}
 		return NewRecoverableError(
@@ -310,6 +382,7 @@ func HandleEVML2Refund(store Store, log types.Log) error {
 		return nil
 	}
 	swap.RefundTxHash = log.TxHash.String()
+	swap.RefundBlockNumber = refundL1Block
 	swap.Status = model.Refunded
 	err = store.UpdateSwap(&swap)
 	if err != nil {
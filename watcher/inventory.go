@@ -0,0 +1,139 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/catalogfi/orderbook/internal/job"
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/swapper/bitcoin"
+	"github.com/catalogfi/orderbook/swapper/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// DefaultInventoryInterval is the time between readings of the inventories on
+// chain when it is not configured
+const DefaultInventoryInterval = time.Minute
+
+type InventoryStore interface {
+	// get the inventories of the fillers
+	GetInventories(filler string, since time.Time) ([]model.Inventory, error)
+	// record the balance of the address of an inventory read on chain
+	UpdateInventoryBalance(inventory model.Inventory, amount *big.Int, readAt time.Time) error
+}
+
+// BalanceReader reads the balance of an asset held by an address on a chain
+type BalanceReader interface {
+	Balance(ctx context.Context, asset model.Asset, address string) (*big.Int, error)
+}
+
+type evmBalanceReader struct {
+	client ethereum.Client
+	config model.NetworkConfig
+}
+
+// NewEVMBalanceReader returns a reader of the native balances and of the balances
+// of the tokens of the assets of an evm chain
+func NewEVMBalanceReader(client ethereum.Client, config model.NetworkConfig) BalanceReader {
+	return &evmBalanceReader{client: client, config: config}
+}
+
+func (reader *evmBalanceReader) Balance(ctx context.Context, asset model.Asset, address string) (*big.Int, error) {
+	if asset == model.Primary {
+		return reader.client.GetProvider().BalanceAt(ctx, common.HexToAddress(address), nil)
+	}
+	token := common.HexToAddress(reader.config.Assets[asset].TokenAddress)
+	if reader.config.Assets[asset].TokenAddress == "" {
+		// secondary assets are the htlc contracts of their tokens
+		var err error
+		if token, err = reader.client.GetTokenAddress(common.HexToAddress(string(asset))); err != nil {
+			return nil, fmt.Errorf("failed to get token address: %v", err)
+		}
+	}
+	return reader.client.GetERC20Balance(token, common.HexToAddress(address))
+}
+
+type btcBalanceReader struct {
+	client bitcoin.Client
+	chain  model.Chain
+}
+
+// NewBTCBalanceReader returns a reader of the confirmed balances of bitcoin
+// addresses
+func NewBTCBalanceReader(client bitcoin.Client, chain model.Chain) BalanceReader {
+	return &btcBalanceReader{client: client, chain: chain}
+}
+
+func (reader *btcBalanceReader) Balance(ctx context.Context, asset model.Asset, address string) (*big.Int, error) {
+	addr, err := btcutil.DecodeAddress(address, reader.chain.Params())
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %v", err)
+	}
+	_, _, confirmed, err := reader.client.GetUTXOs(addr, 0)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(confirmed), nil
+}
+
+// LoadBalanceReaders returns the balance readers of the chains of the network
+func LoadBalanceReaders(config model.Network, logger *zap.Logger) (map[model.Chain]BalanceReader, error) {
+	readers := map[model.Chain]BalanceReader{}
+	for chain, netConfig := range config {
+		switch {
+		case chain.IsBTC():
+			client, err := LoadBTCClient(chain, netConfig, nil)
+			if err != nil {
+				return nil, err
+			}
+			readers[chain] = NewBTCBalanceReader(client, chain)
+		case chain.IsEVM():
+			client, err := ethereum.NewClient(logger, netConfig.RPC["ethrpc"])
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client: %v", err)
+			}
+			readers[chain] = NewEVMBalanceReader(client, netConfig)
+		}
+	}
+	return readers, nil
+}
+
+// NewStocktaker returns a job periodically reading the inventories fillers hold
+// at addresses on chain
+func NewStocktaker(store InventoryStore, readers map[model.Chain]BalanceReader, config model.Config, logger *zap.Logger) job.Job {
+	logger = logger.With(zap.String("service", "inventory"))
+	return job.NewPeriodic(job.Interval(config.InventoryInterval, DefaultInventoryInterval), func(ctx context.Context) error {
+		return readInventories(ctx, store, readers, logger)
+	}, logger)
+}
+
+// read the balances of the inventories held on chain, an inventory whose balance
+// cannot be read expires when it is not read again in time
+func readInventories(ctx context.Context, store InventoryStore, readers map[model.Chain]BalanceReader, logger *zap.Logger) error {
+	inventories, err := store.GetInventories("", time.Time{})
+	if err != nil {
+		return fmt.Errorf("failed to get inventories: %v", err)
+	}
+	for _, inventory := range inventories {
+		if inventory.Source != model.InventoryOnChain {
+			continue
+		}
+		reader, ok := readers[inventory.Chain]
+		if !ok {
+			continue
+		}
+		balance, err := reader.Balance(ctx, inventory.Asset, inventory.Address)
+		if err != nil {
+			logger.Error("read balance", zap.String("filler", inventory.Filler), zap.String("chain", string(inventory.Chain)), zap.Error(err))
+			continue
+		}
+		if err := store.UpdateInventoryBalance(inventory, balance, time.Now().UTC()); err != nil {
+			logger.Error("update inventory", zap.String("filler", inventory.Filler), zap.Error(err))
+		}
+	}
+	return nil
+}
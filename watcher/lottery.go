@@ -0,0 +1,27 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/catalogfi/orderbook/internal/job"
+	"github.com/catalogfi/orderbook/model"
+	"go.uber.org/zap"
+)
+
+// LotteryInterval is the time between draws of the lotteries which have ended
+const LotteryInterval = time.Second
+
+type LotteryStore interface {
+	// draw the lotteries which have ended and fill their orders with the winners
+	DrawLotteries(config model.Network) error
+}
+
+// NewDrawer returns a job assigning lottery orders to fillers once their
+// lotteries end
+func NewDrawer(store LotteryStore, config model.Network, logger *zap.Logger) job.Job {
+	logger = logger.With(zap.String("service", "lottery"))
+	return job.NewPeriodic(LotteryInterval, func(ctx context.Context) error {
+		return store.DrawLotteries(config)
+	}, logger)
+}
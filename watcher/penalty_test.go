@@ -0,0 +1,81 @@
+package watcher_test
+
+import (
+	"time"
+
+	"github.com/catalogfi/orderbook/mocks"
+	"github.com/catalogfi/orderbook/model"
+
+	. "github.com/catalogfi/orderbook/watcher"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Penalties", func() {
+	logger := zap.NewNop()
+	filler := "0x3cb762058f019c3abcd5e4a07957ee996ee319bd"
+	var (
+		mockCtrl  *gomock.Controller
+		mockStore *mocks.MockStore
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockStore = mocks.NewMockStore(mockCtrl)
+		mockStore.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(Store) error) error {
+			return fn(mockStore)
+		}).AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should record one penalty when the filler never initiated", func() {
+		order := model.Order{
+			Status: model.Filled,
+			Taker:  filler,
+			InitiatorAtomicSwap: &model.AtomicSwap{
+				Status: model.Refunded,
+			},
+			FollowerAtomicSwap: &model.AtomicSwap{
+				Status: model.NotStarted,
+			},
+		}
+		order, updated := ProcessOrder(order, mockStore, logger)
+		Expect(updated).Should(BeTrue())
+		Expect(order.Status).Should(Equal(model.FailedSoft))
+
+		mockStore.EXPECT().UpdateOrder(gomock.Any()).Return(nil)
+		mockStore.EXPECT().RecordPenalty(gomock.Any(), model.PenaltyFillerDefaulted).DoAndReturn(func(penalised *model.Order, reason string) error {
+			Expect(penalised.Taker).Should(Equal(filler))
+			return nil
+		}).Times(1)
+		Expect(SaveOrder(order, mockStore, logger)).Should(Succeed())
+	})
+
+	It("should not record a penalty when the maker never initiated", func() {
+		filledAt := time.Now().Add(-2 * SwapInitiationTimeout)
+		order := model.Order{
+			Status:   model.Filled,
+			Taker:    filler,
+			FilledAt: &filledAt,
+			InitiatorAtomicSwap: &model.AtomicSwap{
+				Status: model.NotStarted,
+			},
+			FollowerAtomicSwap: &model.AtomicSwap{
+				Status: model.NotStarted,
+			},
+		}
+		order, updated := ProcessOrder(order, mockStore, logger)
+		Expect(updated).Should(BeTrue())
+		Expect(order.Status).Should(Equal(model.Cancelled))
+
+		mockStore.EXPECT().UpdateOrder(gomock.Any()).Return(nil)
+		mockStore.EXPECT().RecordPenalty(gomock.Any(), gomock.Any()).Times(0)
+		Expect(SaveOrder(order, mockStore, logger)).Should(Succeed())
+	})
+})
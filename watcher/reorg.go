@@ -0,0 +1,39 @@
+package watcher
+
+import (
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/swapper/ethereum"
+)
+
+// ReorgDepth is the number of recently processed blocks which are checked
+// against the canonical chain on every poll
+const ReorgDepth = 64
+
+// FindForkPoint compares the recently processed blocks of the given htlc contract
+// against the canonical chain. It returns the newest processed block which is still
+// canonical and whether any block processed after it has been reorged out.
+func FindForkPoint(client ethereum.Client, store Store, chain model.Chain, contract string) (uint64, bool, error) {
+	blocks, err := store.GetProcessedBlocks(chain, contract, ReorgDepth)
+	if err != nil {
+		return 0, false, err
+	}
+	for i, block := range blocks {
+		hash, err := client.GetBlockHash(block.BlockNumber)
+		if err != nil {
+			return 0, false, err
+		}
+		if hash.Hex() == block.BlockHash {
+			return block.BlockNumber, i != 0, nil
+		}
+	}
+	if len(blocks) == 0 {
+		return 0, false, nil
+	}
+
+	// the reorg is deeper than the tracked blocks, reprocess everything after the oldest one
+	oldest := blocks[len(blocks)-1].BlockNumber
+	if oldest > 0 {
+		oldest--
+	}
+	return oldest, true, nil
+}
@@ -0,0 +1,327 @@
+package watcher_test
+
+import (
+	"fmt"
+
+	GardenHTLC "github.com/catalogfi/blockchain/evm/bindings/contracts/htlc/gardenhtlc"
+	"github.com/catalogfi/orderbook/mocks"
+	"github.com/catalogfi/orderbook/model"
+	"github.com/catalogfi/orderbook/swapper/ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	. "github.com/catalogfi/orderbook/watcher"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// fakeEthClient serves a canonical chain which can be replaced to simulate reorgs
+type fakeEthClient struct {
+	ethereum.Client
+
+	currentBlock uint64
+	hashes       map[uint64]common.Hash
+	logs         []types.Log
+	logRanges    [][2]uint64
+}
+
+func newFakeEthClient(currentBlock uint64, fork string) *fakeEthClient {
+	client := &fakeEthClient{currentBlock: currentBlock, hashes: map[uint64]common.Hash{}}
+	client.reorg(0, fork)
+	return client
+}
+
+// replaces every block after the given block with blocks from the given fork
+func (client *fakeEthClient) reorg(after uint64, fork string) {
+	for i := after + 1; i <= client.currentBlock+16; i++ {
+		client.hashes[i] = common.BytesToHash([]byte(fmt.Sprintf("%s-%d", fork, i)))
+	}
+}
+
+func (client *fakeEthClient) GetCurrentBlock() (uint64, error) {
+	return client.currentBlock, nil
+}
+
+func (client *fakeEthClient) GetBlockHash(blockNumber uint64) (common.Hash, error) {
+	return client.hashes[blockNumber], nil
+}
+
+// the fake L2 settles four blocks per L1 block
+func (client *fakeEthClient) GetL1CurrentBlock() (uint64, error) {
+	return client.currentBlock / 4, nil
+}
+
+func (client *fakeEthClient) GetL1BlockAt(blockNumber uint64) (uint64, error) {
+	return blockNumber / 4, nil
+}
+
+func (client *fakeEthClient) GetProvider() *ethclient.Client {
+	return nil
+}
+
+func (client *fakeEthClient) GetLogs(contract common.Address, fromBlock, toBlock uint64, eventIds [][]common.Hash, eventWindow uint64) ([]types.Log, error) {
+	client.logRanges = append(client.logRanges, [2]uint64{fromBlock, toBlock})
+	return client.logs, nil
+}
+
+var _ = Describe("Ethereum reorgs", func() {
+	var (
+		mockCtrl  *gomock.Controller
+		mockStore *mocks.MockStore
+
+		chain    = model.EthereumSepolia
+		contract = common.HexToAddress("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockStore = mocks.NewMockStore(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("can find the fork point", func() {
+		It("should not report a reorg when nothing was processed", func() {
+			client := newFakeEthClient(110, "a")
+			mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(nil, nil)
+			_, reorged, err := FindForkPoint(client, mockStore, chain, contract.Hex())
+			Expect(err).Should(BeNil())
+			Expect(reorged).Should(BeFalse())
+		})
+
+		It("should not report a reorg when the latest processed block is canonical", func() {
+			client := newFakeEthClient(110, "a")
+			mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return([]model.ProcessedBlock{
+				{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+				{BlockNumber: 105, BlockHash: client.hashes[105].Hex()},
+			}, nil)
+			block, reorged, err := FindForkPoint(client, mockStore, chain, contract.Hex())
+			Expect(err).Should(BeNil())
+			Expect(reorged).Should(BeFalse())
+			Expect(block).Should(Equal(uint64(110)))
+		})
+
+		It("should return the newest canonical block after a reorg", func() {
+			client := newFakeEthClient(110, "a")
+			processed := []model.ProcessedBlock{
+				{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+				{BlockNumber: 105, BlockHash: client.hashes[105].Hex()},
+				{BlockNumber: 100, BlockHash: client.hashes[100].Hex()},
+			}
+			client.reorg(103, "b")
+			mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(processed, nil)
+			block, reorged, err := FindForkPoint(client, mockStore, chain, contract.Hex())
+			Expect(err).Should(BeNil())
+			Expect(reorged).Should(BeTrue())
+			Expect(block).Should(Equal(uint64(100)))
+		})
+
+		It("should reprocess every tracked block when the reorg is deeper than the tracked blocks", func() {
+			client := newFakeEthClient(110, "a")
+			processed := []model.ProcessedBlock{
+				{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+				{BlockNumber: 105, BlockHash: client.hashes[105].Hex()},
+			}
+			client.reorg(90, "b")
+			mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(processed, nil)
+			block, reorged, err := FindForkPoint(client, mockStore, chain, contract.Hex())
+			Expect(err).Should(BeNil())
+			Expect(reorged).Should(BeTrue())
+			Expect(block).Should(Equal(uint64(104)))
+		})
+	})
+
+	Describe("can poll for logs", func() {
+		newWatcher := func(client ethereum.Client, startBlock uint64) *EthereumWatcher {
+			mockStore.EXPECT().GetBlockCursor(chain, contract.Hex()).Return(startBlock, nil)
+			watcher, err := NewEthereumWatcherWithClient(mockStore, chain, model.NetworkConfig{}, contract, 0, 1000, client, nil, zap.NewNop())
+			Expect(err).Should(BeNil())
+			return watcher
+		}
+		expectProcessed := func(client *fakeEthClient, block uint64) {
+			mockStore.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(Store) error) error {
+				return fn(mockStore)
+			})
+			mockStore.EXPECT().UpdateBlockCursor(chain, contract.Hex(), block).Return(nil)
+			mockStore.EXPECT().RecordBlockHash(chain, contract.Hex(), block, client.hashes[block].Hex()).Return(nil)
+			mockStore.EXPECT().GetActiveSwaps(chain).Return(nil, nil)
+		}
+
+		It("should resume from the persisted block cursor", func() {
+			client := newFakeEthClient(120, "a")
+			watcher := newWatcher(client, 110)
+
+			mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return([]model.ProcessedBlock{
+				{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+			}, nil)
+			expectProcessed(client, 120)
+			Expect(watcher.Poll()).Should(Succeed())
+			Expect(client.logRanges).Should(Equal([][2]uint64{{110, 120}}))
+		})
+
+		It("should rollback and reprocess the blocks after the fork point", func() {
+			client := newFakeEthClient(120, "a")
+			watcher := newWatcher(client, 120)
+			processed := []model.ProcessedBlock{
+				{BlockNumber: 120, BlockHash: client.hashes[120].Hex()},
+				{BlockNumber: 115, BlockHash: client.hashes[115].Hex()},
+				{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+			}
+			client.reorg(112, "b")
+			client.currentBlock = 122
+
+			mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(processed, nil)
+			mockStore.EXPECT().RollbackBlocks(chain, contract.Hex(), uint64(110)).Return(nil)
+			expectProcessed(client, 122)
+			Expect(watcher.Poll()).Should(Succeed())
+			Expect(client.logRanges).Should(Equal([][2]uint64{{110, 122}}))
+		})
+
+		It("should not advance when the rollback fails", func() {
+			client := newFakeEthClient(120, "a")
+			watcher := newWatcher(client, 120)
+			processed := []model.ProcessedBlock{
+				{BlockNumber: 120, BlockHash: client.hashes[120].Hex()},
+				{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+			}
+			client.reorg(112, "b")
+
+			mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(processed, nil)
+			mockStore.EXPECT().RollbackBlocks(chain, contract.Hex(), uint64(110)).Return(fmt.Errorf("mock error"))
+			Expect(watcher.Poll()).ShouldNot(Succeed())
+			Expect(client.logRanges).Should(BeEmpty())
+		})
+
+		It("should wait for a lagging rpc to catch up", func() {
+			client := newFakeEthClient(100, "a")
+			watcher := newWatcher(client, 120)
+			Expect(watcher.Poll()).Should(Succeed())
+			Expect(client.logRanges).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("Ethereum L2 reorgs", func() {
+	var (
+		mockCtrl  *gomock.Controller
+		mockStore *mocks.MockStore
+
+		chain    = model.EthereumArbitrum
+		contract = common.HexToAddress("0x130Ff59B75a415d0bcCc2e996acAf27ce70fD5eF")
+		orderID  = common.HexToHash("0x01")
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockStore = mocks.NewMockStore(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	newWatcher := func(client ethereum.Client, startBlock uint64) *EthereumL2Watcher {
+		mockStore.EXPECT().GetBlockCursor(chain, contract.Hex()).Return(startBlock, nil)
+		watcher, err := NewEthereumL2WatcherWithClient(mockStore, chain, model.NetworkConfig{}, contract, 0, 1000, client, nil, zap.NewNop())
+		Expect(err).Should(BeNil())
+		return watcher
+	}
+	expectProcessed := func(client *fakeEthClient, block uint64) {
+		mockStore.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(Store) error) error {
+			return fn(mockStore)
+		})
+		mockStore.EXPECT().UpdateBlockCursor(chain, contract.Hex(), block).Return(nil)
+		mockStore.EXPECT().RecordBlockHash(chain, contract.Hex(), block, client.hashes[block].Hex()).Return(nil)
+		mockStore.EXPECT().GetActiveSwaps(chain).Return(nil, nil)
+	}
+	event := func(name string, block uint64) types.Log {
+		htlcABI, err := GardenHTLC.GardenHTLCMetaData.GetAbi()
+		Expect(err).Should(BeNil())
+		return types.Log{
+			Topics:      []common.Hash{htlcABI.Events[name].ID, orderID},
+			Data:        make([]byte, 96),
+			BlockNumber: block,
+			TxHash:      common.HexToHash(fmt.Sprintf("0x%x", block)),
+		}
+	}
+
+	It("should resume from the persisted block cursor", func() {
+		client := newFakeEthClient(120, "a")
+		watcher := newWatcher(client, 110)
+
+		mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return([]model.ProcessedBlock{
+			{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+		}, nil)
+		expectProcessed(client, 120)
+		Expect(watcher.Poll()).Should(Succeed())
+		Expect(client.logRanges).Should(Equal([][2]uint64{{110, 120}}))
+	})
+
+	It("should rollback the swaps from an earlier l1 block than the fork point", func() {
+		client := newFakeEthClient(120, "a")
+		watcher := newWatcher(client, 120)
+		processed := []model.ProcessedBlock{
+			{BlockNumber: 120, BlockHash: client.hashes[120].Hex()},
+			{BlockNumber: 115, BlockHash: client.hashes[115].Hex()},
+			{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+		}
+		client.reorg(112, "b")
+		client.currentBlock = 122
+
+		// block 110 settles in l1 block 27, the blocks are reprocessed from block 46 in l1 block 11
+		mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(processed, nil)
+		mockStore.EXPECT().RollbackL2Blocks(chain, contract.Hex(), uint64(46), uint64(11)).Return(nil)
+		expectProcessed(client, 122)
+		Expect(watcher.Poll()).Should(Succeed())
+		Expect(client.logRanges).Should(Equal([][2]uint64{{46, 122}}))
+	})
+
+	It("should not advance when the rollback fails", func() {
+		client := newFakeEthClient(120, "a")
+		watcher := newWatcher(client, 120)
+		processed := []model.ProcessedBlock{
+			{BlockNumber: 120, BlockHash: client.hashes[120].Hex()},
+			{BlockNumber: 110, BlockHash: client.hashes[110].Hex()},
+		}
+		client.reorg(112, "b")
+
+		mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(processed, nil)
+		mockStore.EXPECT().RollbackL2Blocks(chain, contract.Hex(), uint64(46), uint64(11)).Return(fmt.Errorf("mock error"))
+		Expect(watcher.Poll()).ShouldNot(Succeed())
+		Expect(client.logRanges).Should(BeEmpty())
+	})
+
+	It("should record the l1 blocks of redeems and refunds", func() {
+		client := newFakeEthClient(120, "a")
+		client.logs = []types.Log{event("Redeemed", 113), event("Refunded", 118)}
+		watcher := newWatcher(client, 110)
+
+		mockStore.EXPECT().GetProcessedBlocks(chain, contract.Hex(), ReorgDepth).Return(nil, nil)
+		mockStore.EXPECT().SwapByOCID(orderID.Hex()[2:]).Return(model.AtomicSwap{Status: model.Initiated, Amount: "1", Timelock: "100"}, nil).Times(2)
+		mockStore.EXPECT().UpdateSwap(gomock.Any()).DoAndReturn(func(swap *model.AtomicSwap) error {
+			Expect(swap.Status).Should(Equal(model.Redeemed))
+			Expect(swap.RedeemBlockNumber).Should(Equal(uint64(28)))
+			return nil
+		})
+		mockStore.EXPECT().UpdateSwap(gomock.Any()).DoAndReturn(func(swap *model.AtomicSwap) error {
+			Expect(swap.Status).Should(Equal(model.Refunded))
+			Expect(swap.RefundBlockNumber).Should(Equal(uint64(29)))
+			return nil
+		})
+		expectProcessed(client, 120)
+		Expect(watcher.Poll()).Should(Succeed())
+	})
+
+	It("should wait for a lagging rpc to catch up", func() {
+		client := newFakeEthClient(100, "a")
+		watcher := newWatcher(client, 120)
+		Expect(watcher.Poll()).Should(Succeed())
+		Expect(client.logRanges).Should(BeEmpty())
+	})
+})
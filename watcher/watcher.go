@@ -14,6 +14,8 @@ const OrderTimeout = 3 * time.Minute
 type Store interface {
 	// UpdateOrder updates and order status in the db
 	UpdateOrder(order *model.Order) error
+	// RecordPenalty records a penalty against the filler of an order it failed, once per order
+	RecordPenalty(order *model.Order, reason string) error
 	// GetActiveOrders fetches all orders which are active.
 	GetActiveOrders() ([]model.Order, error)
 
@@ -23,6 +25,21 @@ type Store interface {
 	UpdateSwap(swap *model.AtomicSwap) error
 	GetActiveSwaps(chain model.Chain) ([]model.AtomicSwap, error)
 	SwapByOCID(ocID string) (model.AtomicSwap, error)
+
+	// GetBlockCursor returns the last block processed for the given htlc contract
+	GetBlockCursor(chain model.Chain, contract string) (uint64, error)
+	// UpdateBlockCursor advances the last processed block for the given htlc contract
+	UpdateBlockCursor(chain model.Chain, contract string, blockNumber uint64) error
+	// RecordBlockHash stores the hash of the last block of a processed range for the given htlc contract
+	RecordBlockHash(chain model.Chain, contract string, blockNumber uint64, blockHash string) error
+	// GetProcessedBlocks returns the most recently processed blocks for the given htlc contract, newest first
+	GetProcessedBlocks(chain model.Chain, contract string, limit int) ([]model.ProcessedBlock, error)
+	// RollbackBlocks reverts the swap updates made after the given block and rewinds the block cursor to it
+	RollbackBlocks(chain model.Chain, contract string, blockNumber uint64) error
+	// RollbackL2Blocks reverts the swap updates of an L2 made after the given L1 block and rewinds the block cursor to the given L2 block
+	RollbackL2Blocks(chain model.Chain, contract string, blockNumber, l1BlockNumber uint64) error
+	// Transaction runs fn against a store bound to a single db transaction
+	Transaction(fn func(Store) error) error
 }
 
 // Watcher watches the blockchain and update order status accordingly.
@@ -86,7 +103,7 @@ func (w *watcher) RunWorker(ctx context.Context) {
 			logger := w.logger.With(zap.Uint("order id", order.ID))
 			order, hasUpdated := ProcessOrder(order, w.store, logger)
 			if hasUpdated {
-				if err := w.store.UpdateOrder(&order); err != nil {
+				if err := SaveOrder(order, w.store, logger); err != nil {
 					logger.Error("update order failed with", zap.Error(err))
 				}
 			}
@@ -95,6 +112,9 @@ func (w *watcher) RunWorker(ctx context.Context) {
 }
 
 func ProcessOrder(order model.Order, store Store, logger *zap.Logger) (model.Order, bool) {
+	if order.Partial {
+		return processPartialOrder(order, logger)
+	}
 	// copy secret from follower atomic swap
 	secretUpdated := false
 	if order.Secret != order.FollowerAtomicSwap.Secret {
@@ -104,7 +124,7 @@ func ProcessOrder(order model.Order, store Store, logger *zap.Logger) (model.Ord
 	}
 	// Special cases regardless of order status
 	switch {
-	case order.Status == model.Created && time.Since(order.CreatedAt) > OrderTimeout:
+	case order.Status == model.Created && order.IsExpired(time.Now(), OrderTimeout):
 		order.Status = model.Cancelled
 	// Redeem and refund happens at the same time which should not happen. Defensive check.
 	case (order.InitiatorAtomicSwap.Status == model.Redeemed && order.FollowerAtomicSwap.Status == model.Refunded) || (order.FollowerAtomicSwap.Status == model.Redeemed && order.InitiatorAtomicSwap.Status == model.Refunded):
@@ -115,7 +135,7 @@ func ProcessOrder(order model.Order, store Store, logger *zap.Logger) (model.Ord
 		logger.Error("atomic swap soft failed due to initiator refunding")
 		order.Status = model.FailedSoft
 	// Follower has not filled the swap before the order timeout
-	case (order.Status == model.Created && time.Since(order.CreatedAt) > OrderTimeout) || order.Status == model.Filled && time.Since(order.CreatedAt) > SwapInitiationTimeout && order.InitiatorAtomicSwap.Status == model.NotStarted:
+	case (order.Status == model.Created && order.IsExpired(time.Now(), OrderTimeout)) || order.Status == model.Filled && time.Since(filledAt(order)) > SwapInitiationTimeout && order.InitiatorAtomicSwap.Status == model.NotStarted:
 		//check if the user partially filled the order
 		if order.InitiatorAtomicSwap.FilledAmount != "" {
 			break
@@ -128,3 +148,50 @@ func ProcessOrder(order model.Order, store Store, logger *zap.Logger) (model.Ord
 	}
 	return order, (order.Status != model.Created && order.Status != model.Filled) || secretUpdated
 }
+
+// SaveOrder saves a processed order and records a penalty against its filler
+// when the order failed as the filler never initiated
+func SaveOrder(order model.Order, store Store, logger *zap.Logger) error {
+	return store.Transaction(func(store Store) error {
+		if err := store.UpdateOrder(&order); err != nil {
+			return err
+		}
+		if order.FillerDefaulted() {
+			logger.Info("recording penalty as the filler did not initiate", zap.String("filler", order.Taker))
+			return store.RecordPenalty(&order, model.PenaltyFillerDefaulted)
+		}
+		return nil
+	})
+}
+
+// a partially fillable order has no atomic swaps of its own, it is open for fills
+// until it is filled or expires and then completes with its child orders
+func processPartialOrder(order model.Order, logger *zap.Logger) (model.Order, bool) {
+	status := order.Status
+	if order.Status == model.Created && order.IsExpired(time.Now(), OrderTimeout) {
+		if len(order.Children) == 0 {
+			logger.Info("partially fillable order cancelled as it expired before it was filled")
+			order.Status = model.Cancelled
+			return order, true
+		}
+		// the parts filled before the order expired still complete
+		now := time.Now().UTC()
+		order.Status = model.Filled
+		order.FilledAt = &now
+	}
+	if order.Status == model.Filled {
+		if childStatus, done := model.RollupChildren(order.Children); done {
+			logger.Info("partially fillable order completed", zap.Uint("status", uint(childStatus)))
+			order.Status = childStatus
+		}
+	}
+	return order, order.Status != status
+}
+
+// orders filled before the fill time was recorded were filled shortly after creation
+func filledAt(order model.Order) time.Time {
+	if order.FilledAt != nil {
+		return *order.FilledAt
+	}
+	return order.CreatedAt
+}
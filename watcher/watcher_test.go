@@ -325,6 +325,9 @@ var _ = Describe("Watcher", func() {
 			}
 
 			mockStore.EXPECT().GetActiveOrders().Return([]model.Order{order}, nil).MaxTimes(2)
+			mockStore.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(Store) error) error {
+				return fn(mockStore)
+			})
 			mockStore.EXPECT().UpdateOrder(&updatedOrder).Return(nil)
 
 			ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
@@ -377,8 +380,12 @@ var _ = Describe("Watcher", func() {
 			}
 
 			mockStore.EXPECT().GetActiveOrders().Return([]model.Order{order}, nil).AnyTimes()
+			mockStore.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(Store) error) error {
+				return fn(mockStore)
+			})
 			mockStore.EXPECT().UpdateOrder(&updatedOrder).Return(mockError)
-			ctx, _ := context.WithTimeout(context.Background(), 7*time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), 7*time.Second)
+			defer cancel()
 			watcher.Run(ctx)
 		})
 
@@ -386,7 +393,8 @@ var _ = Describe("Watcher", func() {
 			watcher := NewWatcher(logger, mockStore, minWorkers)
 			Expect(watcher).ToNot(BeNil())
 			mockStore.EXPECT().GetActiveOrders().Return(nil, mockError).AnyTimes()
-			ctx, _ := context.WithTimeout(context.Background(), 8*time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			defer cancel()
 			watcher.Run(ctx)
 		})
 
@@ -403,7 +411,8 @@ var _ = Describe("Watcher", func() {
 			watcher := NewWatcher(logger, mockStore, 0)
 			Expect(watcher).ToNot(BeNil())
 			mockStore.EXPECT().GetActiveOrders().Return([]model.Order{order}, nil)
-			ctx, _ := context.WithTimeout(context.Background(), 8*time.Second)
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			defer cancel()
 			watcher.Run(ctx)
 		})
 	})